@@ -1,26 +1,49 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
-	"time"
-
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/gofiber/fiber/v2/middleware/requestid"
-	_ "github.com/raulaguila/go-pass/configs"
-	"github.com/raulaguila/go-pass/internal/api/middleware"
+
+	"github.com/raulaguila/go-pass/configs"
 	"github.com/raulaguila/go-pass/internal/infra/database"
 	"github.com/raulaguila/go-pass/internal/infra/handlers"
-	"github.com/raulaguila/go-pass/internal/pkg/i18n"
-	"github.com/raulaguila/go-pass/pkg/helpers"
-	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
 )
 
+var errUsage error = errors.New("invalid arguments")
+
+type command struct {
+	usage string
+	help  string
+	run   func(args []string) error
+}
+
+var commands map[string]*command
+
+func init() {
+	commands = map[string]*command{
+		"serve":   {usage: "serve [flags]", help: "Migrate and seed the database, then start the API (default)", run: serve},
+		"migrate": {usage: "migrate up|down [steps]|status [flags]", help: "Apply, revert or list the database migrations", run: migrateCommand},
+		"seed":    {usage: "seed [flags]", help: "Create the default profiles and the admin user when missing", run: seed},
+		"user":    {usage: "user create <mail> <name> <profile>|reset <mail>|disable <mail> [flags]", help: "Create, reset the password of or disable a user", run: userCommand},
+		"profile": {usage: "profile list [flags]", help: "List the profiles", run: profileCommand},
+		"keys":    {usage: "keys rotate access|refresh [RS256|ES256|EdDSA] [flags]", help: "Generate a new signing key, keeping the current ones for verification", run: keysCommand},
+		"config":  {usage: "config check [flags]", help: "Validate the configuration", run: configCommand},
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "usage: go-pass <command> [arguments] [flags]\n\ncommands:\n")
+	for _, name := range []string{"serve", "migrate", "seed", "user", "profile", "keys", "config"} {
+		fmt.Fprintf(w, "  %v\n      %v\n", commands[name].usage, commands[name].help)
+	}
+	fmt.Fprintf(w, "\nthe flags override the configuration, see: go-pass serve -h\n")
+}
+
 // @title 							Go - Template API
 // @description 					Template API.
 
@@ -34,62 +57,110 @@ import (
 // @name							Authorization
 // @description 					Type "Bearer" followed by a space and JWT token.
 func main() {
-	postgresdb, err := database.ConnectPostgresDB()
-	helpers.PanicIfErr(err)
-
-	app := fiber.New(fiber.Config{
-		EnablePrintRoutes:     false,
-		Prefork:               os.Getenv("SYS_PREFORK") == "true",
-		CaseSensitive:         true,
-		StrictRouting:         true,
-		DisableStartupMessage: false,
-		AppName:               "Go - Expense API",
-		ReduceMemoryUsage:     false,
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, err)
-		},
-	})
-
-	app.Use(
-		recover.New(),
-		middleware.GetRequestLanguage,
-		requestid.New(),
-	)
-
-	if strings.ToLower(os.Getenv("API_LOGGER")) == "true" {
-		app.Use(logger.New(logger.Config{
-			CustomTags: map[string]logger.LogFunc{
-				"xip": func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
-					return output.WriteString(fmt.Sprintf("%15s", c.IP()))
-				},
-				"fullpath": func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
-					return output.WriteString(string(c.Request().RequestURI()))
-				},
-			},
-			Format:     "[FIBER:${magenta}${pid}${reset}] ${time} | ${status} | ${latency} | ${xip} | ${method} ${fullpath} ${magenta}${error}${reset}\n",
-			TimeFormat: "2006-01-02 15:04:05",
-			TimeZone:   time.Local.String(),
-		}))
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the command named by the first argument, starting the API when it is omitted.
+func run(args []string) int {
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage(os.Stderr)
+		return 2
+	}
+
+	err := cmd.run(args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, "%v\nusage: go-pass %v\n", err.Error(), cmd.usage)
+		return 2
+	default:
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+}
+
+// positional splits the n leading arguments from the flags.
+func positional(args []string, n int) ([]string, []string, error) {
+	if len(args) < n {
+		return nil, nil, fmt.Errorf("%w: expected %v arguments", errUsage, n)
+	}
+	for _, arg := range args[:n] {
+		if strings.HasPrefix(arg, "-") {
+			return nil, nil, fmt.Errorf("%w: expected %v arguments", errUsage, n)
+		}
+	}
+
+	return args[:n], args[n:], nil
+}
+
+// action splits the action of a command, like the 'up' of 'migrate up', from its arguments.
+func action(args []string, actions ...string) (string, []string, error) {
+	values, rest, err := positional(args, 1)
+	if err != nil || !slices.Contains(actions, values[0]) {
+		return "", nil, fmt.Errorf("%w: expected %v", errUsage, strings.Join(actions, ", "))
+	}
+
+	return values[0], rest, nil
+}
+
+func loadConfig(args []string) (*configs.Config, error) {
+	cfg, err := configs.Load(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, cfg.Apply()
+}
+
+// openServices connects to the database, without migrating it, and creates the services.
+func openServices(args []string) (*configs.Config, *handlers.Services, error) {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	services, err := handlers.NewServices(database.Open(cfg), cfg)
+	return cfg, services, err
+}
+
+// configCommand validates the configuration without starting the API, like: go-pass config check -config go-pass.toml
+func configCommand(args []string) error {
+	_, args, err := action(args, "check")
+	if err != nil {
+		return err
+	}
+
+	if _, err := configs.Load(args); err != nil {
+		return err
+	}
+
+	fmt.Println("configuration ok")
+	return nil
+}
+
+func seed(args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	if err := database.Seed(database.Open(cfg), cfg.Admin); err != nil {
+		return err
 	}
 
-	app.Use(
-		cors.New(cors.Config{
-			AllowOrigins:     "*",
-			AllowMethods:     strings.Join([]string{fiber.MethodGet, fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete, fiber.MethodOptions}, ","),
-			AllowHeaders:     "*",
-			AllowCredentials: true,
-			ExposeHeaders:    "*",
-			MaxAge:           1,
-		}),
-		limiter.New(limiter.Config{
-			Max:        200,
-			Expiration: time.Minute,
-			LimitReached: func(c *fiber.Ctx) error {
-				messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
-				return httphelper.NewHTTPResponse(c, fiber.StatusTooManyRequests, messages.ErrManyRequest)
-			},
-		}),
-	)
-
-	handlers.HandleRequests(app, postgresdb)
+	fmt.Println("seeded")
+	return nil
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raulaguila/go-pass/configs"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+)
+
+// keysCommand prints the token keys with a new signing key, the previous keys verify the issued tokens until removed:
+//
+//	go-pass keys rotate access EdDSA
+func keysCommand(args []string) error {
+	_, args, err := action(args, "rotate")
+	if err != nil {
+		return err
+	}
+
+	values, args, err := positional(args, 1)
+	if err != nil || (values[0] != "access" && values[0] != "refresh") {
+		return fmt.Errorf("%w: expected access or refresh", errUsage)
+	}
+
+	algorithm := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		algorithm, args = args[0], args[1:]
+	}
+
+	cfg, err := configs.Load(args)
+	if err != nil {
+		return err
+	}
+
+	prefix, private, public := "ACCESS", cfg.Tokens.AccessPrivate, cfg.Tokens.AccessPublic
+	if values[0] == "refresh" {
+		prefix, private, public = "REFRESH", cfg.Tokens.RefreshPrivate, cfg.Tokens.RefreshPublic
+	}
+
+	current, err := keyring.FromBase64(private, public)
+	if err != nil {
+		return err
+	}
+	if algorithm == "" {
+		algorithm = current.Active().Algorithm
+	}
+
+	key, err := keyring.Generate(algorithm)
+	if err != nil {
+		return err
+	}
+
+	newPrivate, err := key.PrivateBase64()
+	if err != nil {
+		return err
+	}
+
+	publics := []string{}
+	for _, previous := range current.Keys() {
+		encoded, err := previous.PublicBase64()
+		if err != nil {
+			return err
+		}
+		publics = append(publics, encoded)
+	}
+
+	fmt.Printf("# New %v key %v, the previous keys can be removed after the %v token expiration\n", values[0], key.ID, values[0])
+	fmt.Printf("%v_TOKEN_PRIVATE='%v'\n", prefix, newPrivate)
+	fmt.Printf("%v_TOKEN_PUBLIC='%v'\n", prefix, strings.Join(publics, ","))
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raulaguila/go-pass/internal/infra/database"
+	"github.com/raulaguila/go-pass/pkg/migrate"
+)
+
+// migrateCommand applies, reverts or lists the migrations, like: go-pass migrate down 2 -config go-pass.toml
+func migrateCommand(args []string) error {
+	command, args, err := action(args, "up", "down", "status")
+	if err != nil {
+		return err
+	}
+
+	steps := 1
+	if command == "down" && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if steps, err = strconv.Atoi(args[0]); err != nil || steps < 1 {
+			return fmt.Errorf("%w: invalid steps %q", errUsage, args[0])
+		}
+		args = args[1:]
+	}
+
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := database.NewMigrator(database.Open(cfg))
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var migrations []migrate.Migration
+	switch command {
+	case "up":
+		migrations, err = migrator.Up(ctx)
+	case "down":
+		migrations, err = migrator.Down(ctx, steps)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Local().Format(time.DateTime)
+			}
+			fmt.Printf("%04d_%-30v %v\n", status.Version, status.Name, state)
+		}
+		return nil
+	}
+
+	for _, migration := range migrations {
+		fmt.Printf("%v %04d_%v\n", command, migration.Version, migration.Name)
+	}
+
+	return err
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/infra/database"
+	"github.com/raulaguila/go-pass/internal/infra/handlers"
+)
+
+// serve migrates and seeds the database, then starts the API until an interrupt or terminate signal, like: go-pass serve -api.port 9000
+func serve(args []string) error {
+	cfg, err := loadConfig(args)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	server, err := handlers.NewServer(db, cfg)
+	if err != nil {
+		return err
+	}
+
+	return server.Start(context.Background())
+}
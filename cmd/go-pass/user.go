@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/pkg/filter"
+)
+
+// userCommand manages a user like the user routes, the users without password receive an invitation:
+//
+//	go-pass user create john@email.com "John Cena" ROOT
+//	go-pass user reset john@email.com
+//	go-pass user disable john@email.com
+func userCommand(args []string) error {
+	command, args, err := action(args, "create", "reset", "disable")
+	if err != nil {
+		return err
+	}
+
+	count := 1
+	if command == "create" {
+		count = 3
+	}
+	values, args, err := positional(args, count)
+	if err != nil {
+		return err
+	}
+
+	cfg, services, err := openServices(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	translation := i18n.I18nTranslations[cfg.System.Language]
+
+	switch command {
+	case "create":
+		profile, err := services.Profile.GetProfileByName(ctx, values[2])
+		if err != nil {
+			return fmt.Errorf("profile %v: %w", values[2], err)
+		}
+
+		status := true
+		user, err := services.User.CreateUser(ctx, &dto.UserInputDTO{Name: &values[1], Email: &values[0], Status: &status, ProfileID: &profile.Id})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("user %v created\n", user.Id)
+
+		return invite(ctx, services.UserToken, user, translation)
+	case "reset":
+		user, err := services.User.GetUserByMail(ctx, values[0])
+		if err != nil {
+			return err
+		}
+
+		if !user.New {
+			if err := services.User.ResetUser(ctx, user); err != nil {
+				return err
+			}
+			if user, err = services.User.GetUserByID(ctx, user.Id); err != nil {
+				return err
+			}
+		}
+
+		return invite(ctx, services.UserToken, user, translation)
+	default:
+		user, err := services.User.GetUserByMail(ctx, values[0])
+		if err != nil {
+			return err
+		}
+
+		status := false
+		if err := services.User.UpdateUser(ctx, user, &dto.UserInputDTO{Status: &status}); err != nil {
+			return err
+		}
+
+		fmt.Printf("user %v disabled\n", user.Id)
+		return nil
+	}
+}
+
+func invite(ctx context.Context, userTokenService domain.UserTokenService, user *domain.User, translation *i18n.Translation) error {
+	if err := userTokenService.InviteUser(ctx, user, translation); err != nil {
+		return err
+	}
+
+	fmt.Printf("invitation sent to %v\n", user.Email)
+	return nil
+}
+
+// profileCommand lists the profiles, like: go-pass profile list
+func profileCommand(args []string) error {
+	_, args, err := action(args, "list")
+	if err != nil {
+		return err
+	}
+
+	_, services, err := openServices(args)
+	if err != nil {
+		return err
+	}
+
+	response, err := services.Profile.GetProfilesOutputDTO(context.Background(), filter.NewFilter("name", "asc"))
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range *response.Items.(*[]domain.Profile) {
+		fmt.Printf("%-6v %-30v user=%v profile=%v\n", profile.Id, profile.Name, profile.Permissions.UserModule, profile.Permissions.ProfileModule)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const clipboardSumEnv string = "GOPASS_CLIPBOARD_SUM"
+
+var errNoClipboard error = errors.New("no clipboard available, install wl-clipboard, xclip or xsel")
+
+type clipboard struct {
+	copy  []string
+	paste []string
+}
+
+// findClipboard selects the clipboard commands of the system.
+func findClipboard() (*clipboard, error) {
+	candidates := []clipboard{}
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = append(candidates, clipboard{copy: []string{"pbcopy"}, paste: []string{"pbpaste"}})
+	case "windows":
+		candidates = append(candidates, clipboard{copy: []string{"clip"}, paste: []string{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}})
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, clipboard{copy: []string{"wl-copy"}, paste: []string{"wl-paste", "--no-newline"}})
+		}
+		candidates = append(candidates,
+			clipboard{copy: []string{"xclip", "-selection", "clipboard"}, paste: []string{"xclip", "-selection", "clipboard", "-o"}},
+			clipboard{copy: []string{"xsel", "--clipboard", "--input"}, paste: []string{"xsel", "--clipboard", "--output"}},
+		)
+	}
+
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate.copy[0]); err == nil {
+			return &candidate, nil
+		}
+	}
+
+	return nil, errNoClipboard
+}
+
+func (c *clipboard) write(text string) error {
+	cmd := exec.Command(c.copy[0], c.copy[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+func (c *clipboard) read() (string, error) {
+	out, err := exec.Command(c.paste[0], c.paste[1:]...).Output()
+	return strings.TrimRight(string(out), "\r\n"), err
+}
+
+func checksum(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// copyToClipboard copies the secret and starts a detached process clearing it after the delay, when it was not replaced.
+func copyToClipboard(secret string, clear time.Duration) error {
+	board, err := findClipboard()
+	if err != nil {
+		return err
+	}
+
+	if err := board.write(secret); err != nil {
+		return err
+	}
+	if clear <= 0 {
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(executable, "clipboard-clear", clear.String())
+	cmd.Env = append(os.Environ(), clipboardSumEnv+"="+checksum(secret))
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	return cmd.Process.Release()
+}
+
+// clearClipboard is the detached process of copyToClipboard, only the checksum of the secret is known to it.
+func clearClipboard(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected the delay", errUsage)
+	}
+
+	delay, err := time.ParseDuration(args[0])
+	if err != nil {
+		return err
+	}
+
+	signal.Ignore(syscall.SIGHUP, syscall.SIGINT)
+	time.Sleep(delay)
+
+	board, err := findClipboard()
+	if err != nil {
+		return err
+	}
+
+	current, err := board.read()
+	if err != nil {
+		return err
+	}
+	if checksum(current) != os.Getenv(clipboardSumEnv) {
+		return nil
+	}
+
+	return board.write("")
+}
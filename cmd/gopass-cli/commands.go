@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/raulaguila/go-pass/pkg/client"
+	"github.com/raulaguila/go-pass/pkg/passgen"
+	"golang.org/x/term"
+)
+
+// prompt reads a line from the terminal, without echo for the secrets, or from the piped input.
+func prompt(label string, secret bool) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if line == "" && err != nil {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, label)
+	if secret {
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(value), err
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(line), err
+}
+
+func login(args []string) error {
+	current, err := loadSession()
+	if err != nil {
+		return err
+	}
+
+	server := current.Server
+	if env := os.Getenv(serverEnv); env != "" {
+		server = env
+	}
+	if server == "" {
+		server = "http://localhost:9000"
+	}
+
+	flags := flag.NewFlagSet("login", flag.ContinueOnError)
+	flags.StringVar(&server, "server", server, "API URL")
+	email := flags.String("email", "", "User email, prompted when empty")
+	expire := flags.Bool("expire", true, "Request expiring tokens, -expire=false caches tokens without expiration")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		if *email, err = prompt("Email: ", false); err != nil {
+			return err
+		}
+	}
+
+	password, err := prompt("Password: ", true)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeout()
+	defer cancel()
+	response, err := client.New(server, client.WithLanguage(os.Getenv(langEnv))).Login(ctx, *email, password, *expire)
+	if err != nil {
+		return err
+	}
+
+	current.Server, current.Tokens = server, response.Tokens
+	if err := current.save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("logged in as %v\n", response.User.Email)
+	if response.User.ChangePassword {
+		fmt.Println("the password must be changed before using the API")
+	}
+	return nil
+}
+
+func logout(args []string) error {
+	if _, err := parse(flag.NewFlagSet("logout", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+
+	return removeSession()
+}
+
+func whoami(args []string) error {
+	flags := flag.NewFlagSet("whoami", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "Print JSON")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	api, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeout()
+	defer cancel()
+	user, err := api.Me(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(user)
+	}
+
+	profile := ""
+	if user.Profile != nil {
+		profile = user.Profile.Name
+	}
+	fmt.Printf("%v <%v> %v\n", user.Name, user.Email, profile)
+	return nil
+}
+
+func printAccounts(accounts []client.Account) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tSITE\tUSERNAME\tPHONE")
+	for _, account := range accounts {
+		site, phone := "", ""
+		if account.Site != nil {
+			site = account.Site.Name
+		}
+		if account.Phone != nil {
+			phone = account.Phone.Number
+		}
+		fmt.Fprintf(writer, "%v\t%v\t%v\t%v\n", account.ID, site, account.Username, phone)
+	}
+	writer.Flush()
+}
+
+func list(args []string) error {
+	flags := flag.NewFlagSet("ls", flag.ContinueOnError)
+	filter := &client.AccountFilter{}
+	flags.StringVar(&filter.Search, "search", "", "Search the username and the site")
+	site := flags.Uint("site", 0, "Only the accounts of the site")
+	flags.IntVar(&filter.Page, "page", 0, "Page, with the limit")
+	flags.IntVar(&filter.Limit, "limit", 0, "Accounts per page")
+	asJSON := flags.Bool("json", false, "Print JSON")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	if *site != 0 {
+		filter.SiteIDs = []uint{*site}
+	}
+
+	api, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeout()
+	defer cancel()
+	accounts, err := api.Accounts(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(accounts)
+	}
+
+	printAccounts(accounts.Items)
+	return nil
+}
+
+func show(args []string) error {
+	flags := flag.NewFlagSet("show", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "Print JSON")
+	values, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(values[0])
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeout()
+	defer cancel()
+	account, err := api.Account(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(account)
+	}
+
+	printAccounts([]client.Account{*account})
+	return nil
+}
+
+// output prints the password, or copies it to the clipboard cleared after the delay.
+func output(password string, clip bool, clear time.Duration, asJSON bool, value interface{}) error {
+	if clip {
+		if err := copyToClipboard(password, clear); err != nil {
+			return err
+		}
+		if clear > 0 {
+			fmt.Fprintf(os.Stderr, "copied to the clipboard, cleared in %v\n", clear)
+		} else {
+			fmt.Fprintln(os.Stderr, "copied to the clipboard")
+		}
+		return nil
+	}
+
+	if asJSON {
+		return printJSON(value)
+	}
+
+	fmt.Println(password)
+	return nil
+}
+
+func reveal(args []string) error {
+	flags := flag.NewFlagSet("reveal", flag.ContinueOnError)
+	clip := flags.Bool("clip", false, "Copy to the clipboard instead of printing")
+	clear := flags.Duration("clear", 45*time.Second, "Clear the clipboard after the delay, 0 keeps it")
+	asJSON := flags.Bool("json", false, "Print JSON")
+	values, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	id, err := parseID(values[0])
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeout()
+	defer cancel()
+	password, err := api.AccountPassword(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return output(password, *clip, *clear, *asJSON, map[string]interface{}{"id": id, "password": password})
+}
+
+func add(args []string) error {
+	flags := flag.NewFlagSet("add", flag.ContinueOnError)
+	site := flags.Uint("site", 0, "Site ID, see: gopass-cli sites")
+	phone := flags.Uint("phone", 0, "Phone ID, see: gopass-cli phones")
+	username := flags.String("username", "", "Account username")
+	mail := flags.Uint("mail", 0, "ID of the email account of the account")
+	length := flags.Int("length", passgen.DefaultLength, "Generated password length")
+	symbols := flags.Bool("symbols", true, "Use symbols in the generated password")
+	ask := flags.Bool("prompt", false, "Prompt the password instead of generating it")
+	clip := flags.Bool("clip", false, "Copy the password to the clipboard instead of printing")
+	clear := flags.Duration("clear", 45*time.Second, "Clear the clipboard after the delay, 0 keeps it")
+	asJSON := flags.Bool("json", false, "Print JSON")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	if *site == 0 || *phone == 0 || *username == "" {
+		return fmt.Errorf("%w: -site, -phone and -username are required", errUsage)
+	}
+
+	var password string
+	var err error
+	if *ask {
+		password, err = prompt("Password: ", true)
+	} else {
+		password, err = passgen.Generate(*length, *symbols)
+	}
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	in := &client.AccountInput{Username: username, Password: &password, SiteID: site, PhoneID: phone}
+	if *mail != 0 {
+		in.MailID = mail
+	}
+
+	ctx, cancel := timeout()
+	defer cancel()
+	account, err := api.CreateAccount(ctx, in)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "account %v created\n", account.ID)
+	if *ask {
+		if *asJSON {
+			return printJSON(account)
+		}
+		return nil
+	}
+
+	return output(password, *clip, *clear, *asJSON, map[string]interface{}{"account": account, "password": password})
+}
+
+func sites(args []string) error {
+	flags := flag.NewFlagSet("sites", flag.ContinueOnError)
+	filter := &client.Filter{Sort: "name", Order: "asc"}
+	flags.StringVar(&filter.Search, "search", "", "Search the name and the URL")
+	asJSON := flags.Bool("json", false, "Print JSON")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	api, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeout()
+	defer cancel()
+	response, err := api.Sites(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(response)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tNAME\tURL")
+	for _, site := range response.Items {
+		fmt.Fprintf(writer, "%v\t%v\t%v\n", site.ID, site.Name, site.URL)
+	}
+	return writer.Flush()
+}
+
+func phones(args []string) error {
+	flags := flag.NewFlagSet("phones", flag.ContinueOnError)
+	filter := &client.Filter{Sort: "number", Order: "asc"}
+	flags.StringVar(&filter.Search, "search", "", "Search the number")
+	asJSON := flags.Bool("json", false, "Print JSON")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	api, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeout()
+	defer cancel()
+	response, err := api.Phones(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(response)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tNUMBER\tOPERATOR")
+	for _, phone := range response.Items {
+		operator := ""
+		if phone.Operator != nil {
+			operator = phone.Operator.Name
+		}
+		fmt.Fprintf(writer, "%v\t%v\t%v\n", phone.ID, phone.Number, operator)
+	}
+	return writer.Flush()
+}
+
+func gen(args []string) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	length := flags.Int("length", passgen.DefaultLength, "Password length")
+	symbols := flags.Bool("symbols", true, "Use symbols")
+	clip := flags.Bool("clip", false, "Copy to the clipboard instead of printing")
+	clear := flags.Duration("clear", 45*time.Second, "Clear the clipboard after the delay, 0 keeps it")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	password, err := passgen.Generate(*length, *symbols)
+	if err != nil {
+		return err
+	}
+
+	return output(password, *clip, *clear, false, nil)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raulaguila/go-pass/pkg/client"
+)
+
+// Environment variables overriding the cached session, the token may be a personal token for scripting.
+const (
+	serverEnv string = "GOPASS_SERVER"
+	tokenEnv  string = "GOPASS_TOKEN"
+	langEnv   string = "GOPASS_LANG"
+)
+
+var errUsage error = errors.New("invalid arguments")
+
+type command struct {
+	usage string
+	help  string
+	run   func(args []string) error
+}
+
+var commands map[string]*command
+
+var order = []string{"login", "logout", "whoami", "ls", "show", "reveal", "add", "sites", "phones", "gen"}
+
+func init() {
+	commands = map[string]*command{
+		"login":  {usage: "login [-server URL] [-email MAIL] [-expire]", help: "Log in and cache the session", run: login},
+		"logout": {usage: "logout", help: "Remove the cached session", run: logout},
+		"whoami": {usage: "whoami [-json]", help: "Show the logged in user", run: whoami},
+		"ls":     {usage: "ls [-search TEXT] [-site ID] [-page N] [-limit N] [-json]", help: "List and search the accounts", run: list},
+		"show":   {usage: "show <id> [-json]", help: "Show an account", run: show},
+		"reveal": {usage: "reveal <id> [-clip] [-clear 45s] [-json]", help: "Print the password of an account, or copy it to the clipboard", run: reveal},
+		"add":    {usage: "add -site ID -phone ID -username NAME [-mail ID] [-length 20] [-symbols=false] [-prompt] [-clip] [-json]", help: "Create an account with a generated password", run: add},
+		"sites":  {usage: "sites [-search TEXT] [-json]", help: "List the sites", run: sites},
+		"phones": {usage: "phones [-search TEXT] [-json]", help: "List the phones", run: phones},
+		"gen":    {usage: "gen [-length 20] [-symbols=false] [-clip]", help: "Generate a password without storing it", run: gen},
+
+		"clipboard-clear": {run: clearClipboard},
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "usage: gopass-cli <command> [arguments]\n\ncommands:\n")
+	for _, name := range order {
+		fmt.Fprintf(w, "  %v\n      %v\n", commands[name].usage, commands[name].help)
+	}
+	fmt.Fprintf(w, "\nenvironment:\n  %v  server URL\n  %v   access or personal token, instead of the session\n  %v    language of the error messages\n", serverEnv, tokenEnv, langEnv)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 || slices.Contains([]string{"help", "-h", "-help", "--help"}, args[0]) {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		printUsage(os.Stderr)
+		return 2
+	}
+
+	err := cmd.run(args[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, "%v\nusage: gopass-cli %v\n", err.Error(), cmd.usage)
+		return 2
+	case errors.Is(err, client.ErrUnauthorized), errors.Is(err, client.ErrNoSession):
+		fmt.Fprintf(os.Stderr, "%v\nrun: gopass-cli login\n", err.Error())
+		return 1
+	default:
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+}
+
+// parse parses the flags around the n leading positional arguments, like 'reveal 12 -clip'.
+func parse(flags *flag.FlagSet, args []string, n int) ([]string, error) {
+	flags.SetOutput(io.Discard)
+	positional := []string{}
+	for len(positional) < n && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		positional, args = append(positional, args[0]), args[1:]
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			flags.SetOutput(os.Stdout)
+			flags.PrintDefaults()
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", errUsage, err.Error())
+	}
+
+	positional = append(positional, flags.Args()...)
+	if len(positional) != n {
+		return nil, fmt.Errorf("%w: expected %v argument(s)", errUsage, n)
+	}
+
+	return positional, nil
+}
+
+func parseID(value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: invalid id %q", errUsage, value)
+	}
+
+	return uint(id), nil
+}
+
+// newClient resumes the cached session, saving the refreshed tokens, the environment overrides it.
+func newClient() (*client.Client, error) {
+	current, err := loadSession()
+	if err != nil {
+		return nil, err
+	}
+
+	if server := os.Getenv(serverEnv); server != "" {
+		current.Server = server
+	}
+	if current.Server == "" {
+		return nil, client.ErrNoSession
+	}
+
+	options := []client.Option{client.WithLanguage(os.Getenv(langEnv))}
+	if token := os.Getenv(tokenEnv); token != "" {
+		options = append(options, client.WithTokens(client.Tokens{AccessToken: token}))
+	} else {
+		options = append(options, client.WithTokens(current.Tokens), client.WithTokensHandler(func(tokens client.Tokens) {
+			current.Tokens = tokens
+			if err := current.save(); err != nil {
+				fmt.Fprintf(os.Stderr, "could not save the session: %v\n", err.Error())
+			}
+		}))
+	}
+
+	return client.New(current.Server, options...), nil
+}
+
+func printJSON(value interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func timeout() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), time.Minute)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/raulaguila/go-pass/pkg/client"
+)
+
+// session is cached in the user config directory, readable only by the user.
+type session struct {
+	Server string `json:"server"`
+	client.Tokens
+}
+
+func sessionPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "go-pass", "session.json"), nil
+}
+
+func loadSession() (*session, error) {
+	path, err := sessionPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &session{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	current := &session{}
+	return current, json.Unmarshal(data, current)
+}
+
+func (s *session) save() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// The permissions of an existing file are not changed by the open.
+	if err := file.Chmod(0o600); err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	return err
+}
+
+func removeSession() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -2,16 +2,21 @@ package configs
 
 import (
 	"embed"
+	"errors"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path"
 	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
-	"github.com/joho/godotenv"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	myi18n "github.com/raulaguila/go-pass/internal/pkg/i18n"
-	"github.com/raulaguila/go-pass/pkg/helpers"
+	"github.com/raulaguila/go-pass/pkg/conf"
+	"github.com/raulaguila/go-pass/pkg/hasher"
+	"github.com/raulaguila/go-pass/pkg/logging"
+	"github.com/raulaguila/go-pass/pkg/validator"
 	"golang.org/x/text/language"
 )
 
@@ -21,28 +26,255 @@ var viewsfs embed.FS
 //go:embed version.txt
 var version string
 
-func init() {
-	err := godotenv.Load(path.Join("configs", ".env"))
-	helpers.PanicIfErr(err)
+// Development environment file, created by configs/env.sh.
+var EnvFile string = path.Join("configs", ".env")
 
-	os.Setenv("SYS_VERSION", version)
+type (
+	// Config is loaded once by Load and passed to the constructors, see the env.sh for the meaning of each option.
+	Config struct {
+		Version  string
+		System   SystemConfig   `key:"system"`
+		Log      LogConfig      `key:"log"`
+		API      APIConfig      `key:"api"`
+		Metrics  MetricsConfig  `key:"metrics"`
+		Tracing  TracingConfig  `key:"tracing"`
+		Tokens   TokensConfig   `key:"tokens"`
+		Auth     AuthConfig     `key:"auth"`
+		Password PasswordConfig `key:"password"`
+		OIDC     OIDCConfig     `key:"oidc"`
+		LDAP     LDAPConfig     `key:"ldap"`
+		Mail     MailConfig     `key:"mail"`
+		Database DatabaseConfig `key:"database"`
+		Postgres PostgresConfig `key:"postgres"`
+		SQLite   SQLiteConfig   `key:"sqlite"`
+		Admin    AdminConfig    `key:"admin"`
+	}
+
+	SystemConfig struct {
+		TimeZone  string   `key:"time_zone" env:"TZ" default:"UTC"`
+		Language  string   `key:"language" env:"SYS_LANGUAGE" default:"en"`
+		Languages []string `key:"languages" env:"SYS_LANGUAGES" default:"en,pt"`
+		Prefork   bool     `key:"prefork" env:"SYS_PREFORK" default:"false"`
+
+		// Translations is a directory of active.<language>.toml bundles, loaded over the embedded ones, to add or adjust
+		// languages without recompiling.
+		Translations string `key:"translations" env:"SYS_TRANSLATIONS"`
+	}
+
+	// The records are written to the standard output, in JSON for the log collectors or text for the terminal.
+	LogConfig struct {
+		Level  string `key:"level" env:"LOG_LEVEL" default:"info"`
+		Format string `key:"format" env:"LOG_FORMAT" default:"json"`
+	}
+
+	APIConfig struct {
+		Port            string        `key:"port" env:"API_PORT" default:"9000"`
+		Logger          bool          `key:"logger" env:"API_LOGGER" default:"true"`
+		Swagger         bool          `key:"swagger" env:"API_SWAGGO" default:"false"`
+		DefaultSort     string        `key:"default_sort" env:"API_DEFAULT_SORT" default:"updated_at"`
+		DefaultOrder    string        `key:"default_order" env:"API_DEFAULT_ORDER" default:"desc"`
+		AppURL          string        `key:"app_url" env:"APP_URL" default:"http://localhost:3000"`
+		RateLimit       int           `key:"rate_limit" env:"API_RATE_LIMIT" default:"200"`
+		ShutdownTimeout time.Duration `key:"shutdown_timeout" env:"API_SHUTDOWN_TIMEOUT" default:"30" unit:"s"`
+	}
+
+	// The metrics are served on the API port, or on their own address to keep them off the public network. With prefork,
+	// each child counts its own requests, so the API port serves the metrics of the child answering the scrape.
+	MetricsConfig struct {
+		Enabled bool   `key:"enabled" env:"METRICS_ENABLED" default:"false"`
+		Token   string `key:"token" env:"METRICS_TOKEN"`
+		Address string `key:"address" env:"METRICS_ADDRESS"`
+	}
+
+	// The spans are exported with OTLP over HTTP to a collector, or written to the standard output for local debugging.
+	TracingConfig struct {
+		Exporter string `key:"exporter" env:"TRACING_EXPORTER" default:"none"`
+		Endpoint string `key:"endpoint" env:"TRACING_ENDPOINT"`
+		Insecure bool   `key:"insecure" env:"TRACING_INSECURE" default:"false"`
+	}
+
+	// The misspelled RFRESH and PRIVAT names are still accepted.
+	TokensConfig struct {
+		AccessExpire   time.Duration `key:"access_expire" env:"ACCESS_TOKEN_EXPIRE" default:"120" unit:"m"`
+		AccessPrivate  string        `key:"access_private" env:"ACCESS_TOKEN_PRIVATE,ACCESS_TOKEN_PRIVAT"`
+		AccessPublic   string        `key:"access_public" env:"ACCESS_TOKEN_PUBLIC"`
+		RefreshExpire  time.Duration `key:"refresh_expire" env:"REFRESH_TOKEN_EXPIRE,RFRESH_TOKEN_EXPIRE" default:"360" unit:"m"`
+		RefreshPrivate string        `key:"refresh_private" env:"REFRESH_TOKEN_PRIVATE,RFRESH_TOKEN_PRIVAT"`
+		RefreshPublic  string        `key:"refresh_public" env:"REFRESH_TOKEN_PUBLIC,RFRESH_TOKEN_PUBLIC"`
+		InviteExpire   time.Duration `key:"invite_expire" env:"INVITE_TOKEN_EXPIRE" default:"4320" unit:"m"`
+		ResetExpire    time.Duration `key:"reset_expire" env:"RESET_TOKEN_EXPIRE" default:"30" unit:"m"`
+	}
+
+	// The failed logins lock the email from the IP after MaxAttempts, and the IP alone after IPMaxAttempts. The
+	// attempts are counted in memory by each process, so with prefork every child allows its own attempts.
+	AuthConfig struct {
+		MaxAttempts    int           `key:"max_attempts" env:"AUTH_MAX_ATTEMPTS" default:"5"`
+		IPMaxAttempts  int           `key:"ip_max_attempts" env:"AUTH_IP_MAX_ATTEMPTS" default:"100"`
+		BackoffDelay   time.Duration `key:"backoff_delay" env:"AUTH_BACKOFF_DELAY" default:"1" unit:"s"`
+		LockoutTime    time.Duration `key:"lockout_time" env:"AUTH_LOCKOUT_TIME" default:"15" unit:"m"`
+		LockoutWebhook string        `key:"lockout_webhook" env:"AUTH_LOCKOUT_WEBHOOK"`
+	}
 
-	time.Local, err = time.LoadLocation(os.Getenv("TZ"))
-	helpers.PanicIfErr(err)
-	helpers.PanicIfErr(loadMessages())
+	PasswordConfig struct {
+		MinLength         int    `key:"min_length" env:"PASSWORD_MIN_LENGTH" default:"10"`
+		MinClasses        int    `key:"min_classes" env:"PASSWORD_MIN_CLASSES" default:"1"`
+		History           int    `key:"history" env:"PASSWORD_HISTORY" default:"5"`
+		CheckCommon       bool   `key:"check_common" env:"PASSWORD_CHECK_COMMON" default:"true"`
+		Hasher            string `key:"hasher" env:"PASSWORD_HASHER" default:"argon2id"`
+		Argon2Memory      int    `key:"argon2_memory" env:"ARGON2_MEMORY" default:"65536"`
+		Argon2Iterations  int    `key:"argon2_iterations" env:"ARGON2_ITERATIONS" default:"3"`
+		Argon2Parallelism int    `key:"argon2_parallelism" env:"ARGON2_PARALLELISM" default:"4"`
+		BcryptCost        int    `key:"bcrypt_cost" env:"BCRYPT_COST" default:"10"`
+	}
+
+	OIDCConfig struct {
+		Issuer           string   `key:"issuer" env:"OIDC_ISSUER"`
+		ClientID         string   `key:"client_id" env:"OIDC_CLIENT_ID"`
+		ClientSecret     string   `key:"client_secret" env:"OIDC_CLIENT_SECRET"`
+		RedirectURL      string   `key:"redirect_url" env:"OIDC_REDIRECT_URL"`
+		Scopes           []string `key:"scopes" env:"OIDC_SCOPES" default:"openid,email,profile"`
+		ProvisionProfile string   `key:"provision_profile" env:"OIDC_PROVISION_PROFILE"`
+		CookieKey        string   `key:"cookie_key" env:"OIDC_COOKIE_KEY"`
+	}
+
+	LDAPConfig struct {
+		URL           string        `key:"url" env:"LDAP_URL"`
+		StartTLS      bool          `key:"start_tls" env:"LDAP_START_TLS" default:"false"`
+		BindDN        string        `key:"bind_dn" env:"LDAP_BIND_DN"`
+		BindPassword  string        `key:"bind_password" env:"LDAP_BIND_PASS"`
+		UserBaseDN    string        `key:"user_base_dn" env:"LDAP_USER_BASE_DN"`
+		UserFilter    string        `key:"user_filter" env:"LDAP_USER_FILTER" default:"(mail=%s)"`
+		MailAttr      string        `key:"mail_attr" env:"LDAP_MAIL_ATTR" default:"mail"`
+		NameAttr      string        `key:"name_attr" env:"LDAP_NAME_ATTR" default:"cn"`
+		GroupBaseDN   string        `key:"group_base_dn" env:"LDAP_GROUP_BASE_DN"`
+		GroupFilter   string        `key:"group_filter" env:"LDAP_GROUP_FILTER" default:"(member=%s)"`
+		GroupProfiles string        `key:"group_profiles" env:"LDAP_GROUP_PROFILES"`
+		SyncInterval  time.Duration `key:"sync_interval" env:"LDAP_SYNC_INTERVAL" default:"60" unit:"m"`
+	}
+
+	MailConfig struct {
+		Driver       string `key:"driver" env:"MAIL_DRIVER" default:"log"`
+		From         string `key:"from" env:"MAIL_FROM" default:"go-pass@localhost"`
+		File         string `key:"file" env:"MAIL_FILE" default:"mail.log"`
+		SMTPHost     string `key:"smtp_host" env:"SMTP_HOST" default:"localhost"`
+		SMTPPort     string `key:"smtp_port" env:"SMTP_PORT" default:"587"`
+		SMTPUser     string `key:"smtp_user" env:"SMTP_USER"`
+		SMTPPassword string `key:"smtp_password" env:"SMTP_PASS"`
+		SMTPTLS      bool   `key:"smtp_tls" env:"SMTP_TLS" default:"false"`
+	}
+
+	// The sqlite driver keeps the data in a single file, for small teams and tests.
+	DatabaseConfig struct {
+		Driver string `key:"driver" env:"DB_DRIVER" default:"postgres"`
+	}
+
+	PostgresConfig struct {
+		Host     string `key:"host" env:"POSTGRES_HOST" default:"localhost"`
+		Port     string `key:"port" env:"POSTGRES_PORT" default:"5432"`
+		User     string `key:"user" env:"POSTGRES_USER"`
+		Password string `key:"password" env:"POSTGRES_PASS"`
+		Base     string `key:"base" env:"POSTGRES_BASE" default:"gopassdb"`
+	}
+
+	SQLiteConfig struct {
+		Path string `key:"path" env:"SQLITE_PATH" default:"gopass.db"`
+	}
+
+	AdminConfig struct {
+		Name     string `key:"name" env:"ADM_NAME" default:"Administrator"`
+		Mail     string `key:"mail" env:"ADM_MAIL"`
+		Password string `key:"password" env:"ADM_PASS"`
+	}
+)
+
+// Load reads the configuration from the defaults, the file given by -config or CONFIG_FILE,
+// the environment (with the development .env) and the flags, then validates it.
+func Load(args []string) (*Config, error) {
+	cfg := &Config{Version: strings.TrimSpace(version)}
+	if err := conf.Load(cfg, "go-pass", EnvFile, args); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Apply sets the process wide settings: time zone, translations and password hasher.
+func (c *Config) Apply() error {
+	location, err := time.LoadLocation(c.System.TimeZone)
+	if err != nil {
+		return err
+	}
+	time.Local = location
+
+	if err := loadMessages(c.System.Translations, c.System.Languages); err != nil {
+		return err
+	}
+
+	logger, err := logging.New(os.Stdout, c.Log.Level, c.Log.Format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+
+	hasher.SetDefault(c.Password.NewHasher())
+	return nil
 }
 
-func loadMessages() error {
+// NewHasher creates the hasher of the new passwords.
+func (c PasswordConfig) NewHasher() hasher.Hasher {
+	if strings.ToLower(c.Hasher) == "bcrypt" {
+		return hasher.NewBcrypt(c.BcryptCost)
+	}
+
+	params := hasher.DefaultArgon2idParams
+	params.Memory = uint32(c.Argon2Memory)
+	params.Iterations = uint32(c.Argon2Iterations)
+	params.Parallelism = uint8(c.Argon2Parallelism)
+	return hasher.NewArgon2id(params)
+}
+
+func loadMessages(dir string, languages []string) error {
 	bundle := i18n.NewBundle(language.English)
 	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
 
-	for _, lang := range strings.Split(os.Getenv("SYS_LANGUAGES"), ",") {
-		if _, err := bundle.LoadMessageFileFS(viewsfs, path.Join("i18n", "active."+lang+".toml")); err != nil {
+	for _, lang := range languages {
+		if _, err := bundle.LoadMessageFileFS(viewsfs, path.Join("i18n", translationFile(lang))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if dir != "" {
+			if _, err := bundle.LoadMessageFileFS(os.DirFS(dir), translationFile(lang)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+
+		translation := myi18n.NewTranslation(lang, i18n.NewLocalizer(bundle, lang))
+		if err := validator.StructValidator.RegisterTranslation(lang, translation.ValidationMessages()); err != nil {
 			return err
 		}
 
-		myi18n.I18nTranslations[lang] = myi18n.NewTranslation(i18n.NewLocalizer(bundle, lang))
+		myi18n.I18nTranslations[lang] = translation
 	}
 
 	return nil
 }
+
+func translationFile(lang string) string {
+	return "active." + lang + ".toml"
+}
+
+// hasTranslation reports whether the language has an embedded bundle or one in the directory of translations.
+func hasTranslation(dir string, lang string) bool {
+	if _, err := fs.Stat(viewsfs, path.Join("i18n", translationFile(lang))); err == nil {
+		return true
+	}
+	if dir == "" {
+		return false
+	}
+
+	_, err := fs.Stat(os.DirFS(dir), translationFile(lang))
+	return err == nil
+}
@@ -11,24 +11,93 @@ for ((i = 0; i < 2; i++)); do
     rm pvt.pem pub.pem
 done
 
+# Every option can also be set in a TOML or YAML file given by -config or CONFIG_FILE, with the section and key
+# shown by 'go-pass -h', like [api] port = 9000, and overridden by flags like -api.port=9000.
 echo "TZ='America/Manaus'                             # Set system time zone
 SYS_LANGUAGE='en'                               # Default system language
 SYS_LANGUAGES='en,pt'                           # System languages
+SYS_TRANSLATIONS=''                             # Directory of extra active.<language>.toml bundles
 SYS_PREFORK='true'                              # Enable Fiber Prefork
 
+LOG_LEVEL='info'                                # Log level: debug, info, warn or error
+LOG_FORMAT='text'                               # Log format: json for the log collectors or text
+
 API_PORT='9000'                                 # API Container PORT
-API_LOGGER='true'                               # API Logger enable
+API_LOGGER='true'                               # Log every request with its status and latency
 API_SWAGGO='true'                               # API Swagger enable
 API_DEFAULT_SORT='updated_at'                   # API default column sort
 API_DEFAULT_ORDER='desc'                        # API default order
+API_RATE_LIMIT='200'                            # API requests allowed by IP per minute
+API_SHUTDOWN_TIMEOUT='30'                       # [SECONDS] API time to finish the requests in progress on shutdown
+APP_URL='http://localhost:3000'                 # Frontend URL used in the emailed links, like APP_URL/invite?token=
+
+METRICS_ENABLED='false'                         # Expose the Prometheus metrics on /metrics
+METRICS_TOKEN=''                                # Bearer token required by /metrics, can be empty only with METRICS_ADDRESS
+METRICS_ADDRESS=''                              # Serve /metrics on its own address, like 127.0.0.1:9100, instead of the API port, not supported with SYS_PREFORK
+
+TRACING_EXPORTER='none'                         # Export the OpenTelemetry spans: none, otlp or stdout
+TRACING_ENDPOINT=''                             # OTLP/HTTP collector, like localhost:4318, the OTEL_EXPORTER_OTLP_* variables apply when empty
+TRACING_INSECURE='false'                        # Send the spans to the collector without TLS
 
 ACCESS_TOKEN_EXPIRE='120'                       # [MINUTES] Access token expiration time
-ACCESS_TOKEN_PRIVAT='${tokens[0, 0]}'           # Token to encode access token - PRIVATE TOKEN
-ACCESS_TOKEN_PUBLIC='${tokens[0, 1]}'           # Token to decode access token - PUBLIC TOKEN
+ACCESS_TOKEN_PRIVATE='${tokens[0, 0]}'          # Keys to encode access token, comma separated, the first signs (RSA, EC P-256 or Ed25519) - PRIVATE TOKEN
+ACCESS_TOKEN_PUBLIC='${tokens[0, 1]}'           # Extra keys to decode access token, comma separated, for rotated keys - PUBLIC TOKEN
+
+REFRESH_TOKEN_EXPIRE='360'                      # [MINUTES] Refresh token expiration time
+REFRESH_TOKEN_PRIVATE='${tokens[1, 0]}'         # Keys to encode refresh token, comma separated, the first signs (RSA, EC P-256 or Ed25519) - PRIVATE TOKEN
+REFRESH_TOKEN_PUBLIC='${tokens[1, 1]}'          # Extra keys to decode refresh token, comma separated, for rotated keys - PUBLIC TOKEN
+
+AUTH_MAX_ATTEMPTS='5'                           # Failed logins before locking the email from the IP, counted by each prefork child
+AUTH_IP_MAX_ATTEMPTS='100'                      # Failed logins before locking the IP, higher for the users behind a NAT
+AUTH_BACKOFF_DELAY='1'                          # [SECONDS] Delay after a failed login, doubled on every new failure
+AUTH_LOCKOUT_TIME='15'                          # [MINUTES] Lock time after AUTH_MAX_ATTEMPTS failed logins
+AUTH_LOCKOUT_WEBHOOK=''                         # URL notified with a POST when a lockout happens
+
+PASSWORD_MIN_LENGTH='10'                        # Minimum password length
+PASSWORD_MIN_CLASSES='1'                        # Minimum of character classes (lowercase, uppercase, digits and symbols) in passwords
+PASSWORD_HISTORY='5'                            # Previous passwords that can not be reused, 0 disables
+PASSWORD_CHECK_COMMON='true'                    # Deny the passwords in the embedded list of common and breached passwords
+PASSWORD_HASHER='argon2id'                      # Hash of the new passwords: argon2id or bcrypt, the other hashes are upgraded on login
+ARGON2_MEMORY='65536'                           # [KiB] Argon2id memory
+ARGON2_ITERATIONS='3'                           # Argon2id iterations
+ARGON2_PARALLELISM='4'                          # Argon2id parallelism
+BCRYPT_COST='10'                                # Bcrypt cost, when PASSWORD_HASHER is bcrypt
+
+OIDC_ISSUER=''                                  # OpenID Connect issuer URL, empty disables the OIDC login
+OIDC_CLIENT_ID=''                               # OpenID Connect client ID
+OIDC_CLIENT_SECRET=''                           # OpenID Connect client secret
+OIDC_REDIRECT_URL=''                            # Callback URL, like https://gopass.example.com/auth/oidc/callback
+OIDC_SCOPES='openid,email,profile'              # Requested scopes, comma separated
+OIDC_PROVISION_PROFILE=''                       # Profile name of users created on the first login, empty disables the provisioning
+OIDC_COOKIE_KEY='$(openssl rand -base64 32)'    # Key encrypting the login session cookie
+
+LDAP_URL=''                                     # Directory URL, like ldaps://ldap.example.com, empty disables the LDAP login
+LDAP_START_TLS='false'                          # Upgrade ldap:// connections with StartTLS
+LDAP_BIND_DN=''                                 # Service account DN used to search the directory, empty for anonymous searches
+LDAP_BIND_PASS=''                               # Service account password
+LDAP_USER_BASE_DN=''                            # Base DN of the users, like ou=people,dc=example,dc=org
+LDAP_USER_FILTER='(mail=%s)'                    # User filter, %s is replaced by the login email
+LDAP_MAIL_ATTR='mail'                           # User email attribute
+LDAP_NAME_ATTR='cn'                             # User name attribute
+LDAP_GROUP_BASE_DN=''                           # Base DN of the groups, empty reads only the memberOf attribute
+LDAP_GROUP_FILTER='(member=%s)'                 # Group filter, %s is replaced by the user DN
+LDAP_GROUP_PROFILES=''                          # Group to profile map, like go-pass-admins:ADMIN,go-pass-users:USER, the first match wins
+LDAP_SYNC_INTERVAL='60'                         # [MINUTES] Interval disabling users removed from the directory, 0 disables
+
+INVITE_TOKEN_EXPIRE='4320'                      # [MINUTES] Invitation link expiration time
+RESET_TOKEN_EXPIRE='30'                         # [MINUTES] Password reset link expiration time
+
+MAIL_DRIVER='log'                               # Email delivery: smtp, file or log
+MAIL_FROM='go-pass@localhost'                   # Email sender address
+MAIL_FILE='mail.log'                            # File receiving the emails when MAIL_DRIVER is file
+SMTP_HOST='localhost'                           # SMTP server HOST
+SMTP_PORT='587'                                 # SMTP server PORT
+SMTP_USER=''                                    # SMTP USER, empty disables the authentication
+SMTP_PASS=''                                    # SMTP PASS
+SMTP_TLS='false'                                # Implicit TLS, usually on port 465, otherwise STARTTLS is used when offered
 
-RFRESH_TOKEN_EXPIRE='360'                       # [MINUTES] Refresh token expiration time
-RFRESH_TOKEN_PRIVAT='${tokens[1, 0]}'           # Token to encode refresh token - PRIVATE TOKEN
-RFRESH_TOKEN_PUBLIC='${tokens[1, 1]}'           # Token to decode refresh token - PUBLIC TOKEN
+DB_DRIVER='postgres'                            # Storage: postgres, or sqlite for a single file database
+SQLITE_PATH='gopass.db'                         # Database file when DB_DRIVER is sqlite
 
 POSTGRES_HOST='postgres'                        # Postgres Container HOST
 POSTGRES_PORT='5432'                            # Postgres Container PORT
@@ -0,0 +1,150 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/raulaguila/go-pass/pkg/ldapauth"
+	"github.com/raulaguila/go-pass/pkg/logging"
+	"github.com/raulaguila/go-pass/pkg/tracing"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/text/language"
+)
+
+func invalid(key string, format string, args ...interface{}) error {
+	return fmt.Errorf("%v: %v", key, fmt.Sprintf(format, args...))
+}
+
+func validPort(port string) bool {
+	value, err := strconv.Atoi(port)
+	return err == nil && value > 0 && value < 65536
+}
+
+// Validate returns every invalid option, the keyrings are also decoded.
+func (c *Config) Validate() error {
+	errs := []error{}
+	check := func(ok bool, key string, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, invalid(key, format, args...))
+		}
+	}
+
+	_, err := time.LoadLocation(c.System.TimeZone)
+	check(err == nil, "system.time_zone", "unknown time zone %q", c.System.TimeZone)
+	check(len(c.System.Languages) > 0, "system.languages", "at least one language is required")
+	for _, lang := range c.System.Languages {
+		_, err := language.Parse(lang)
+		check(err == nil, "system.languages", "%q is not a BCP 47 language tag", lang)
+		check(hasTranslation(c.System.Translations, lang), "system.languages", "no translation for %q", lang)
+	}
+	if c.System.Translations != "" {
+		info, err := os.Stat(c.System.Translations)
+		check(err == nil && info.IsDir(), "system.translations", "%q is not a directory", c.System.Translations)
+	}
+	check(slices.Contains(c.System.Languages, c.System.Language), "system.language", "%q is not one of the languages", c.System.Language)
+
+	_, err = logging.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level", "unknown level %q, use debug, info, warn or error", c.Log.Level)
+	check(slices.Contains([]string{logging.FormatJSON, logging.FormatText}, strings.ToLower(c.Log.Format)), "log.format", "must be json or text")
+
+	check(validPort(c.API.Port), "api.port", "invalid port %q", c.API.Port)
+	check(c.API.DefaultSort != "", "api.default_sort", "required")
+	check(slices.Contains([]string{"asc", "desc"}, strings.ToLower(c.API.DefaultOrder)), "api.default_order", "must be asc or desc")
+	check(c.API.RateLimit > 0, "api.rate_limit", "must be positive")
+	check(c.API.ShutdownTimeout > 0, "api.shutdown_timeout", "must be positive")
+	if c.Metrics.Enabled && c.Metrics.Address != "" {
+		_, port, err := net.SplitHostPort(c.Metrics.Address)
+		check(err == nil && validPort(port), "metrics.address", "invalid address %q, expected host:port", c.Metrics.Address)
+		// Only one process could bind the address, serving the requests counted by that process alone.
+		check(!c.System.Prefork, "metrics.address", "not supported with system.prefork")
+	}
+	// Without a token, the metrics are only served on their own address, which can be kept off the public network.
+	check(!c.Metrics.Enabled || c.Metrics.Token != "" || c.Metrics.Address != "", "metrics.token", "required to serve the metrics on the API port, or set metrics.address")
+
+	check(slices.Contains([]string{tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout}, strings.ToLower(c.Tracing.Exporter)), "tracing.exporter", "must be none, otlp or stdout")
+
+	appURL, err := url.Parse(c.API.AppURL)
+	check(err == nil && appURL.Scheme != "" && appURL.Host != "", "api.app_url", "invalid URL %q", c.API.AppURL)
+
+	check(c.Tokens.AccessExpire > 0, "tokens.access_expire", "must be positive")
+	check(c.Tokens.RefreshExpire > 0, "tokens.refresh_expire", "must be positive")
+	check(c.Tokens.InviteExpire > 0, "tokens.invite_expire", "must be positive")
+	check(c.Tokens.ResetExpire > 0, "tokens.reset_expire", "must be positive")
+	_, err = keyring.FromBase64(c.Tokens.AccessPrivate, c.Tokens.AccessPublic)
+	check(c.Tokens.AccessPrivate != "" && err == nil, "tokens.access_private", "invalid access keys: %v", err)
+	_, err = keyring.FromBase64(c.Tokens.RefreshPrivate, c.Tokens.RefreshPublic)
+	check(c.Tokens.RefreshPrivate != "" && err == nil, "tokens.refresh_private", "invalid refresh keys: %v", err)
+
+	check(c.Auth.MaxAttempts > 0, "auth.max_attempts", "must be positive")
+	check(c.Auth.IPMaxAttempts >= c.Auth.MaxAttempts, "auth.ip_max_attempts", "can not be lower than auth.max_attempts")
+	check(c.Auth.BackoffDelay >= 0, "auth.backoff_delay", "can not be negative")
+	check(c.Auth.LockoutTime > 0, "auth.lockout_time", "must be positive")
+
+	check(c.Password.MinLength > 0, "password.min_length", "must be positive")
+	check(c.Password.MinClasses >= 0 && c.Password.MinClasses <= 4, "password.min_classes", "must be between 0 and 4")
+	check(c.Password.History >= 0, "password.history", "can not be negative")
+	switch strings.ToLower(c.Password.Hasher) {
+	case "argon2id":
+		check(c.Password.Argon2Iterations > 0, "password.argon2_iterations", "must be positive")
+		check(c.Password.Argon2Parallelism > 0 && c.Password.Argon2Parallelism < 256, "password.argon2_parallelism", "must be between 1 and 255")
+		check(c.Password.Argon2Memory >= 8*c.Password.Argon2Parallelism, "password.argon2_memory", "must be at least 8 KiB per thread")
+	case "bcrypt":
+		check(c.Password.BcryptCost >= bcrypt.MinCost && c.Password.BcryptCost <= bcrypt.MaxCost, "password.bcrypt_cost", "must be between %v and %v", bcrypt.MinCost, bcrypt.MaxCost)
+	default:
+		check(false, "password.hasher", "must be argon2id or bcrypt")
+	}
+
+	if c.OIDC.Issuer != "" {
+		check(c.OIDC.ClientID != "", "oidc.client_id", "required with an issuer")
+		check(c.OIDC.RedirectURL != "", "oidc.redirect_url", "required with an issuer")
+	}
+
+	if c.LDAP.URL != "" {
+		check(c.LDAP.UserBaseDN != "", "ldap.user_base_dn", "required with a directory URL")
+		_, err = ldapauth.ParseGroupMap(c.LDAP.GroupProfiles)
+		check(err == nil, "ldap.group_profiles", "%v", err)
+		check(c.LDAP.SyncInterval >= 0, "ldap.sync_interval", "can not be negative")
+	}
+
+	_, err = mail.ParseAddress(c.Mail.From)
+	check(err == nil, "mail.from", "invalid address %q", c.Mail.From)
+	switch strings.ToLower(c.Mail.Driver) {
+	case "smtp":
+		check(c.Mail.SMTPHost != "", "mail.smtp_host", "required by the smtp driver")
+		check(validPort(c.Mail.SMTPPort), "mail.smtp_port", "invalid port %q", c.Mail.SMTPPort)
+	case "file":
+		check(c.Mail.File != "", "mail.file", "required by the file driver")
+	case "log":
+	default:
+		check(false, "mail.driver", "must be smtp, file or log")
+	}
+
+	switch strings.ToLower(c.Database.Driver) {
+	case "postgres":
+		check(c.Postgres.Host != "", "postgres.host", "required")
+		check(validPort(c.Postgres.Port), "postgres.port", "invalid port %q", c.Postgres.Port)
+		check(c.Postgres.User != "", "postgres.user", "required")
+		check(c.Postgres.Base != "", "postgres.base", "required")
+	case "sqlite":
+		check(c.SQLite.Path != "", "sqlite.path", "required by the sqlite driver")
+		// Each prefork child would open the file, racing on the migrations and the writes.
+		check(!c.System.Prefork, "system.prefork", "not supported by the sqlite driver")
+	default:
+		check(false, "database.driver", "must be postgres or sqlite")
+	}
+
+	_, err = mail.ParseAddress(c.Admin.Mail)
+	check(err == nil, "admin.mail", "invalid address %q", c.Admin.Mail)
+	check(c.Admin.Password != "", "admin.password", "required")
+
+	return errors.Join(errs...)
+}
@@ -42,6 +42,26 @@ const docTemplate = `{
                 }
             }
         },
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys to verify the access tokens, selected by the 'kid' token header",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Token signing keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/keyring.JWKSet"
+                        }
+                    }
+                }
+            }
+        },
         "/account": {
             "get": {
                 "security": [
@@ -91,6 +111,18 @@ const docTemplate = `{
                         "name": "search",
                         "in": "query"
                     },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "csv",
+                        "example": [
+                            1
+                        ],
+                        "name": "site_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "example": "'updated_at', 'created_at', 'name' or some other field of the response object",
@@ -111,7 +143,7 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -160,19 +192,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -221,19 +253,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -289,19 +321,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -345,13 +377,13 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -403,19 +435,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -463,13 +495,13 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -510,13 +542,13 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -560,13 +592,309 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/forgot": {
+            "post": {
+                "description": "Email a password reset link, the response is the same whether the email exists or not",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Forgot password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Email model",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ForgotInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "Validates the identity provider response and authenticates the user by email",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "OpenID Connect callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Login state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Redirects to the identity provider login",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "OpenID Connect login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Change the password of the authenticated user, ending the user sessions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Password model",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasswordInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset": {
+            "post": {
+                "description": "Set a new password using the token of the reset link, ending the user sessions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Password model",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasswordInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports that the process is up, without checking its dependencies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ping"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
                         }
                     }
                 }
@@ -641,7 +969,7 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -690,19 +1018,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -751,19 +1079,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -819,19 +1147,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -875,13 +1203,13 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -956,7 +1284,7 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1005,19 +1333,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1066,19 +1394,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1134,19 +1462,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1190,13 +1518,13 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1271,7 +1599,7 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1320,19 +1648,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1381,19 +1709,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1449,19 +1777,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1505,13 +1833,127 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Reports whether the instance can serve: listening, with the database reachable and migrated and the signing keys parsed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ping"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/secrets/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Resolve references like gopass://site/github/account/deploy-bot#password (fields: password, username or url), every resolution is audited",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "Secret"
+                ],
+                "summary": "Resolve secrets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json, dotenv or shell",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Secret references",
+                        "name": "secrets",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SecretResolveInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/secretref.Secret"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1586,7 +2028,7 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1635,19 +2077,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1696,30 +2138,199 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update site by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Site"
+                ],
+                "summary": "Update site by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Site model",
+                        "name": "site",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SiteInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Site"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete site by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Site"
+                ],
+                "summary": "Delete site by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/token": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the personal tokens of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token"
+                ],
+                "summary": "Get personal tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.ItemsOutputDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update site by ID",
+                "description": "Insert personal token, the token value is only returned in this response",
                 "consumes": [
                     "application/json"
                 ],
@@ -1727,9 +2338,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Site"
+                    "Token"
                 ],
-                "summary": "Update site by ID",
+                "summary": "Insert personal token",
                 "parameters": [
                     {
                         "type": "string",
@@ -1738,56 +2349,51 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Site ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Site model",
-                        "name": "site",
+                        "description": "Personal token model",
+                        "name": "token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SiteInputDTO"
+                            "$ref": "#/definitions/dto.PersonalTokenInputDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.Site"
+                            "$ref": "#/definitions/domain.PersonalTokenResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/token/{id}": {
             "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete site by ID",
+                "description": "Revoke personal token by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -1795,9 +2401,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Site"
+                    "Token"
                 ],
-                "summary": "Delete site by ID",
+                "summary": "Revoke personal token",
                 "parameters": [
                     {
                         "type": "string",
@@ -1807,7 +2413,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Site ID",
+                        "description": "Token ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1817,16 +2423,22 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1892,6 +2504,12 @@ const docTemplate = `{
                         "example": "'updated_at', 'created_at', 'name' or some other field of the response object",
                         "name": "sort",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "local",
+                        "name": "source",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1907,7 +2525,7 @@ const docTemplate = `{
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1918,7 +2536,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Insert user",
+                "description": "Insert user and email the invitation to set the password",
                 "consumes": [
                     "application/json"
                 ],
@@ -1956,19 +2574,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -1976,7 +2594,7 @@ const docTemplate = `{
         },
         "/user/{email}/passw": {
             "patch": {
-                "description": "Set user password by ID",
+                "description": "Set the password of an invited user, using the token of the invitation",
                 "consumes": [
                     "application/json"
                 ],
@@ -2018,16 +2636,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/domain.User"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -2076,19 +2694,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -2144,19 +2762,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -2200,13 +2818,71 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/invite": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Email a new invitation to a user without password, invalidating the previous one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Invite user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language responses",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -2219,7 +2895,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Reset user password by ID",
+                "description": "Reset user password by ID and email a new invitation",
                 "consumes": [
                     "application/json"
                 ],
@@ -2255,13 +2931,13 @@ const docTemplate = `{
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/httphelper.HTTPResponse"
+                            "$ref": "#/definitions/httphelper.Problem"
                         }
                     }
                 }
@@ -2333,6 +3009,53 @@ const docTemplate = `{
                 }
             }
         },
+        "domain.PersonalTokenResponse": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "allowed_ips": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 2
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sites": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "domain.Phone": {
             "type": "object",
             "required": [
@@ -2407,9 +3130,16 @@ const docTemplate = `{
                 "name"
             ],
             "properties": {
+                "change_password": {
+                    "description": "ChangePassword blocks every route but the password change until the user picks a new password.",
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "language": {
+                    "type": "string"
+                },
                 "mail": {
                     "type": "string"
                 },
@@ -2423,6 +3153,9 @@ const docTemplate = `{
                 "profile": {
                     "$ref": "#/definitions/domain.Profile"
                 },
+                "source": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "boolean"
                 }
@@ -2453,6 +3186,15 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.ForgotInputDTO": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.cena@email.com"
+                }
+            }
+        },
         "dto.ItemsOutputDTO": {
             "type": "object",
             "properties": {
@@ -2491,13 +3233,21 @@ const docTemplate = `{
         "dto.PasswordInputDTO": {
             "type": "object",
             "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "old-secret-01"
+                },
                 "password": {
                     "type": "string",
-                    "example": "secret"
+                    "example": "new-secret-02"
                 },
                 "password_confirm": {
                     "type": "string",
-                    "example": "secret"
+                    "example": "new-secret-02"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9..."
                 }
             }
         },
@@ -2514,6 +3264,47 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.PersonalTokenInputDTO": {
+            "type": "object",
+            "properties": {
+                "allowed_ips": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "10.0.0.0/8"
+                    ]
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "CI deploy"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "account:read",
+                        "account:reveal"
+                    ]
+                },
+                "sites": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1
+                    ]
+                }
+            }
+        },
         "dto.PhoneInputDTO": {
             "type": "object",
             "properties": {
@@ -2539,6 +3330,34 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.SecretRefInputDTO": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "DEPLOY_PASSWORD"
+                },
+                "ref": {
+                    "type": "string",
+                    "example": "gopass://site/github/account/deploy-bot#password"
+                }
+            }
+        },
+        "dto.SecretResolveInputDTO": {
+            "type": "object",
+            "required": [
+                "secrets"
+            ],
+            "properties": {
+                "secrets": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.SecretRefInputDTO"
+                    }
+                }
+            }
+        },
         "dto.SiteInputDTO": {
             "type": "object",
             "properties": {
@@ -2559,6 +3378,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "john.cena@email.com"
                 },
+                "language": {
+                    "type": "string",
+                    "example": "pt-BR"
+                },
                 "name": {
                     "type": "string",
                     "example": "John Cena"
@@ -2573,16 +3396,148 @@ const docTemplate = `{
                 }
             }
         },
-        "httphelper.HTTPResponse": {
+        "health.Component": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "connection refused"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.Component"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "httphelper.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "Email"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Email does not meet the 'email[]' requirement with value 'invalid'"
+                },
+                "param": {
+                    "type": "string",
+                    "example": ""
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "email"
+                }
+            }
+        },
+        "httphelper.Problem": {
             "type": "object",
             "properties": {
                 "code": {
+                    "type": "string",
+                    "example": "user_not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "User not found."
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/httphelper.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/user/1"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "0b8f9d6e-3c1a-4d8e-9b5e-1f2a3b4c5d6e"
+                },
+                "status": {
                     "type": "integer",
-                    "example": 400
+                    "example": 404
                 },
-                "message": {
+                "title": {
+                    "type": "string",
+                    "example": "Not found"
+                },
+                "type": {
                     "type": "string",
-                    "example": "status bad request"
+                    "example": "urn:go-pass:problem:user_not_found"
+                }
+            }
+        },
+        "keyring.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "keyring.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/keyring.JWK"
+                    }
+                }
+            }
+        },
+        "secretref.Secret": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "ref": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         }
@@ -1,80 +1,35 @@
 package handler
 
 import (
-	"errors"
-	"fmt"
-	"log"
-	"strings"
-
 	"github.com/gofiber/fiber/v2"
 	"github.com/raulaguila/go-pass/internal/api/middleware"
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
 	"github.com/raulaguila/go-pass/internal/pkg/dto"
-	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/internal/pkg/metrics"
 	"github.com/raulaguila/go-pass/pkg/filter"
 	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
-	"github.com/raulaguila/go-pass/pkg/pgerror"
-	"github.com/raulaguila/go-pass/pkg/validator"
 )
 
 type AccountHandler struct {
 	accountService domain.AccountService
-}
-
-func (AccountHandler) foreignKeyViolatedMethod(c *fiber.Ctx, err error, translation *i18n.Translation) error {
-	switch c.Method() {
-	case fiber.MethodPut, fiber.MethodPost, fiber.MethodPatch:
-		if strings.Contains(err.Error(), "site") {
-			return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrSiteNotFound)
-		}
-		if strings.Contains(err.Error(), "phone") {
-			return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrPhoneNotFound)
-		}
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrAccountNotFound)
-	case fiber.MethodDelete:
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrAccountUsed)
-	default:
-		return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
-	}
-}
-
-func (s AccountHandler) handlerError(c *fiber.Ctx, err error) error {
-	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
-	fmt.Printf("Error: %\n", err.Error())
-
-	switch pgerror.HandlerError(err) {
-	case pgerror.ErrDuplicatedKey:
-		return httphelper.NewHTTPResponse(c, fiber.StatusConflict, translation.ErrAccountRegistered)
-	case pgerror.ErrForeignKeyViolated:
-		return s.foreignKeyViolatedMethod(c, err, translation)
-	case pgerror.ErrUndefinedColumn:
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrUndefinedColumn)
-	case translation.ErrInvalidId:
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidId)
-	}
-
-	if errors.As(err, &validator.ErrValidator) {
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, err)
-	}
-
-	log.Println(err.Error())
-	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
+	metrics        *metrics.Metrics
 }
 
 // Creates a new handler.
-func NewAccountHandler(route fiber.Router, ps domain.AccountService, mid *middleware.RequesttMiddleware) {
+func NewAccountHandler(route fiber.Router, access, list fiber.Handler, ps domain.AccountService, mid *middleware.RequesttMiddleware, m *metrics.Metrics) {
 	handler := &AccountHandler{
 		accountService: ps,
+		metrics:        m,
 	}
 
-	route.Use(middleware.MidAccess)
+	route.Use(access, middleware.Scoped("account"))
 
-	route.Get("", middleware.GetGenericFilter, handler.getAccounts)
-	route.Post("", middleware.GetAccountDTO, handler.createAccount)
-	route.Get("/:"+httphelper.ParamID, mid.AccountByID, handler.getAccountBydID)
-	route.Get("/:"+httphelper.ParamID+"/pass", mid.AccountByID, handler.getAccountPasswordBydID)
-	route.Put("/:"+httphelper.ParamID, mid.AccountByID, middleware.GetAccountDTO, handler.updateAccount)
-	route.Delete("/:"+httphelper.ParamID, mid.AccountByID, handler.deleteAccount)
+	route.Get("", list, middleware.RestrictAccountSites, handler.getAccounts)
+	route.Post("", middleware.GetAccountDTO, middleware.RestrictAccountSites, handler.createAccount)
+	route.Get("/:"+httphelper.ParamID, mid.AccountByID, middleware.RestrictAccountSites, handler.getAccountBydID)
+	route.Get("/:"+httphelper.ParamID+"/pass", middleware.RequireScope(domain.ScopeAccountReveal), mid.AccountByID, middleware.RestrictAccountSites, handler.getAccountPasswordBydID)
+	route.Put("/:"+httphelper.ParamID, mid.AccountByID, middleware.GetAccountDTO, middleware.RestrictAccountSites, handler.updateAccount)
+	route.Delete("/:"+httphelper.ParamID, mid.AccountByID, middleware.RestrictAccountSites, handler.deleteAccount)
 }
 
 // getAccounts godoc
@@ -84,16 +39,16 @@ func NewAccountHandler(route fiber.Router, ps domain.AccountService, mid *middle
 // @Accept       json
 // @Produce      json
 // @Param        lang query string false "Language responses"
-// @Param        filter query filter.Filter false "Optional Filter"
+// @Param        filter query filter.AccountFilter false "Optional Filter"
 // @Success      200  {array}   dto.ItemsOutputDTO
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /account [get]
 // @Security	 Bearer
 func (h *AccountHandler) getAccounts(c *fiber.Ctx) error {
 	user := c.Locals(httphelper.LocalUser).(*domain.User)
-	response, err := h.accountService.GetAccountsOutputDTO(c.Context(), c.Locals(httphelper.LocalFilter).(*filter.Filter), user.Id)
+	response, err := h.accountService.GetAccountsOutputDTO(c.UserContext(), c.Locals(httphelper.LocalFilter).(*filter.AccountFilter), user.Id)
 	if err != nil {
-		return h.handlerError(c, err)
+		return handlerError(c, err, accountResource)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -108,9 +63,9 @@ func (h *AccountHandler) getAccounts(c *fiber.Ctx) error {
 // @Param        lang query string false "Language responses"
 // @Param        id   path			int			true        "Account ID"
 // @Success      200  {object}  domain.Account
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /account/{id} [get]
 // @Security	 Bearer
 func (h *AccountHandler) getAccountBydID(c *fiber.Ctx) error {
@@ -126,13 +81,14 @@ func (h *AccountHandler) getAccountBydID(c *fiber.Ctx) error {
 // @Param        lang query string false "Language responses"
 // @Param        id   path			int			true        "Account ID"
 // @Success      200  {object}  map[string]string
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /account/{id}/pass [get]
 // @Security	 Bearer
 func (h *AccountHandler) getAccountPasswordBydID(c *fiber.Ctx) error {
 	account := c.Locals(httphelper.LocalObject).(*domain.Account)
+	h.metrics.Reveal(metrics.SourceAccount, 1)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"password": account.DecodePass(),
 	})
@@ -147,17 +103,17 @@ func (h *AccountHandler) getAccountPasswordBydID(c *fiber.Ctx) error {
 // @Param        lang query string false "Language responses"
 // @Param        account body dto.AccountInputDTO true "Account model"
 // @Success      201  {object}  domain.Account
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      409  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      409  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /account [post]
 // @Security	 Bearer
 func (h *AccountHandler) createAccount(c *fiber.Ctx) error {
 	user := c.Locals(httphelper.LocalUser).(*domain.User)
 	accountDTO := c.Locals(httphelper.LocalDTO).(*dto.AccountInputDTO)
-	account, err := h.accountService.CreateAccount(c.Context(), accountDTO, user.Id)
+	account, err := h.accountService.CreateAccount(c.UserContext(), accountDTO, user.Id)
 	if err != nil {
-		return h.handlerError(c, err)
+		return handlerError(c, err, accountResource)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(account)
@@ -173,18 +129,18 @@ func (h *AccountHandler) createAccount(c *fiber.Ctx) error {
 // @Param        id     path    int     true        "Account ID"
 // @Param        account body dto.AccountInputDTO true "Account model"
 // @Success      200  {object}  domain.Account
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /account/{id} [put]
 // @Security	 Bearer
 func (h *AccountHandler) updateAccount(c *fiber.Ctx) error {
 	user := c.Locals(httphelper.LocalUser).(*domain.User)
 	accountDTO := c.Locals(httphelper.LocalDTO).(*dto.AccountInputDTO)
 	account := c.Locals(httphelper.LocalObject).(*domain.Account)
-	account, err := h.accountService.UpdateAccount(c.Context(), account, accountDTO, user.Id)
+	account, err := h.accountService.UpdateAccount(c.UserContext(), account, accountDTO, user.Id)
 	if err != nil {
-		return h.handlerError(c, err)
+		return handlerError(c, err, accountResource)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(account)
@@ -199,15 +155,15 @@ func (h *AccountHandler) updateAccount(c *fiber.Ctx) error {
 // @Param        lang query string false "Language responses"
 // @Param        id     path    int     true        "Account ID"
 // @Success      204  {object}  nil
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /account/{id} [delete]
 // @Security	 Bearer
 func (h *AccountHandler) deleteAccount(c *fiber.Ctx) error {
 	user := c.Locals(httphelper.LocalUser).(*domain.User)
 	account := c.Locals(httphelper.LocalObject).(*domain.Account)
-	if err := h.accountService.DeleteAccount(c.Context(), account, user.Id); err != nil {
-		return h.handlerError(c, err)
+	if err := h.accountService.DeleteAccount(c.UserContext(), account, user.Id); err != nil {
+		return handlerError(c, err, accountResource)
 	}
 
 	return c.Status(fiber.StatusNoContent).Send(nil)
@@ -1,26 +1,46 @@
 package handler
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/google/uuid"
 	"github.com/raulaguila/go-pass/internal/api/middleware"
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
 	"github.com/raulaguila/go-pass/internal/pkg/dto"
 	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/internal/pkg/metrics"
+	"github.com/raulaguila/go-pass/pkg/bruteforce"
+	"github.com/raulaguila/go-pass/pkg/hasher"
+	"github.com/raulaguila/go-pass/pkg/helpers"
 	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/raulaguila/go-pass/pkg/ldapauth"
 	"gorm.io/gorm"
 )
 
-type AuthHandler struct {
-	authService domain.AuthService
-}
+// Password recovery requests allowed by IP and by email in the window.
+const (
+	recoveryMaxRequests int           = 5
+	recoveryWindow      time.Duration = 15 * time.Minute
+)
 
-func (AuthHandler) handlerError(c *fiber.Ctx, err error) error {
-	messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+type AuthHandler struct {
+	authService      domain.AuthService
+	userService      domain.UserService
+	userTokenService domain.UserTokenService
+	guard            *bruteforce.Guard
+	ipGuard          *bruteforce.Guard
+	metrics          *metrics.Metrics
 
-	log.Println(err.Error())
-	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, messages.ErrGeneric)
+	// Hash compared when the email is unknown, so both failures take the same time.
+	dummyPassword string
 }
 
 func (s *AuthHandler) checkCredentials(c *fiber.Ctx) error {
@@ -30,22 +50,52 @@ func (s *AuthHandler) checkCredentials(c *fiber.Ctx) error {
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidDatas)
 	}
 
-	user, err := s.authService.GetUserByMail(c.Context(), credentials.Email)
-	if err != nil {
-		switch err {
-		case gorm.ErrRecordNotFound:
-			return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrUserNotFound)
+	// The email is locked from the IP only, so nobody can lock another user out, and the IP has its own guard with a
+	// higher threshold, for the users behind a NAT.
+	mailKey, ipKey := "mail:"+strings.ToLower(credentials.Email)+",ip:"+c.IP(), "ip:"+c.IP()
+	mailWait, _ := s.guard.Check(mailKey)
+	ipWait, _ := s.ipGuard.Check(ipKey)
+	if wait := max(mailWait, ipWait); wait > 0 {
+		c.Set(fiber.HeaderRetryAfter, fmt.Sprint(math.Ceil(wait.Seconds())))
+		s.metrics.Login(metrics.ResultLocked)
+		return httphelper.NewHTTPResponse(c, fiber.StatusTooManyRequests, translation.ErrLoginLocked)
+	}
+	fail := func() error {
+		s.guard.Fail(mailKey)
+		s.ipGuard.Fail(ipKey)
+		s.metrics.Login(metrics.ResultFailure)
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrInvalidCredentials)
+	}
+
+	user, err := s.authService.GetUserByMail(c.UserContext(), credentials.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		httphelper.LogError(c, err)
+		return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
+	}
+
+	// Users without a local password are authenticated by the directory, when enabled. The dummy hash is compared
+	// first, so the unknown emails take the time of a password check with or without the directory.
+	if err != nil || user.Password == nil {
+		(&domain.User{Password: &s.dummyPassword}).ValidatePassword(credentials.Password)
+		user, err = s.authService.DirectoryLogin(c.UserContext(), credentials.Email, credentials.Password)
+		switch {
+		case err == nil:
+		case errors.Is(err, domain.ErrDirectoryDisabled), errors.Is(err, ldapauth.ErrInvalidCredentials), errors.Is(err, gorm.ErrRecordNotFound):
+			return fail()
+		case errors.Is(err, domain.ErrExternalUserConflict):
+			s.metrics.Login(metrics.ResultFailure)
+			return httphelper.NewHTTPResponse(c, fiber.StatusConflict, translation.ErrExternalUserConflict)
 		default:
-			log.Println(err.Error())
+			httphelper.LogError(c, err)
 			return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
 		}
+	} else if !user.ValidatePassword(credentials.Password) {
+		return fail()
 	}
-
-	if !user.ValidatePassword(credentials.Password) {
-		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrIncorrectPassword)
-	}
+	s.guard.Reset(mailKey)
 
 	if !user.Status || user.New {
+		s.metrics.Login(metrics.ResultFailure)
 		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrDisabledUser)
 	}
 
@@ -54,15 +104,48 @@ func (s *AuthHandler) checkCredentials(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+func (s *AuthHandler) recoveryLimiter(key func(*fiber.Ctx) string) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:          recoveryMaxRequests,
+		Expiration:   recoveryWindow,
+		KeyGenerator: key,
+		LimitReached: func(c *fiber.Ctx) error {
+			s.metrics.RateLimited(metrics.LimiterRecovery)
+			messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+			return httphelper.NewHTTPResponse(c, fiber.StatusTooManyRequests, messages.ErrManyRequest)
+		},
+	})
+}
+
 // Creates a new handler.
-func NewAuthHandler(route fiber.Router, as domain.AuthService) {
+func NewAuthHandler(route fiber.Router, access, refresh fiber.Handler, as domain.AuthService, us domain.UserService, uts domain.UserTokenService, guard, ipGuard *bruteforce.Guard, m *metrics.Metrics) {
 	handler := &AuthHandler{
-		authService: as,
+		authService:      as,
+		userService:      us,
+		userTokenService: uts,
+		guard:            guard,
+		ipGuard:          ipGuard,
+		metrics:          m,
+	}
+
+	// Created by the configured hasher, so it costs the same as the stored hashes.
+	dummyPassword, err := hasher.Hash(uuid.NewString())
+	helpers.PanicIfErr(err)
+	handler.dummyPassword = dummyPassword
+
+	byIP := func(c *fiber.Ctx) string {
+		return c.IP()
+	}
+	byMail := func(c *fiber.Ctx) string {
+		return strings.ToLower(c.Locals(httphelper.LocalDTO).(*dto.ForgotInputDTO).Email)
 	}
 
 	route.Post("", handler.checkCredentials, handler.login)
-	route.Get("", middleware.MidAccess, handler.me)
-	route.Put("", middleware.MidRefresh, handler.refresh)
+	route.Get("", middleware.AllowPasswordChange, access, handler.me)
+	route.Put("", middleware.AllowPasswordChange, refresh, handler.refresh)
+	route.Put("/password", middleware.AllowPasswordChange, access, middleware.GetPasswordInputDTO, handler.password)
+	route.Post("/forgot", handler.recoveryLimiter(byIP), middleware.GetForgotInputDTO, handler.recoveryLimiter(byMail), handler.forgot)
+	route.Post("/reset", handler.recoveryLimiter(byIP), middleware.GetPasswordInputDTO, handler.reset)
 }
 
 // login godoc
@@ -74,19 +157,22 @@ func NewAuthHandler(route fiber.Router, as domain.AuthService) {
 // @Param        lang query string false "Language responses"
 // @Param        credentials body dto.LoginInputDTO true "Credentials model"
 // @Success      200  {object}  domain.AuthResponse
-// @Failure      401  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.Problem
+// @Failure      409  {object}  httphelper.Problem
+// @Failure      429  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /auth [post]
 func (s *AuthHandler) login(c *fiber.Ctx) error {
-	authResponse, err := s.authService.Login(c.Context(), c.Locals(httphelper.LocalObject).(*domain.User), c.IP())
+	authResponse, err := s.authService.Login(c.UserContext(), c.Locals(httphelper.LocalObject).(*domain.User), c.IP())
 	if err != nil {
-		return s.handlerError(c, err)
+		return handlerError(c, err, nil)
 	}
 
 	if err := authResponse.Validate(); err != nil {
-		return s.handlerError(c, err)
+		return handlerError(c, err, nil)
 	}
 
+	s.metrics.Login(metrics.ResultSuccess)
 	return c.Status(fiber.StatusOK).JSON(authResponse)
 }
 
@@ -99,8 +185,8 @@ func (s *AuthHandler) login(c *fiber.Ctx) error {
 // @Param        Authorization header string false "User token"
 // @Param        lang query string false "Language responses"
 // @Success      200  {object}  domain.User
-// @Failure      401  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /auth [get]
 // @Security	 Bearer
 func (s *AuthHandler) me(c *fiber.Ctx) error {
@@ -116,18 +202,118 @@ func (s *AuthHandler) me(c *fiber.Ctx) error {
 // @Param        Authorization header string false "User token"
 // @Param        lang query string false "Language responses"
 // @Success      200  {object}  domain.TokensResponse
-// @Failure      401  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      401  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /auth [put]
 func (s *AuthHandler) refresh(c *fiber.Ctx) error {
-	tokensResponse, err := s.authService.Refresh(c.Context(), c.Locals(httphelper.LocalUser).(*domain.User), c.IP())
+	tokensResponse, err := s.authService.Refresh(c.UserContext(), c.Locals(httphelper.LocalUser).(*domain.User), c.IP())
+	if err == nil {
+		err = tokensResponse.Validate()
+	}
 	if err != nil {
-		return s.handlerError(c, err)
+		s.metrics.Refresh(metrics.ResultFailure)
+		return handlerError(c, err, nil)
 	}
 
-	if err := tokensResponse.Validate(); err != nil {
-		return s.handlerError(c, err)
+	s.metrics.Refresh(metrics.ResultSuccess)
+	return c.Status(fiber.StatusOK).JSON(tokensResponse)
+}
+
+// password godoc
+// @Summary      Change password
+// @Description  Change the password of the authenticated user, ending the user sessions
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Authorization header string false "User token"
+// @Param        lang query string false "Language responses"
+// @Param        password body dto.PasswordInputDTO true "Password model"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      401  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
+// @Router       /auth/password [put]
+// @Security	 Bearer
+func (s *AuthHandler) password(c *fiber.Ctx) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+	pass := c.Locals(httphelper.LocalDTO).(*dto.PasswordInputDTO)
+	if !pass.IsValid() {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrPassUnmatch)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(tokensResponse)
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	if pass.Current == nil || !user.ValidatePassword(*pass.Current) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrInvalidCredentials)
+	}
+
+	if err := s.userService.PasswordUser(c.UserContext(), user, pass); err != nil {
+		return handlerError(c, err, nil)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// forgot godoc
+// @Summary      Forgot password
+// @Description  Email a password reset link, the response is the same whether the email exists or not
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        email body dto.ForgotInputDTO true "Email model"
+// @Success      202  {object}  nil
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      429  {object}  httphelper.Problem
+// @Router       /auth/forgot [post]
+func (s *AuthHandler) forgot(c *fiber.Ctx) error {
+	email := c.Locals(httphelper.LocalDTO).(*dto.ForgotInputDTO).Email
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	// Sent in background, so the response time does not reveal whether the email exists. The context keeps the
+	// attributes of the request for the logs, the fiber context is reused once the response is sent.
+	ctx := context.WithoutCancel(c.UserContext())
+	go func() {
+		if err := s.userTokenService.ForgotPassword(ctx, email, translation); err != nil {
+			slog.ErrorContext(ctx, "could not send the password reset", "error", err)
+		}
+	}()
+
+	return c.Status(fiber.StatusAccepted).Send(nil)
+}
+
+// reset godoc
+// @Summary      Reset password
+// @Description  Set a new password using the token of the reset link, ending the user sessions
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        password body dto.PasswordInputDTO true "Password model"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      429  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
+// @Router       /auth/reset [post]
+func (s *AuthHandler) reset(c *fiber.Ctx) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+	pass := c.Locals(httphelper.LocalDTO).(*dto.PasswordInputDTO)
+	if !pass.IsValid() {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrPassUnmatch)
+	}
+
+	if pass.Token == nil {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidUserToken)
+	}
+
+	user, err := s.userTokenService.ResetPassword(c.UserContext(), *pass.Token, pass)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidUserToken) {
+			return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidUserToken)
+		}
+		return handlerError(c, err, nil)
+	}
+
+	// The new password also lifts the lockout of the email.
+	s.guard.Reset("mail:" + strings.ToLower(user.Email))
+	return c.Status(fiber.StatusNoContent).Send(nil)
 }
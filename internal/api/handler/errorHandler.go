@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/raulaguila/go-pass/pkg/passpolicy"
+	"github.com/raulaguila/go-pass/pkg/pgerror"
+	"github.com/raulaguila/go-pass/pkg/validator"
+	"gorm.io/gorm"
+)
+
+type (
+	// resourceErrors are the messages of the database errors of a resource.
+	resourceErrors struct {
+		notFound   error
+		registered error
+		// used answers the deletes of the rows still referenced.
+		used error
+		// references answer the writes referencing missing rows, the first one whose word is in the constraint
+		// violated is used, the empty word matches any constraint.
+		references []reference
+	}
+
+	reference struct {
+		word    string
+		message error
+	}
+
+	// resource returns the messages of the errors of a resource in the language of the request.
+	resource func(*i18n.Translation) *resourceErrors
+)
+
+var (
+	profileResource resource = func(t *i18n.Translation) *resourceErrors {
+		return &resourceErrors{notFound: t.ErrProfileNotFound, registered: t.ErrProfileRegistered, used: t.ErrProfileUsed, references: []reference{{"", t.ErrProfileNotFound}}}
+	}
+	userResource resource = func(t *i18n.Translation) *resourceErrors {
+		return &resourceErrors{notFound: t.ErrUserNotFound, registered: t.ErrUserRegistered, used: t.ErrUserUsed, references: []reference{{"", t.ErrProfileNotFound}}}
+	}
+	siteResource resource = func(t *i18n.Translation) *resourceErrors {
+		return &resourceErrors{notFound: t.ErrSiteNotFound, registered: t.ErrSiteRegistered, used: t.ErrSiteUsed, references: []reference{{"", t.ErrSiteNotFound}}}
+	}
+	operatorResource resource = func(t *i18n.Translation) *resourceErrors {
+		return &resourceErrors{notFound: t.ErrOperatorNotFound, registered: t.ErrOperatorRegistered, used: t.ErrOperatorUsed, references: []reference{{"", t.ErrOperatorNotFound}}}
+	}
+	phoneResource resource = func(t *i18n.Translation) *resourceErrors {
+		return &resourceErrors{notFound: t.ErrPhoneNotFound, registered: t.ErrPhoneRegistered, used: t.ErrPhoneUsed, references: []reference{{"", t.ErrPhoneNotFound}}}
+	}
+	accountResource resource = func(t *i18n.Translation) *resourceErrors {
+		return &resourceErrors{notFound: t.ErrAccountNotFound, registered: t.ErrAccountRegistered, used: t.ErrAccountUsed, references: []reference{{"site", t.ErrSiteNotFound}, {"phone", t.ErrPhoneNotFound}, {"", t.ErrAccountNotFound}}}
+	}
+	tokenResource resource = func(t *i18n.Translation) *resourceErrors {
+		return &resourceErrors{notFound: t.ErrTokenNotFound}
+	}
+)
+
+// passwordError localizes the password policy and reuse errors, returning nil for the other errors.
+func passwordError(messages *i18n.Translation, err error) error {
+	policyErr := &passpolicy.Error{}
+	switch {
+	case errors.As(err, &policyErr):
+		return messages.PasswordPolicy(policyErr)
+	case errors.Is(err, domain.ErrPasswordReused):
+		return messages.ErrPasswordReused
+	}
+
+	return nil
+}
+
+// handlerError answers the error of a service with the status and the message mapped from it, the database errors are
+// mapped by the messages of the resource, nil for the routes without one. The unexpected errors are logged.
+func handlerError(c *fiber.Ctx, err error, res resource) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	violations := validator.ValidationErrors{}
+	if errors.As(err, &violations) {
+		return httphelper.NewValidationResponse(c, translation.ErrInvalidFields, violations)
+	}
+
+	if message := passwordError(translation, err); message != nil {
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, message)
+	}
+
+	mapped := pgerror.HandlerError(err)
+	switch {
+	case errors.Is(err, domain.ErrInvalidUserToken):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidUserToken)
+	case errors.Is(err, domain.ErrUserHasPassword):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrUserHasPass)
+	case mapped == pgerror.ErrUndefinedColumn:
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrUndefinedColumn)
+	}
+
+	if res != nil {
+		messages := res(translation)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound) && messages.notFound != nil:
+			return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, messages.notFound)
+		case mapped == pgerror.ErrDuplicatedKey && messages.registered != nil:
+			return httphelper.NewHTTPResponse(c, fiber.StatusConflict, messages.registered)
+		case mapped == pgerror.ErrForeignKeyViolated && c.Method() == fiber.MethodDelete && messages.used != nil:
+			return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.used)
+		case mapped == pgerror.ErrForeignKeyViolated && c.Method() != fiber.MethodDelete:
+			for _, ref := range messages.references {
+				if strings.Contains(err.Error(), ref.word) {
+					return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, ref.message)
+				}
+			}
+		}
+	}
+
+	httphelper.LogError(c, err)
+	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
+}
@@ -5,15 +5,27 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/monitor"
+	"github.com/raulaguila/go-pass/internal/api/middleware"
+	"github.com/raulaguila/go-pass/pkg/health"
+	"github.com/raulaguila/go-pass/pkg/keyring"
 )
 
-type MiscHandler struct{}
+type MiscHandler struct {
+	accessKeys *keyring.Keyring
+	readiness  *health.Checker
+}
 
-func NewMiscHandler(miscRoute fiber.Router) {
-	handler := &MiscHandler{}
+func NewMiscHandler(miscRoute fiber.Router, access fiber.Handler, accessKeys *keyring.Keyring, readiness *health.Checker) {
+	handler := &MiscHandler{
+		accessKeys: accessKeys,
+		readiness:  readiness,
+	}
 
 	miscRoute.Get("", handler.healthCheck).Name("Root")
-	miscRoute.Get("/monitor", monitor.New(monitor.Config{
+	miscRoute.Get("/healthz", handler.live)
+	miscRoute.Get("/readyz", handler.ready)
+	miscRoute.Get("/.well-known/jwks.json", handler.jwks)
+	miscRoute.Get("/monitor", access, middleware.DenyPersonalToken, monitor.New(monitor.Config{
 		Title:   "Server Monitor",
 		Refresh: 5 * time.Second,
 		APIOnly: false,
@@ -32,3 +44,47 @@ func (h *MiscHandler) healthCheck(c *fiber.Ctx) error {
 		"time": time.Now(),
 	})
 }
+
+// live godoc
+// @Summary      Liveness probe
+// @Description  Reports that the process is up, without checking its dependencies
+// @Tags         Ping
+// @Produce      json
+// @Success      200  {object}   health.Report
+// @Router       /healthz [get]
+func (h *MiscHandler) live(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(&health.Report{Status: health.StatusUp})
+}
+
+// ready godoc
+// @Summary      Readiness probe
+// @Description  Reports whether the instance can serve: listening, with the database reachable and migrated and the signing keys parsed
+// @Tags         Ping
+// @Produce      json
+// @Success      200  {object}   health.Report
+// @Failure      503  {object}   health.Report
+// @Router       /readyz [get]
+func (h *MiscHandler) ready(c *fiber.Ctx) error {
+	report := h.readiness.Run(c.UserContext())
+
+	status := fiber.StatusOK
+	if !report.Up() {
+		status = fiber.StatusServiceUnavailable
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(status).JSON(report)
+}
+
+// jwks godoc
+// @Summary      Token signing keys
+// @Description  Public keys to verify the access tokens, selected by the 'kid' token header
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}   keyring.JWKSet
+// @Router       /.well-known/jwks.json [get]
+func (h *MiscHandler) jwks(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.accessKeys.JWKS())
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/encryptcookie"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/raulaguila/go-pass/pkg/oidc"
+	"gorm.io/gorm"
+)
+
+// Cookie keeping the login session between the redirect and the callback.
+const oidcCookie string = "gopass_oidc"
+
+type OIDCHandler struct {
+	authService domain.AuthService
+	provider    *oidc.Provider
+	profile     string
+	cookieKey   string
+}
+
+func (OIDCHandler) handlerError(c *fiber.Ctx, err error) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	switch {
+	case errors.Is(err, oidc.ErrInvalidSession):
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidLoginSession)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrInvalidCredentials)
+	case errors.Is(err, domain.ErrExternalUserConflict):
+		return httphelper.NewHTTPResponse(c, fiber.StatusConflict, translation.ErrExternalUserConflict)
+	}
+
+	return handlerError(c, err, nil)
+}
+
+func (s *OIDCHandler) setSession(c *fiber.Ctx, session *oidc.Session) error {
+	cookie := &fiber.Cookie{
+		Name:     oidcCookie,
+		Path:     "/auth/oidc",
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode,
+	}
+
+	if session == nil {
+		cookie.Expires = time.Unix(0, 0)
+		c.Cookie(cookie)
+		return nil
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	cookie.Value, err = encryptcookie.EncryptCookie(string(data), s.cookieKey)
+	if err != nil {
+		return err
+	}
+
+	cookie.Expires = session.Expires
+	c.Cookie(cookie)
+	return nil
+}
+
+func (s *OIDCHandler) getSession(c *fiber.Ctx) *oidc.Session {
+	data, err := encryptcookie.DecryptCookie(c.Cookies(oidcCookie), s.cookieKey)
+	if err != nil {
+		return nil
+	}
+
+	session := &oidc.Session{}
+	if err := json.Unmarshal([]byte(data), session); err != nil {
+		return nil
+	}
+
+	return session
+}
+
+// Creates a new handler.
+func NewOIDCHandler(route fiber.Router, as domain.AuthService, provider *oidc.Provider, profile, cookieKey string) {
+	handler := &OIDCHandler{
+		authService: as,
+		provider:    provider,
+		profile:     profile,
+		cookieKey:   cookieKey,
+	}
+
+	route.Get("/login", handler.login)
+	route.Get("/callback", handler.callback)
+}
+
+// login godoc
+// @Summary      OpenID Connect login
+// @Description  Redirects to the identity provider login
+// @Tags         Auth
+// @Param        lang query string false "Language responses"
+// @Success      302
+// @Failure      500  {object}  httphelper.Problem
+// @Router       /auth/oidc/login [get]
+func (s *OIDCHandler) login(c *fiber.Ctx) error {
+	authURL, session, err := s.provider.AuthURL()
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	if err := s.setSession(c, session); err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// callback godoc
+// @Summary      OpenID Connect callback
+// @Description  Validates the identity provider response and authenticates the user by email
+// @Tags         Auth
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        state query string true "Login state"
+// @Param        code query string true "Authorization code"
+// @Success      200  {object}  domain.AuthResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      401  {object}  httphelper.Problem
+// @Failure      409  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
+// @Router       /auth/oidc/callback [get]
+func (s *OIDCHandler) callback(c *fiber.Ctx) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	session := s.getSession(c)
+	if err := s.setSession(c, nil); err != nil {
+		return s.handlerError(c, err)
+	}
+
+	if reason := c.Query("error"); reason != "" {
+		slog.WarnContext(c.UserContext(), "login refused by the identity provider", "reason", reason, "description", c.Query("error_description"))
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrInvalidCredentials)
+	}
+
+	identity, err := s.provider.Exchange(c.UserContext(), session, c.Query("state"), c.Query("code"))
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidSession) {
+			return s.handlerError(c, err)
+		}
+
+		slog.WarnContext(c.UserContext(), "identity provider exchange failed", "error", err)
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrInvalidCredentials)
+	}
+
+	user, err := s.authService.GetExternalUser(c.UserContext(), &domain.ExternalIdentity{Email: identity.Email, Name: identity.Name, Profile: s.profile, Source: domain.UserSourceOIDC})
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	if !user.Status {
+		return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, translation.ErrDisabledUser)
+	}
+
+	user.Expire = true
+	authResponse, err := s.authService.Login(c.UserContext(), user, c.IP())
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	if err := authResponse.Validate(); err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(authResponse)
+}
@@ -1,64 +1,27 @@
 package handler
 
 import (
-	"errors"
-	"log"
-
 	"github.com/gofiber/fiber/v2"
 	"github.com/raulaguila/go-pass/internal/api/middleware"
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
 	"github.com/raulaguila/go-pass/internal/pkg/dto"
-	"github.com/raulaguila/go-pass/internal/pkg/i18n"
 	"github.com/raulaguila/go-pass/pkg/filter"
 	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
-	"github.com/raulaguila/go-pass/pkg/pgerror"
-	"github.com/raulaguila/go-pass/pkg/validator"
 )
 
 type OperatorHandler struct {
 	operatorService domain.OperatorService
 }
 
-func (OperatorHandler) foreignKeyViolatedMethod(c *fiber.Ctx, translation *i18n.Translation) error {
-	switch c.Method() {
-	case fiber.MethodPut, fiber.MethodPost, fiber.MethodPatch:
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrOperatorNotFound)
-	case fiber.MethodDelete:
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrOperatorUsed)
-	default:
-		return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
-	}
-}
-
-func (s OperatorHandler) handlerError(c *fiber.Ctx, err error) error {
-	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
-
-	switch pgerror.HandlerError(err) {
-	case pgerror.ErrDuplicatedKey:
-		return httphelper.NewHTTPResponse(c, fiber.StatusConflict, translation.ErrOperatorRegistered)
-	case pgerror.ErrForeignKeyViolated:
-		return s.foreignKeyViolatedMethod(c, translation)
-	case pgerror.ErrUndefinedColumn:
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrUndefinedColumn)
-	}
-
-	if errors.As(err, &validator.ErrValidator) {
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, err)
-	}
-
-	log.Println(err.Error())
-	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
-}
-
 // Creates a new handler.
-func NewOperatorHandler(route fiber.Router, ps domain.OperatorService, mid *middleware.RequesttMiddleware) {
+func NewOperatorHandler(route fiber.Router, access, list fiber.Handler, ps domain.OperatorService, mid *middleware.RequesttMiddleware) {
 	handler := &OperatorHandler{
 		operatorService: ps,
 	}
 
-	route.Use(middleware.MidAccess)
+	route.Use(access, middleware.Scoped("operator"))
 
-	route.Get("", middleware.GetGenericFilter, handler.getOperators)
+	route.Get("", list, handler.getOperators)
 	route.Post("", middleware.GetOperatorDTO, handler.createOperator)
 	route.Get("/:"+httphelper.ParamID, mid.OperatorByID, handler.getOperatorBydID)
 	route.Put("/:"+httphelper.ParamID, mid.OperatorByID, middleware.GetOperatorDTO, handler.updateOperator)
@@ -74,13 +37,13 @@ func NewOperatorHandler(route fiber.Router, ps domain.OperatorService, mid *midd
 // @Param        lang query string false "Language responses"
 // @Param        filter query filter.Filter false "Optional Filter"
 // @Success      200  {array}   dto.ItemsOutputDTO
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /operator [get]
 // @Security	 Bearer
 func (h *OperatorHandler) getOperators(c *fiber.Ctx) error {
-	response, err := h.operatorService.GetOperatorsOutputDTO(c.Context(), c.Locals(httphelper.LocalFilter).(*filter.Filter))
+	response, err := h.operatorService.GetOperatorsOutputDTO(c.UserContext(), c.Locals(httphelper.LocalFilter).(*filter.Filter))
 	if err != nil {
-		return h.handlerError(c, err)
+		return handlerError(c, err, operatorResource)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -95,9 +58,9 @@ func (h *OperatorHandler) getOperators(c *fiber.Ctx) error {
 // @Param        lang query string false "Language responses"
 // @Param        id   path			int			true        "Operator ID"
 // @Success      200  {object}  domain.Operator
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /operator/{id} [get]
 // @Security	 Bearer
 func (h *OperatorHandler) getOperatorBydID(c *fiber.Ctx) error {
@@ -113,16 +76,16 @@ func (h *OperatorHandler) getOperatorBydID(c *fiber.Ctx) error {
 // @Param        lang query string false "Language responses"
 // @Param        operator body dto.OperatorInputDTO true "Operator model"
 // @Success      201  {object}  domain.Operator
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      409  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      409  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /operator [post]
 // @Security	 Bearer
 func (h *OperatorHandler) createOperator(c *fiber.Ctx) error {
 	operatorDTO := c.Locals(httphelper.LocalDTO).(*dto.OperatorInputDTO)
-	operator, err := h.operatorService.CreateOperator(c.Context(), operatorDTO)
+	operator, err := h.operatorService.CreateOperator(c.UserContext(), operatorDTO)
 	if err != nil {
-		return h.handlerError(c, err)
+		return handlerError(c, err, operatorResource)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(operator)
@@ -138,16 +101,16 @@ func (h *OperatorHandler) createOperator(c *fiber.Ctx) error {
 // @Param        id     path    int     true        "Operator ID"
 // @Param        operator body dto.OperatorInputDTO true "Operator model"
 // @Success      200  {object}  domain.Operator
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /operator/{id} [put]
 // @Security	 Bearer
 func (h *OperatorHandler) updateOperator(c *fiber.Ctx) error {
 	operatorDTO := c.Locals(httphelper.LocalDTO).(*dto.OperatorInputDTO)
 	operator := c.Locals(httphelper.LocalObject).(*domain.Operator)
-	if err := h.operatorService.UpdateOperator(c.Context(), operator, operatorDTO); err != nil {
-		return h.handlerError(c, err)
+	if err := h.operatorService.UpdateOperator(c.UserContext(), operator, operatorDTO); err != nil {
+		return handlerError(c, err, operatorResource)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(operator)
@@ -162,14 +125,14 @@ func (h *OperatorHandler) updateOperator(c *fiber.Ctx) error {
 // @Param        lang query string false "Language responses"
 // @Param        id     path    int     true        "Operator ID"
 // @Success      204  {object}  nil
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /operator/{id} [delete]
 // @Security	 Bearer
 func (h *OperatorHandler) deleteOperator(c *fiber.Ctx) error {
 	operator := c.Locals(httphelper.LocalObject).(*domain.Operator)
-	if err := h.operatorService.DeleteOperator(c.Context(), operator); err != nil {
-		return h.handlerError(c, err)
+	if err := h.operatorService.DeleteOperator(c.UserContext(), operator); err != nil {
+		return handlerError(c, err, operatorResource)
 	}
 
 	return c.Status(fiber.StatusNoContent).Send(nil)
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/api/middleware"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/internal/pkg/metrics"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/raulaguila/go-pass/pkg/secretref"
+	"gorm.io/gorm"
+)
+
+type SecretHandler struct {
+	secretService domain.SecretService
+	metrics       *metrics.Metrics
+}
+
+func (SecretHandler) handlerError(c *fiber.Ctx, err error) error {
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+
+	var secretErr *domain.SecretError
+	if errors.As(err, &secretErr) {
+		switch secretErr.Err {
+		case secretref.ErrInvalidReference:
+			return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, fmt.Errorf("%w (%v)", translation.ErrInvalidSecretRef, secretErr.Ref))
+		case gorm.ErrRecordNotFound, domain.ErrSecretForbidden:
+			return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, fmt.Errorf("%w (%v)", translation.ErrAccountNotFound, secretErr.Ref))
+		case domain.ErrSecretAmbiguous:
+			return httphelper.NewHTTPResponse(c, fiber.StatusConflict, fmt.Errorf("%w (%v)", translation.ErrAmbiguousSecretRef, secretErr.Ref))
+		}
+	}
+
+	return handlerError(c, err, nil)
+}
+
+// Creates a new handler.
+func NewSecretHandler(route fiber.Router, access fiber.Handler, ss domain.SecretService, m *metrics.Metrics) {
+	handler := &SecretHandler{
+		secretService: ss,
+		metrics:       m,
+	}
+
+	route.Use(access, middleware.RequireScope(domain.ScopeAccountReveal))
+
+	route.Post("/resolve", middleware.GetSecretResolveDTO, handler.resolveSecrets)
+}
+
+// resolveSecrets godoc
+// @Summary      Resolve secrets
+// @Description  Resolve references like gopass://site/github/account/deploy-bot#password (fields: password, username or url), every resolution is audited
+// @Tags         Secret
+// @Accept       json
+// @Produce      json,plain
+// @Param        lang query string false "Language responses"
+// @Param        format query string false "Response format: json, dotenv or shell"
+// @Param        secrets body dto.SecretResolveInputDTO true "Secret references"
+// @Success      200  {object}  map[string][]secretref.Secret
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      403  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      409  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
+// @Router       /secrets/resolve [post]
+// @Security	 Bearer
+func (h *SecretHandler) resolveSecrets(c *fiber.Ctx) error {
+	format := c.Query("format", secretref.FormatJSON)
+	if _, err := secretref.Encode(format, nil); err != nil {
+		translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidFormat)
+	}
+
+	requester := &domain.SecretRequester{
+		User:      c.Locals(httphelper.LocalUser).(*domain.User),
+		IP:        c.IP(),
+		RequestID: fmt.Sprint(c.Locals(httphelper.LocalRequestID)),
+	}
+	requester.Token, _ = c.Locals(httphelper.LocalToken).(*domain.PersonalToken)
+
+	secrets, err := h.secretService.ResolveSecrets(c.UserContext(), c.Locals(httphelper.LocalDTO).(*dto.SecretResolveInputDTO), requester)
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	body, err := secretref.Encode(format, secrets)
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	h.metrics.Reveal(metrics.SourceResolve, len(secrets))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderContentType, secretref.ContentType(format))
+	return c.Status(fiber.StatusOK).Send(body)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/api/middleware"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+)
+
+type PersonalTokenHandler struct {
+	personalTokenService domain.PersonalTokenService
+}
+
+func (h *PersonalTokenHandler) tokenByID(c *fiber.Ctx) error {
+	id, err := c.ParamsInt(httphelper.ParamID, 0)
+	if err != nil || id < 1 {
+		translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidId)
+	}
+
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	token, err := h.personalTokenService.GetPersonalTokenByID(c.UserContext(), uint(id), user.Id)
+	if err != nil {
+		return handlerError(c, err, tokenResource)
+	}
+
+	c.Locals(httphelper.LocalObject, token)
+	return c.Next()
+}
+
+// Creates a new handler.
+func NewPersonalTokenHandler(route fiber.Router, access fiber.Handler, ts domain.PersonalTokenService) {
+	handler := &PersonalTokenHandler{
+		personalTokenService: ts,
+	}
+
+	route.Use(access, middleware.DenyPersonalToken)
+
+	route.Get("", handler.getTokens)
+	route.Post("", middleware.GetPersonalTokenDTO, handler.createToken)
+	route.Delete("/:"+httphelper.ParamID, handler.tokenByID, handler.deleteToken)
+}
+
+// getTokens godoc
+// @Summary      Get personal tokens
+// @Description  Get the personal tokens of the authenticated user
+// @Tags         Token
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Success      200  {array}   dto.ItemsOutputDTO
+// @Failure      403  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
+// @Router       /token [get]
+// @Security	 Bearer
+func (h *PersonalTokenHandler) getTokens(c *fiber.Ctx) error {
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	response, err := h.personalTokenService.GetPersonalTokensOutputDTO(c.UserContext(), user.Id)
+	if err != nil {
+		return handlerError(c, err, tokenResource)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// createToken godoc
+// @Summary      Insert personal token
+// @Description  Insert personal token, the token value is only returned in this response
+// @Tags         Token
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        token body dto.PersonalTokenInputDTO true "Personal token model"
+// @Success      201  {object}  domain.PersonalTokenResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      403  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
+// @Router       /token [post]
+// @Security	 Bearer
+func (h *PersonalTokenHandler) createToken(c *fiber.Ctx) error {
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	tokenDTO := c.Locals(httphelper.LocalDTO).(*dto.PersonalTokenInputDTO)
+	token, err := h.personalTokenService.CreatePersonalToken(c.UserContext(), tokenDTO, user.Id)
+	if err != nil {
+		return handlerError(c, err, tokenResource)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(token)
+}
+
+// deleteToken godoc
+// @Summary      Revoke personal token
+// @Description  Revoke personal token by ID
+// @Tags         Token
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "Token ID"
+// @Success      204  {object}  nil
+// @Failure      403  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
+// @Router       /token/{id} [delete]
+// @Security	 Bearer
+func (h *PersonalTokenHandler) deleteToken(c *fiber.Ctx) error {
+	if err := h.personalTokenService.DeletePersonalToken(c.UserContext(), c.Locals(httphelper.LocalObject).(*domain.PersonalToken)); err != nil {
+		return handlerError(c, err, tokenResource)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
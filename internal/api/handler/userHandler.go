@@ -1,8 +1,7 @@
 package handler
 
 import (
-	"errors"
-	"log"
+	"log/slog"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -12,81 +11,38 @@ import (
 	"github.com/raulaguila/go-pass/internal/pkg/i18n"
 	"github.com/raulaguila/go-pass/pkg/filter"
 	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
-	"github.com/raulaguila/go-pass/pkg/pgerror"
-	"github.com/raulaguila/go-pass/pkg/validator"
-	"gorm.io/gorm"
 )
 
 type UserHandler struct {
-	userService domain.UserService
-}
-
-func (s UserHandler) foreignKeyViolatedFrom(c *fiber.Ctx, messages *i18n.Translation) error {
-	switch c.Method() {
-	case fiber.MethodPut, fiber.MethodPost, fiber.MethodPatch:
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrProfileNotFound)
-	case fiber.MethodDelete:
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrUserUsed)
-	default:
-		return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, messages.ErrGeneric)
-	}
-}
-
-func (s UserHandler) handlerError(c *fiber.Ctx, err error) error {
-	messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
-
-	switch pgerror.HandlerError(err) {
-	case pgerror.ErrDuplicatedKey:
-		return httphelper.NewHTTPResponse(c, fiber.StatusConflict, messages.ErrUserRegistered)
-	case pgerror.ErrForeignKeyViolated:
-		return s.foreignKeyViolatedFrom(c, messages)
-	case pgerror.ErrUndefinedColumn:
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrUndefinedColumn)
-	}
-
-	if errors.As(err, &validator.ErrValidator) {
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, err)
-	}
-
-	log.Println(err.Error())
-	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, messages.ErrGeneric)
-}
-
-func (h *UserHandler) existUserByEmail(c *fiber.Ctx) error {
-	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
-
-	mail := strings.ReplaceAll(c.Params(httphelper.ParamMail), "%40", "@")
-	user, err := h.userService.GetUserByMail(c.Context(), mail)
-	if err != nil {
-		switch err {
-		case gorm.ErrRecordNotFound:
-			return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, translation.ErrUserNotFound)
-		default:
-			log.Println(err.Error())
-			return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
-		}
-	}
-
-	c.Locals(httphelper.LocalObject, user)
-	return c.Next()
+	userService      domain.UserService
+	userTokenService domain.UserTokenService
 }
 
 // Creates a new handler.
-func NewUserHandler(route fiber.Router, us domain.UserService, mid *middleware.RequesttMiddleware) {
+func NewUserHandler(route fiber.Router, access, list fiber.Handler, us domain.UserService, uts domain.UserTokenService, mid *middleware.RequesttMiddleware) {
 	handler := &UserHandler{
-		userService: us,
+		userService:      us,
+		userTokenService: uts,
 	}
 
-	route.Patch("/:"+httphelper.ParamMail+"/passw", handler.existUserByEmail, middleware.GetPasswordInputDTO, handler.passwordUser)
+	route.Patch("/:"+httphelper.ParamMail+"/passw", middleware.GetPasswordInputDTO, handler.passwordUser)
 
-	route.Use(middleware.MidAccess)
+	route.Use(access, middleware.Scoped("user"))
 
-	route.Get("", middleware.GetUserFilter, handler.getUsers)
+	route.Get("", list, handler.getUsers)
 	route.Post("", middleware.GetUserDTO, handler.createUser)
 	route.Get("/:"+httphelper.ParamID, mid.UserByID, handler.getUser)
 	route.Put("/:"+httphelper.ParamID, mid.UserByID, middleware.GetUserDTO, handler.updateUser)
 	route.Delete("/:"+httphelper.ParamID, mid.UserByID, handler.deleteUser)
 	route.Patch("/:"+httphelper.ParamID+"/reset", mid.UserByID, handler.resetUser)
+	route.Post("/:"+httphelper.ParamID+"/invite", mid.UserByID, handler.inviteUser)
+}
+
+// sendInvite emails the invitation, a failure is logged since the invitation can be sent again.
+func (h *UserHandler) sendInvite(c *fiber.Ctx, user *domain.User) {
+	if err := h.userTokenService.InviteUser(c.UserContext(), user, c.Locals(httphelper.LocalLang).(*i18n.Translation)); err != nil {
+		slog.ErrorContext(c.UserContext(), "could not invite user", "invited_id", user.Id, "error", err)
+	}
 }
 
 // getUsers godoc
@@ -98,13 +54,13 @@ func NewUserHandler(route fiber.Router, us domain.UserService, mid *middleware.R
 // @Param        lang query string false "Language responses"
 // @Param        filter query filter.UserFilter false "Optional Filter"
 // @Success      200  {array}   dto.ItemsOutputDTO
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /user [get]
 // @Security	 Bearer
 func (h *UserHandler) getUsers(c *fiber.Ctx) error {
-	response, err := h.userService.GetUsersOutputDTO(c.Context(), c.Locals(httphelper.LocalFilter).(*filter.UserFilter))
+	response, err := h.userService.GetUsersOutputDTO(c.UserContext(), c.Locals(httphelper.LocalFilter).(*filter.UserFilter))
 	if err != nil {
-		return h.handlerError(c, err)
+		return handlerError(c, err, userResource)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -112,25 +68,26 @@ func (h *UserHandler) getUsers(c *fiber.Ctx) error {
 
 // createUser godoc
 // @Summary      Insert user
-// @Description  Insert user
+// @Description  Insert user and email the invitation to set the password
 // @Tags         User
 // @Accept       json
 // @Produce      json
 // @Param        lang query string false "Language responses"
 // @Param        user body dto.UserInputDTO true "User model"
 // @Success      201  {object}  domain.User
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      409  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      409  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /user [post]
 // @Security	 Bearer
 func (h *UserHandler) createUser(c *fiber.Ctx) error {
 	userDTO := c.Locals(httphelper.LocalDTO).(*dto.UserInputDTO)
-	user, err := h.userService.CreateUser(c.Context(), userDTO)
+	user, err := h.userService.CreateUser(c.UserContext(), userDTO)
 	if err != nil {
-		return h.handlerError(c, err)
+		return handlerError(c, err, userResource)
 	}
 
+	h.sendInvite(c, user)
 	return c.Status(fiber.StatusCreated).JSON(user)
 }
 
@@ -143,9 +100,9 @@ func (h *UserHandler) createUser(c *fiber.Ctx) error {
 // @Param        lang query string false "Language responses"
 // @Param        id     path    int     true        "User ID"
 // @Success      200  {object}  domain.User
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /user/{id} [get]
 // @Security	 Bearer
 func (h *UserHandler) getUser(c *fiber.Ctx) error {
@@ -162,21 +119,21 @@ func (h *UserHandler) getUser(c *fiber.Ctx) error {
 // @Param        id     path    int     true        "User ID"
 // @Param        user body dto.UserInputDTO true "User model"
 // @Success      200  {object}  domain.User
-// @Failure      400  {object}  httphelper.HTTPResponse
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /user/{id} [put]
 // @Security	 Bearer
 func (h *UserHandler) updateUser(c *fiber.Ctx) error {
 	userDTO := c.Locals(httphelper.LocalDTO).(*dto.UserInputDTO)
 	user := c.Locals(httphelper.LocalObject).(*domain.User)
-	if err := h.userService.UpdateUser(c.Context(), user, userDTO); err != nil {
-		return h.handlerError(c, err)
+	if err := h.userService.UpdateUser(c.UserContext(), user, userDTO); err != nil {
+		return handlerError(c, err, userResource)
 	}
 
-	updated, err := h.userService.GetUserByID(c.Context(), user.Id)
+	updated, err := h.userService.GetUserByID(c.UserContext(), user.Id)
 	if err != nil {
-		return h.handlerError(c, err)
+		return handlerError(c, err, userResource)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(updated)
@@ -191,13 +148,13 @@ func (h *UserHandler) updateUser(c *fiber.Ctx) error {
 // @Param        lang query string false "Language responses"
 // @Param        id     path    int     true        "User ID"
 // @Success      204  {object}  nil
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /user/{id} [delete]
 // @Security	 Bearer
 func (h *UserHandler) deleteUser(c *fiber.Ctx) error {
-	if err := h.userService.DeleteUser(c.Context(), c.Locals(httphelper.LocalObject).(*domain.User)); err != nil {
-		return h.handlerError(c, err)
+	if err := h.userService.DeleteUser(c.UserContext(), c.Locals(httphelper.LocalObject).(*domain.User)); err != nil {
+		return handlerError(c, err, userResource)
 	}
 
 	return c.Status(fiber.StatusNoContent).Send(nil)
@@ -205,37 +162,65 @@ func (h *UserHandler) deleteUser(c *fiber.Ctx) error {
 
 // resetUser godoc
 // @Summary      Reset user password
-// @Description  Reset user password by ID
+// @Description  Reset user password by ID and email a new invitation
 // @Tags         User
 // @Accept       json
 // @Produce      json
 // @Param        lang query string false "Language responses"
 // @Param        id     path    int     true        "User ID"
 // @Success      200  {object}  domain.User
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /user/{id}/reset [patch]
 // @Security	 Bearer
 func (h *UserHandler) resetUser(c *fiber.Ctx) error {
 	user := c.Locals(httphelper.LocalObject).(*domain.User)
 
 	if !user.New {
-		if err := h.userService.ResetUser(c.Context(), user); err != nil {
-			return h.handlerError(c, err)
+		if err := h.userService.ResetUser(c.UserContext(), user); err != nil {
+			return handlerError(c, err, userResource)
 		}
-		updated, err := h.userService.GetUserByID(c.Context(), user.Id)
+		updated, err := h.userService.GetUserByID(c.UserContext(), user.Id)
 		if err != nil {
-			return h.handlerError(c, err)
+			return handlerError(c, err, userResource)
 		}
+		h.sendInvite(c, updated)
 		return c.Status(fiber.StatusOK).JSON(updated)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(user)
 }
 
+// inviteUser godoc
+// @Summary      Invite user
+// @Description  Email a new invitation to a user without password, invalidating the previous one
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        lang query string false "Language responses"
+// @Param        id     path    int     true        "User ID"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      404  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
+// @Router       /user/{id}/invite [post]
+// @Security	 Bearer
+func (h *UserHandler) inviteUser(c *fiber.Ctx) error {
+	user := c.Locals(httphelper.LocalObject).(*domain.User)
+	if !user.New {
+		return handlerError(c, domain.ErrUserHasPassword, userResource)
+	}
+
+	if err := h.userTokenService.InviteUser(c.UserContext(), user, c.Locals(httphelper.LocalLang).(*i18n.Translation)); err != nil {
+		return handlerError(c, err, userResource)
+	}
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
 // passwordUser godoc
 // @Summary      Set user password
-// @Description  Set user password by ID
+// @Description  Set the password of an invited user, using the token of the invitation
 // @Tags         User
 // @Accept       json
 // @Produce      json
@@ -243,8 +228,8 @@ func (h *UserHandler) resetUser(c *fiber.Ctx) error {
 // @Param        email     path    string     true        "User email"
 // @Param        password body dto.PasswordInputDTO true "Password model"
 // @Success      200  {object}  domain.User
-// @Failure      404  {object}  httphelper.HTTPResponse
-// @Failure      500  {object}  httphelper.HTTPResponse
+// @Failure      400  {object}  httphelper.Problem
+// @Failure      500  {object}  httphelper.Problem
 // @Router       /user/{email}/passw [patch]
 func (h *UserHandler) passwordUser(c *fiber.Ctx) error {
 	pass := c.Locals(httphelper.LocalDTO).(*dto.PasswordInputDTO)
@@ -253,20 +238,15 @@ func (h *UserHandler) passwordUser(c *fiber.Ctx) error {
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrPassUnmatch)
 	}
 
-	user := c.Locals(httphelper.LocalObject).(*domain.User)
-	if !user.New {
-		messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
-		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrUserHasPass)
+	if pass.Token == nil {
+		return handlerError(c, domain.ErrInvalidUserToken, userResource)
 	}
 
-	if err := h.userService.PasswordUser(c.Context(), user, pass); err != nil {
-		return h.handlerError(c, err)
-	}
-
-	updated, err := h.userService.GetUserByID(c.Context(), user.Id)
+	mail := strings.ReplaceAll(c.Params(httphelper.ParamMail), "%40", "@")
+	user, err := h.userTokenService.AcceptInvite(c.UserContext(), *pass.Token, mail, pass)
 	if err != nil {
-		return h.handlerError(c, err)
+		return handlerError(c, err, userResource)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(updated)
+	return c.Status(fiber.StatusOK).JSON(user)
 }
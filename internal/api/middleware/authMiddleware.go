@@ -1,19 +1,39 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/keyauth"
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
 	"github.com/raulaguila/go-pass/internal/pkg/i18n"
 	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/raulaguila/go-pass/pkg/logging"
 )
 
-var (
-	MidAccess  fiber.Handler
-	MidRefresh fiber.Handler
-)
+// Set by AllowPasswordChange, for the routes reachable by users that must change the password.
+const localPasswordChange string = "localPasswordChange"
+
+// AllowPasswordChange must precede the Auth middleware on the routes needed to change the password.
+func AllowPasswordChange(c *fiber.Ctx) error {
+	c.Locals(localPasswordChange, true)
+	return c.Next()
+}
+
+func tokenError(err error, translation *i18n.Translation) error {
+	switch err {
+	case domain.ErrInvalidIpAssociation:
+		return translation.ErrInvalidIpAssociation
+	case domain.ErrDisabledUser:
+		return translation.ErrDisabledUser
+	default:
+		return translation.ErrExpiredToken
+	}
+}
 
-func Auth(base64key string, ar domain.AuthService) fiber.Handler {
+// Auth validates the JWT signed by the keyring, personal tokens are also accepted when the service is given.
+func Auth(keys *keyring.Keyring, ar domain.AuthService, ts domain.PersonalTokenService) fiber.Handler {
 	return keyauth.New(keyauth.Config{
 		KeyLookup:  "header:" + fiber.HeaderAuthorization,
 		AuthScheme: "Bearer",
@@ -24,26 +44,39 @@ func Auth(base64key string, ar domain.AuthService) fiber.Handler {
 			return false
 		},
 		SuccessHandler: func(c *fiber.Ctx) error {
+			user := c.Locals(httphelper.LocalUser).(*domain.User)
+			if user.ChangePassword && c.Locals(localPasswordChange) == nil {
+				return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, c.Locals(httphelper.LocalLang).(*i18n.Translation).ErrPasswordChange)
+			}
 			return c.Next()
 		},
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			return httphelper.NewHTTPResponse(c, fiber.StatusUnauthorized, err)
 		},
 		Validator: func(c *fiber.Ctx, key string) (bool, error) {
-			user, err := ar.Me(c.Context(), key, base64key, c.IP())
+			if ts != nil && strings.HasPrefix(key, domain.PersonalTokenPrefix) {
+				token, err := ts.Authenticate(c.UserContext(), key, c.IP())
+				if err != nil {
+					return false, tokenError(err, c.Locals(httphelper.LocalLang).(*i18n.Translation))
+				}
+				c.Locals(httphelper.LocalUser, token.User)
+				c.Locals(httphelper.LocalToken, token)
+				userLanguage(c, token.User)
+				c.SetUserContext(logging.With(c.UserContext(), "user_id", token.User.Id))
+				return true, nil
+			}
+
+			user, err := ar.Me(c.UserContext(), key, keys, c.IP())
 			if err != nil || !user.Status {
 				translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
 				if err != nil {
-					switch err {
-					case domain.ErrInvalidIpAssociation:
-						return false, translation.ErrInvalidIpAssociation
-					default:
-						return false, translation.ErrExpiredToken
-					}
+					return false, tokenError(err, translation)
 				}
 				return false, translation.ErrDisabledUser
 			}
 			c.Locals(httphelper.LocalUser, user)
+			userLanguage(c, user)
+			c.SetUserContext(logging.With(c.UserContext(), "user_id", user.Id))
 			return true, nil
 		},
 	})
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	myi18n "github.com/raulaguila/go-pass/internal/pkg/i18n"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+// authService authenticates every token as the user.
+type authService struct {
+	domain.AuthService
+	user *domain.User
+}
+
+func (s *authService) Me(context.Context, string, *keyring.Keyring, string) (*domain.User, error) {
+	return s.user, nil
+}
+
+// go test -run TestAuthPasswordChange
+func TestAuthPasswordChange(t *testing.T) {
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
+	if _, err := bundle.LoadMessageFile("../../../configs/i18n/active.en.toml"); !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	translation := myi18n.NewTranslation("en", i18n.NewLocalizer(bundle, "en"))
+
+	user := &domain.User{Status: true, ChangePassword: true}
+	auth := Auth(nil, &authService{user: user}, nil)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(httphelper.LocalLang, translation)
+		return c.Next()
+	})
+	app.Get("/route", auth, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Get("/password", AllowPasswordChange, auth, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	status := func(path string) int {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer token")
+		resp, err := app.Test(req, -1)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Until the password is changed, only the routes allowing the change are reachable.
+	assert.Equal(t, http.StatusForbidden, status("/route"))
+	assert.Equal(t, http.StatusOK, status("/password"))
+
+	user.ChangePassword = false
+	assert.Equal(t, http.StatusOK, status("/route"))
+}
@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/raulaguila/go-pass/internal/pkg/dto"
@@ -11,7 +11,7 @@ import (
 
 func getDTO(c *fiber.Ctx, dto interface{}) error {
 	if err := c.BodyParser(dto); err != nil {
-		log.Println(err.Error())
+		slog.DebugContext(c.UserContext(), "invalid body", "error", err)
 		messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidDatas)
 	}
@@ -47,3 +47,15 @@ func GetAccountDTO(c *fiber.Ctx) error {
 func GetPasswordInputDTO(c *fiber.Ctx) error {
 	return getDTO(c, &dto.PasswordInputDTO{})
 }
+
+func GetForgotInputDTO(c *fiber.Ctx) error {
+	return getDTO(c, &dto.ForgotInputDTO{})
+}
+
+func GetPersonalTokenDTO(c *fiber.Ctx) error {
+	return getDTO(c, &dto.PersonalTokenInputDTO{})
+}
+
+func GetSecretResolveDTO(c *fiber.Ctx) error {
+	return getDTO(c, &dto.SecretResolveInputDTO{})
+}
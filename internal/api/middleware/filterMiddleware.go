@@ -1,7 +1,7 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/raulaguila/go-pass/internal/pkg/i18n"
@@ -11,7 +11,7 @@ import (
 
 func getQuery(c *fiber.Ctx, data interface{}) error {
 	if err := c.QueryParser(data); err != nil {
-		log.Println(err.Error())
+		slog.DebugContext(c.UserContext(), "invalid query", "error", err)
 		messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, messages.ErrInvalidDatas)
 	}
@@ -20,13 +20,29 @@ func getQuery(c *fiber.Ctx, data interface{}) error {
 	return c.Next()
 }
 
-func GetGenericFilter(c *fiber.Ctx) error {
-	return getQuery(c, filter.NewFilter())
+// GenericFilter parses the list query, sorted by default by the sort column in the order.
+func GenericFilter(sort, order string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return getQuery(c, filter.NewFilter(sort, order))
+	}
 }
 
-func GetUserFilter(c *fiber.Ctx) error {
-	return getQuery(c, &filter.UserFilter{
-		Filter:    *filter.NewFilter(),
-		ProfileID: 0,
-	})
+// UserFilter parses the user list query, sorted by default by the sort column in the order.
+func UserFilter(sort, order string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return getQuery(c, &filter.UserFilter{
+			Filter:    *filter.NewFilter(sort, order),
+			ProfileID: 0,
+		})
+	}
+}
+
+// AccountFilter parses the account list query, sorted by default by the sort column in the order.
+func AccountFilter(sort, order string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return getQuery(c, &filter.AccountFilter{
+			Filter:  *filter.NewFilter(sort, order),
+			SiteIDs: []uint{},
+		})
+	}
 }
@@ -1,22 +1,46 @@
 package middleware
 
 import (
-	"os"
-	"slices"
-	"strings"
-
 	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
 	"github.com/raulaguila/go-pass/internal/pkg/i18n"
 	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
 )
 
-func GetRequestLanguage(c *fiber.Ctx) error {
-	lang := strings.ToLower(c.Query("lang", os.Getenv("SYS_LANGUAGE")))[:2]
+// Set by RequestLanguage when the request does not choose a language, for the Auth middleware to apply the preferred
+// language of the user.
+const localLanguageMatcher string = "localLanguageMatcher"
+
+// RequestLanguage sets the translation negotiated from the 'lang' query, then from the Accept-Language header, falling
+// back to the preferred language of the authenticated user and to the default language.
+func RequestLanguage(defaultLang string, languages []string) fiber.Handler {
+	matcher := i18n.NewMatcher(languages)
+
+	return func(c *fiber.Ctx) error {
+		c.Vary(fiber.HeaderAcceptLanguage)
+
+		lang, ok := matcher.Match(c.Query("lang"))
+		if !ok {
+			lang, ok = matcher.Match(c.Get(fiber.HeaderAcceptLanguage))
+		}
+		if !ok {
+			lang = defaultLang
+			c.Locals(localLanguageMatcher, matcher)
+		}
 
-	if !slices.Contains(strings.Split(os.Getenv("SYS_LANGUAGES"), ","), lang) {
-		lang = os.Getenv("SYS_LANGUAGE")
+		c.Locals(httphelper.LocalLang, i18n.I18nTranslations[lang])
+		return c.Next()
+	}
+}
+
+// userLanguage sets the translation of the preferred language of the user, unless the request chose one.
+func userLanguage(c *fiber.Ctx, user *domain.User) {
+	matcher, ok := c.Locals(localLanguageMatcher).(*i18n.Matcher)
+	if !ok || user.Language == "" {
+		return
 	}
 
-	c.Locals(httphelper.LocalLang, i18n.I18nTranslations[lang])
-	return c.Next()
+	if lang, ok := matcher.Match(user.Language); ok {
+		c.Locals(httphelper.LocalLang, i18n.I18nTranslations[lang])
+	}
 }
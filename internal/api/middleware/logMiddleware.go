@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/raulaguila/go-pass/pkg/logging"
+)
+
+// Logger adds the request id to the context of the request, so every record logged with it carries the id, and logs the
+// requests with their route, status and latency when access is true. It must follow the requestid middleware.
+func Logger(access bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		requestID, _ := c.Locals(httphelper.LocalRequestID).(string)
+		c.SetUserContext(logging.With(c.UserContext(), "request_id", utils.CopyString(requestID)))
+
+		err := c.Next()
+		if !access {
+			return err
+		}
+
+		status := responseStatus(c, err)
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		slog.LogAttrs(c.UserContext(), level, "request",
+			slog.String("method", c.Method()),
+			slog.String("route", c.Route().Path),
+			slog.String("path", c.Path()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("ip", c.IP()),
+		)
+		return err
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/raulaguila/go-pass/internal/pkg/metrics"
+)
+
+// responseStatus returns the status of the response, the errors returned are written by the error handler after the middlewares.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	if fiberErr := (*fiber.Error)(nil); errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// Metrics records the count and the latency of the requests by their registered route.
+func Metrics(m *metrics.Metrics) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		// The method is backed by the request buffer, reused by fiber, and would corrupt the label kept by the collector.
+		m.Request(utils.CopyString(c.Method()), c.Route().Path, responseStatus(c, err), time.Since(start))
+		return err
+	}
+}
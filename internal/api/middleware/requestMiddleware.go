@@ -1,26 +1,35 @@
 package middleware
 
 import (
+	"context"
 	"errors"
-	"log"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
 	"github.com/raulaguila/go-pass/internal/pkg/i18n"
-	"github.com/raulaguila/go-pass/internal/pkg/postgre"
 	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
-func NewRequesttMiddleware(postgres *gorm.DB) *RequesttMiddleware {
+func NewRequesttMiddleware(profileService domain.ProfileService, userService domain.UserService, siteService domain.SiteService, operatorService domain.OperatorService, phoneService domain.PhoneService, accountService domain.AccountService) *RequesttMiddleware {
 	return &RequesttMiddleware{
-		postgres: postgres,
+		profileService:  profileService,
+		userService:     userService,
+		siteService:     siteService,
+		operatorService: operatorService,
+		phoneService:    phoneService,
+		accountService:  accountService,
 	}
 }
 
+// RequesttMiddleware loads the items of the routes by ID through the services, so it works over any repository.
 type RequesttMiddleware struct {
-	postgres *gorm.DB
+	profileService  domain.ProfileService
+	userService     domain.UserService
+	siteService     domain.SiteService
+	operatorService domain.OperatorService
+	phoneService    domain.PhoneService
+	accountService  domain.AccountService
 }
 
 var ErrInvalidID error = errors.New("invalid id")
@@ -31,7 +40,7 @@ func (RequesttMiddleware) handlerError(c *fiber.Ctx, err error, translation *i18
 		return httphelper.NewHTTPResponse(c, fiber.StatusBadRequest, translation.ErrInvalidId)
 	}
 
-	log.Println(err.Error())
+	httphelper.LogError(c, err)
 	return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, translation.ErrGeneric)
 }
 
@@ -59,19 +68,15 @@ func (s RequesttMiddleware) handlerDBError(c *fiber.Ctx, err error, item string)
 	return s.handlerError(c, err, translation)
 }
 
-func (s *RequesttMiddleware) itemByID(c *fiber.Ctx, item interface{}, itemType string, preload ...string) error {
+func (s *RequesttMiddleware) itemByID(c *fiber.Ctx, itemType string, find func(context.Context, uint) (interface{}, error)) error {
 	id, err := c.ParamsInt(httphelper.ParamID, 0)
 	if err != nil || id < 1 {
 		translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
 		return s.handlerError(c, ErrInvalidID, translation)
 	}
 
-	postgres := s.postgres.WithContext(c.Context())
-	for _, pre := range preload {
-		postgres = postgres.Preload(pre)
-	}
-
-	if err := postgres.First(item, id).Error; err != nil {
+	item, err := find(c.UserContext(), uint(id))
+	if err != nil {
 		return s.handlerDBError(c, err, itemType)
 	}
 
@@ -80,25 +85,38 @@ func (s *RequesttMiddleware) itemByID(c *fiber.Ctx, item interface{}, itemType s
 }
 
 func (s *RequesttMiddleware) ProfileByID(c *fiber.Ctx) error {
-	return s.itemByID(c, &domain.Profile{}, domain.ProfileTableName, clause.Associations)
+	return s.itemByID(c, domain.ProfileTableName, func(ctx context.Context, id uint) (interface{}, error) {
+		return s.profileService.GetProfileByID(ctx, id)
+	})
 }
 
 func (s *RequesttMiddleware) UserByID(c *fiber.Ctx) error {
-	return s.itemByID(c, &domain.User{}, domain.UserTableName, postgre.ProfilePermission)
+	return s.itemByID(c, domain.UserTableName, func(ctx context.Context, id uint) (interface{}, error) {
+		return s.userService.GetUserByID(ctx, id)
+	})
 }
 
 func (s *RequesttMiddleware) SiteByID(c *fiber.Ctx) error {
-	return s.itemByID(c, &domain.Site{}, domain.SiteTableName)
+	return s.itemByID(c, domain.SiteTableName, func(ctx context.Context, id uint) (interface{}, error) {
+		return s.siteService.GetSiteByID(ctx, id)
+	})
 }
 
 func (s *RequesttMiddleware) OperatorByID(c *fiber.Ctx) error {
-	return s.itemByID(c, &domain.Operator{}, domain.OperatorTableName)
+	return s.itemByID(c, domain.OperatorTableName, func(ctx context.Context, id uint) (interface{}, error) {
+		return s.operatorService.GetOperatorByID(ctx, id)
+	})
 }
 
 func (s *RequesttMiddleware) PhoneByID(c *fiber.Ctx) error {
-	return s.itemByID(c, &domain.Phone{}, domain.PhoneTableName, postgre.Operator, postgre.Accounts)
+	return s.itemByID(c, domain.PhoneTableName, func(ctx context.Context, id uint) (interface{}, error) {
+		return s.phoneService.GetPhoneByID(ctx, id)
+	})
 }
 
 func (s *RequesttMiddleware) AccountByID(c *fiber.Ctx) error {
-	return s.itemByID(c, &domain.Account{}, domain.AccountTableName, postgre.Site, postgre.Mail, postgre.PhoneOperator)
+	user := c.Locals(httphelper.LocalUser).(*domain.User)
+	return s.itemByID(c, domain.AccountTableName, func(ctx context.Context, id uint) (interface{}, error) {
+		return s.accountService.GetAccountByID(ctx, id, user.Id)
+	})
 }
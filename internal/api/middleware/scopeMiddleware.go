@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/pkg/filter"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+)
+
+func personalToken(c *fiber.Ctx) *domain.PersonalToken {
+	token, _ := c.Locals(httphelper.LocalToken).(*domain.PersonalToken)
+	return token
+}
+
+func checkScope(c *fiber.Ctx, scope string) error {
+	if token := personalToken(c); token != nil && !token.HasScope(scope) {
+		translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, translation.ErrTokenScope)
+	}
+
+	return c.Next()
+}
+
+// RequireScope rejects personal tokens without the scope, user sessions have every scope.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return checkScope(c, scope)
+	}
+}
+
+// Scoped requires the resource read scope on GET requests and the write scope otherwise.
+func Scoped(resource string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead:
+			return checkScope(c, resource+":read")
+		default:
+			return checkScope(c, resource+":write")
+		}
+	}
+}
+
+// DenyPersonalToken restricts the route to user sessions.
+func DenyPersonalToken(c *fiber.Ctx) error {
+	if personalToken(c) != nil {
+		translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, translation.ErrTokenScope)
+	}
+
+	return c.Next()
+}
+
+// RestrictAccountSites limits filters, accounts and account datas to the sites allowed to the personal token.
+func RestrictAccountSites(c *fiber.Ctx) error {
+	token := personalToken(c)
+	if token == nil || len(token.SiteIDs) == 0 {
+		return c.Next()
+	}
+
+	translation := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+	if accountFilter, ok := c.Locals(httphelper.LocalFilter).(*filter.AccountFilter); ok {
+		if len(accountFilter.SiteIDs) == 0 {
+			accountFilter.SiteIDs = token.SiteIDs
+		} else {
+			accountFilter.SiteIDs = slices.DeleteFunc(accountFilter.SiteIDs, func(siteID uint) bool {
+				return !token.AllowsSite(siteID)
+			})
+			if len(accountFilter.SiteIDs) == 0 {
+				// No site with id 0, nothing is listed.
+				accountFilter.SiteIDs = []uint{0}
+			}
+		}
+	}
+
+	if account, ok := c.Locals(httphelper.LocalObject).(*domain.Account); ok && !token.AllowsSite(account.SiteID) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, translation.ErrAccountNotFound)
+	}
+
+	if accountDTO, ok := c.Locals(httphelper.LocalDTO).(*dto.AccountInputDTO); ok && accountDTO.SiteID != nil && !token.AllowsSite(*accountDTO.SiteID) {
+		return httphelper.NewHTTPResponse(c, fiber.StatusForbidden, translation.ErrTokenScope)
+	}
+
+	return c.Next()
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/raulaguila/go-pass/pkg/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName string = "github.com/raulaguila/go-pass"
+
+// headerCarrier reads and writes the propagation headers, like the W3C traceparent, of a request.
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := []string{}
+	h.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+// Trace starts the span of the request in its context, continuing the trace of the incoming propagation headers, so
+// the services and the repositories create child spans. The trace id is also added to the logs of the request.
+func Trace(c *fiber.Ctx) error {
+	ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), headerCarrier{c})
+	ctx, span := otel.Tracer(tracerName).Start(ctx, utils.CopyString(c.Method()),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(utils.CopyString(c.Method())),
+			semconv.URLPath(utils.CopyString(c.Path())),
+			semconv.ClientAddress(c.IP()),
+		),
+	)
+	defer span.End()
+
+	if span.SpanContext().IsValid() {
+		ctx = logging.With(ctx, "trace_id", span.SpanContext().TraceID().String())
+	}
+	c.SetUserContext(ctx)
+
+	err := c.Next()
+
+	// The route is only known once the request reached its handler.
+	status := responseStatus(c, err)
+	span.SetName(c.Method() + " " + c.Route().Path)
+	span.SetAttributes(semconv.HTTPRoute(c.Route().Path), semconv.HTTPResponseStatusCode(status))
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, utils.StatusMessage(status))
+		if err != nil {
+			span.RecordError(err)
+		}
+	}
+
+	return err
+}
@@ -24,7 +24,7 @@ func (s *accountService) GetAccountByID(ctx context.Context, accountID, userID u
 }
 
 // Implementation of 'GetAccountsOutputDTO'.
-func (s *accountService) GetAccountsOutputDTO(ctx context.Context, filter *filter.Filter, userID uint) (*dto.ItemsOutputDTO, error) {
+func (s *accountService) GetAccountsOutputDTO(ctx context.Context, filter *filter.AccountFilter, userID uint) (*dto.ItemsOutputDTO, error) {
 	return s.accountRepository.GetAccountsOutputDTO(ctx, filter, userID)
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/pkg/keyring"
 )
 
 func NewAuthService(r domain.AuthRepository) domain.AuthService {
@@ -20,8 +21,8 @@ func (s *authService) Login(ctx context.Context, user *domain.User, ip string) (
 	return s.authRepository.Login(ctx, user, ip)
 }
 
-func (s *authService) Me(ctx context.Context, userToken, base64Key string, ip string) (*domain.User, error) {
-	return s.authRepository.Me(ctx, userToken, base64Key, ip)
+func (s *authService) Me(ctx context.Context, userToken string, keys *keyring.Keyring, ip string) (*domain.User, error) {
+	return s.authRepository.Me(ctx, userToken, keys, ip)
 }
 
 func (s *authService) Refresh(ctx context.Context, user *domain.User, ip string) (*domain.TokensResponse, error) {
@@ -31,3 +32,15 @@ func (s *authService) Refresh(ctx context.Context, user *domain.User, ip string)
 func (s *authService) GetUserByMail(ctx context.Context, email string) (*domain.User, error) {
 	return s.authRepository.GetUserByMail(ctx, email)
 }
+
+func (s *authService) GetExternalUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	return s.authRepository.GetExternalUser(ctx, identity)
+}
+
+func (s *authService) DirectoryLogin(ctx context.Context, login, password string) (*domain.User, error) {
+	return s.authRepository.DirectoryLogin(ctx, login, password)
+}
+
+func (s *authService) SyncDirectory(ctx context.Context) error {
+	return s.authRepository.SyncDirectory(ctx)
+}
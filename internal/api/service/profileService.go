@@ -23,6 +23,11 @@ func (s *profileService) GetProfileByID(ctx context.Context, profileID uint) (*d
 	return s.profileRepository.GetProfileByID(ctx, profileID)
 }
 
+// Implementation of 'GetProfileByName'.
+func (s *profileService) GetProfileByName(ctx context.Context, name string) (*domain.Profile, error) {
+	return s.profileRepository.GetProfileByName(ctx, name)
+}
+
 // Implementation of 'GetProfilesOutputDTO'.
 func (s *profileService) GetProfilesOutputDTO(ctx context.Context, filter *filter.Filter) (*dto.ItemsOutputDTO, error) {
 	return s.profileRepository.GetProfilesOutputDTO(ctx, filter)
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/secretref"
+	"github.com/raulaguila/go-pass/pkg/validator"
+)
+
+func NewSecretService(r domain.SecretRepository) domain.SecretService {
+	return &secretService{
+		secretRepository: r,
+	}
+}
+
+type secretService struct {
+	secretRepository domain.SecretRepository
+}
+
+// Implementation of 'ResolveSecrets'.
+func (s *secretService) ResolveSecrets(ctx context.Context, datas *dto.SecretResolveInputDTO, requester *domain.SecretRequester) ([]secretref.Secret, error) {
+	if err := validator.StructValidator.Validate(datas); err != nil {
+		return nil, err
+	}
+
+	return s.secretRepository.ResolveSecrets(ctx, datas, requester)
+}
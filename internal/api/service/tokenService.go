@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+)
+
+func NewPersonalTokenService(r domain.PersonalTokenRepository) domain.PersonalTokenService {
+	return &personalTokenService{
+		personalTokenRepository: r,
+	}
+}
+
+type personalTokenService struct {
+	personalTokenRepository domain.PersonalTokenRepository
+}
+
+// Implementation of 'GetPersonalTokenByID'.
+func (s *personalTokenService) GetPersonalTokenByID(ctx context.Context, tokenID, userID uint) (*domain.PersonalToken, error) {
+	return s.personalTokenRepository.GetPersonalTokenByID(ctx, tokenID, userID)
+}
+
+// Implementation of 'GetPersonalTokensOutputDTO'.
+func (s *personalTokenService) GetPersonalTokensOutputDTO(ctx context.Context, userID uint) (*dto.ItemsOutputDTO, error) {
+	return s.personalTokenRepository.GetPersonalTokensOutputDTO(ctx, userID)
+}
+
+// Implementation of 'CreatePersonalToken'.
+func (s *personalTokenService) CreatePersonalToken(ctx context.Context, datas *dto.PersonalTokenInputDTO, userID uint) (*domain.PersonalTokenResponse, error) {
+	return s.personalTokenRepository.CreatePersonalToken(ctx, datas, userID)
+}
+
+// Implementation of 'DeletePersonalToken'.
+func (s *personalTokenService) DeletePersonalToken(ctx context.Context, token *domain.PersonalToken) error {
+	return s.personalTokenRepository.DeletePersonalToken(ctx, token)
+}
+
+// Implementation of 'Authenticate'.
+func (s *personalTokenService) Authenticate(ctx context.Context, secret, ip string) (*domain.PersonalToken, error) {
+	return s.personalTokenRepository.Authenticate(ctx, secret, ip)
+}
@@ -58,7 +58,17 @@ func (s *userService) ResetUser(ctx context.Context, user *domain.User) error {
 	return s.userRepository.ResetUser(ctx, user)
 }
 
+// Implementation of 'CheckPassword'.
+func (s *userService) CheckPassword(ctx context.Context, user *domain.User, password string) error {
+	return s.userRepository.CheckPassword(ctx, user, password)
+}
+
 // Implementation of 'PasswordUser'.
 func (s *userService) PasswordUser(ctx context.Context, user *domain.User, pass *dto.PasswordInputDTO) error {
 	return s.userRepository.PasswordUser(ctx, user, pass)
 }
+
+// Implementation of 'RehashUser'.
+func (s *userService) RehashUser(ctx context.Context, user *domain.User) error {
+	return s.userRepository.RehashUser(ctx, user)
+}
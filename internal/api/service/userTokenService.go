@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+)
+
+func NewUserTokenService(r domain.UserTokenRepository) domain.UserTokenService {
+	return &userTokenService{
+		userTokenRepository: r,
+	}
+}
+
+type userTokenService struct {
+	userTokenRepository domain.UserTokenRepository
+}
+
+// Implementation of 'InviteUser'.
+func (s *userTokenService) InviteUser(ctx context.Context, user *domain.User, translation *i18n.Translation) error {
+	return s.userTokenRepository.InviteUser(ctx, user, translation)
+}
+
+// Implementation of 'AcceptInvite'.
+func (s *userTokenService) AcceptInvite(ctx context.Context, token, email string, pass *dto.PasswordInputDTO) (*domain.User, error) {
+	return s.userTokenRepository.AcceptInvite(ctx, token, email, pass)
+}
+
+// Implementation of 'ForgotPassword'.
+func (s *userTokenService) ForgotPassword(ctx context.Context, email string, translation *i18n.Translation) error {
+	return s.userTokenRepository.ForgotPassword(ctx, email, translation)
+}
+
+// Implementation of 'ResetPassword'.
+func (s *userTokenService) ResetPassword(ctx context.Context, token string, pass *dto.PasswordInputDTO) (*domain.User, error) {
+	return s.userTokenRepository.ResetPassword(ctx, token, pass)
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/configs"
+	"gorm.io/gorm"
+)
+
+// Open connects to the database of the configured driver, without migrating.
+func Open(cfg *configs.Config) *gorm.DB {
+	if strings.ToLower(cfg.Database.Driver) == "sqlite" {
+		return OpenSQLiteDB(cfg.SQLite)
+	}
+
+	return OpenPostgresDB(cfg.Postgres)
+}
+
+// Connect opens the database, applies the pending migrations and seeds the default profiles and the admin user,
+// before the API starts, so a shutdown can not interrupt the seed. The prefork children only open the database, the
+// parent migrated and seeded it before starting them.
+func Connect(cfg *configs.Config) (*gorm.DB, error) {
+	db := Open(cfg)
+	if fiber.IsChild() {
+		return db, nil
+	}
+
+	if err := migrateUp(db); err != nil {
+		return nil, err
+	}
+
+	if err := Seed(db, cfg.Admin); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/raulaguila/go-pass/pkg/pgerror"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// queryLogger writes the gorm logs with slog, so the queries carry the attributes of their context like the request
+// id. The failed queries are logged as errors, the others only on the debug level.
+type queryLogger struct{}
+
+func (l queryLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (queryLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	slog.InfoContext(ctx, fmt.Sprintf(format, args...))
+}
+
+func (queryLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	slog.WarnContext(ctx, fmt.Sprintf(format, args...))
+}
+
+func (queryLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	slog.ErrorContext(ctx, fmt.Sprintf(format, args...))
+}
+
+func (queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	level := slog.LevelDebug
+	switch {
+	case err == nil, errors.Is(err, gorm.ErrRecordNotFound):
+	case pgerror.HandlerError(err) != err:
+		// The constraint violations are answered to the clients, like a duplicated email.
+		level = slog.LevelWarn
+	default:
+		level = slog.LevelError
+	}
+
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{slog.String("sql", sql), slog.Int64("rows", rows), slog.Duration("elapsed", time.Since(begin))}
+	if err != nil && level != slog.LevelDebug {
+		slog.LogAttrs(ctx, level, "query failed", append(attrs, slog.Any("error", err))...)
+		return
+	}
+	slog.LogAttrs(ctx, level, "query", attrs...)
+}
+
+// ParamsFilter keeps the placeholders in the logged queries, the values may be passwords or secrets.
+func (queryLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
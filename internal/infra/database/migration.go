@@ -2,29 +2,69 @@ package database
 
 import (
 	"context"
-	"os"
+	"embed"
+	"fmt"
+	"log/slog"
+	"path"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/raulaguila/go-pass/configs"
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
-	"github.com/raulaguila/go-pass/pkg/helpers"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/raulaguila/go-pass/pkg/hasher"
+	"github.com/raulaguila/go-pass/pkg/migrate"
 	"gorm.io/gorm"
 )
 
-func autoMigrate(postgresdb *gorm.DB) {
-	helpers.PanicIfErr(postgresdb.AutoMigrate(&domain.Permissions{}))
-	helpers.PanicIfErr(postgresdb.AutoMigrate(&domain.Profile{}))
-	helpers.PanicIfErr(postgresdb.AutoMigrate(&domain.User{}))
+//go:embed migrations
+var migrationsfs embed.FS
 
-	helpers.PanicIfErr(postgresdb.AutoMigrate(&domain.Operator{}))
-	helpers.PanicIfErr(postgresdb.AutoMigrate(&domain.Phone{}))
-	helpers.PanicIfErr(postgresdb.AutoMigrate(&domain.Site{}))
-	helpers.PanicIfErr(postgresdb.AutoMigrate(&domain.Account{}))
-	helpers.PanicIfErr(postgresdb.AutoMigrate(&domain.AccountMailHistory{}))
+// Each driver has its own migrations, in the directory of its name.
+var dialects = map[string]migrate.Dialect{
+	"postgres": migrate.Postgres,
+	"sqlite":   migrate.SQLite,
 }
 
-func createDefaults(postgresdb *gorm.DB) {
+// NewMigrator creates the migrator of the embedded migrations of the database driver.
+func NewMigrator(db *gorm.DB) (*migrate.Migrator, error) {
+	driver := db.Dialector.Name()
+	dialect, ok := dialects[driver]
+	if !ok {
+		return nil, fmt.Errorf("no migrations for the %v driver", driver)
+	}
+
+	migrations, err := migrate.Load(migrationsfs, path.Join("migrations", driver))
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.New(sqlDB, dialect, migrations), nil
+}
+
+func migrateUp(db *gorm.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	migrations, err := migrator.Up(ctx)
+	for _, migration := range migrations {
+		slog.Info("migration applied", "version", migration.Version, "name", migration.Name)
+	}
+
+	return err
+}
+
+// Seed creates the default profiles and the admin user when missing, forcing the password change of an existing admin
+// that still has the configured password.
+func Seed(postgresdb *gorm.DB, admin configs.AdminConfig) error {
 	profiles := []domain.Profile{
 		{
 			Name: "ROOT",
@@ -45,29 +85,44 @@ func createDefaults(postgresdb *gorm.DB) {
 	defer cancel()
 	var profileID uint = 0
 	for i, profile := range profiles {
-		helpers.PanicIfErr(postgresdb.WithContext(ctx).FirstOrCreate(&profile, "name = ?", profile.Name).Error)
+		if err := postgresdb.WithContext(ctx).FirstOrCreate(&profile, "name = ?", profile.Name).Error; err != nil {
+			return err
+		}
 		if i == 0 {
 			profileID = profile.Id
 		}
 	}
 
+	// The seeded password comes from the configuration, so it must be changed on the first login.
 	user := &domain.User{
-		Name:      os.Getenv("ADM_NAME"),
-		Email:     os.Getenv("ADM_MAIL"),
-		Status:    true,
-		ProfileID: profileID,
-		New:       false,
-		Token:     new(string),
-		Password:  new(string),
+		Name:           admin.Name,
+		Email:          admin.Mail,
+		Status:         true,
+		ProfileID:      profileID,
+		New:            false,
+		ChangePassword: true,
+		Token:          new(string),
+		Password:       new(string),
 	}
 
 	token := uuid.New().String()
 	*user.Token = token
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(os.Getenv("ADM_PASS")), bcrypt.DefaultCost)
-	helpers.PanicIfErr(err)
-	user.Password = new(string)
-	*user.Password = string(hash)
+	hash, err := hasher.Hash(admin.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = &hash
+
+	result := postgresdb.WithContext(ctx).FirstOrCreate(user, "mail = ?", user.Email)
+	if result.Error != nil || result.RowsAffected > 0 || user.ChangePassword || user.Password == nil {
+		return result.Error
+	}
+
+	// An admin seeded before the forced change, still with the configured password, must change it too.
+	if ok, _, _ := hasher.Verify(*user.Password, admin.Password); !ok {
+		return nil
+	}
 
-	helpers.PanicIfErr(postgresdb.WithContext(ctx).FirstOrCreate(user, "mail = ?", user.Email).Error)
+	return postgresdb.WithContext(ctx).Model(user).Update("change_password", true).Error
 }
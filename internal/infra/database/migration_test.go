@@ -0,0 +1,41 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/raulaguila/go-pass/configs"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/pkg/hasher"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestSeedExistingAdmin
+func TestSeedExistingAdmin(t *testing.T) {
+	db := OpenSQLiteDB(configs.SQLiteConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	if !assert.Nil(t, migrateUp(db)) {
+		t.FailNow()
+	}
+
+	admin := configs.AdminConfig{Name: "Administrator", Mail: "admin@example.com", Password: "Admin#Pass123"}
+	assert.Nil(t, Seed(db, admin))
+
+	changePassword := func() bool {
+		user := &domain.User{}
+		assert.Nil(t, db.First(user, "mail = ?", admin.Mail).Error)
+		return user.ChangePassword
+	}
+	assert.True(t, changePassword())
+
+	// Admin seeded before the forced change, still with the configured password.
+	assert.Nil(t, db.Model(&domain.User{}).Where("mail = ?", admin.Mail).Update("change_password", false).Error)
+	assert.Nil(t, Seed(db, admin))
+	assert.True(t, changePassword())
+
+	// Admin that already changed the password.
+	hash, err := hasher.Hash("Changed#Pass456")
+	assert.Nil(t, err)
+	assert.Nil(t, db.Model(&domain.User{}).Where("mail = ?", admin.Mail).Updates(map[string]interface{}{"password": hash, "change_password": false}).Error)
+	assert.Nil(t, Seed(db, admin))
+	assert.False(t, changePassword())
+}
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS password_history;
+DROP TABLE IF EXISTS user_token;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS personal_token;
+DROP TABLE IF EXISTS account_mail_history;
+DROP TABLE IF EXISTS account;
+DROP TABLE IF EXISTS site;
+DROP TABLE IF EXISTS phone;
+DROP TABLE IF EXISTS operator;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS profiles;
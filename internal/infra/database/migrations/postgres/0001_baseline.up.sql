@@ -0,0 +1,161 @@
+-- Schema created by the AutoMigrate of the previous releases, every statement is
+-- guarded so the databases created by them are only recorded as migrated.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS profiles (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    name varchar(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id bigserial PRIMARY KEY,
+    profile_id bigint UNIQUE,
+    "user" boolean NOT NULL,
+    profile boolean NOT NULL,
+    CONSTRAINT fk_profiles_permissions FOREIGN KEY (profile_id) REFERENCES profiles (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    name varchar(90) NOT NULL,
+    mail varchar(50) NOT NULL UNIQUE,
+    status boolean NOT NULL,
+    new boolean NOT NULL,
+    profile_id bigint NOT NULL,
+    token varchar(255) UNIQUE,
+    password varchar(255),
+    source varchar(10) NOT NULL DEFAULT 'local',
+    change_password boolean NOT NULL DEFAULT false,
+    CONSTRAINT fk_users_profile FOREIGN KEY (profile_id) REFERENCES profiles (id)
+);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS source varchar(10) NOT NULL DEFAULT 'local';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS change_password boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_users_mail ON users (mail);
+CREATE INDEX IF NOT EXISTS idx_users_profile_id ON users (profile_id);
+CREATE INDEX IF NOT EXISTS idx_users_token ON users (token);
+
+CREATE TABLE IF NOT EXISTS operator (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    name varchar(100) NOT NULL UNIQUE
+);
+CREATE INDEX IF NOT EXISTS idx_operator_name ON operator (name);
+
+CREATE TABLE IF NOT EXISTS phone (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    number varchar(100) NOT NULL UNIQUE,
+    operator_id bigint NOT NULL,
+    CONSTRAINT fk_phone_operator FOREIGN KEY (operator_id) REFERENCES operator (id)
+);
+CREATE INDEX IF NOT EXISTS idx_phone_number ON phone (number);
+CREATE INDEX IF NOT EXISTS idx_phone_operator_id ON phone (operator_id);
+
+CREATE TABLE IF NOT EXISTS site (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    name varchar(100) NOT NULL UNIQUE,
+    url varchar(100) NOT NULL UNIQUE
+);
+CREATE INDEX IF NOT EXISTS idx_site_name ON site (name);
+CREATE INDEX IF NOT EXISTS idx_site_url ON site (url);
+
+CREATE TABLE IF NOT EXISTS account (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    username varchar(100) NOT NULL,
+    password varchar(100) NOT NULL,
+    site_id bigint NOT NULL,
+    phone_id bigint NOT NULL,
+    mail_id bigint DEFAULT NULL,
+    user_id bigint NOT NULL,
+    CONSTRAINT fk_account_site FOREIGN KEY (site_id) REFERENCES site (id),
+    CONSTRAINT fk_account_phone FOREIGN KEY (phone_id) REFERENCES phone (id),
+    CONSTRAINT fk_account_mail FOREIGN KEY (mail_id) REFERENCES account (id),
+    CONSTRAINT fk_account_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_account_username ON account (username);
+CREATE INDEX IF NOT EXISTS idx_account_password ON account (password);
+CREATE INDEX IF NOT EXISTS idx_account_site_id ON account (site_id);
+CREATE INDEX IF NOT EXISTS idx_account_phone_id ON account (phone_id);
+CREATE INDEX IF NOT EXISTS idx_account_mail_id ON account (mail_id);
+CREATE INDEX IF NOT EXISTS idx_account_user_id ON account (user_id);
+
+CREATE TABLE IF NOT EXISTS account_mail_history (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    account_id bigint NOT NULL,
+    mail_id bigint NOT NULL,
+    CONSTRAINT fk_account_mail_history_account FOREIGN KEY (account_id) REFERENCES account (id),
+    CONSTRAINT fk_account_mail_history_mail FOREIGN KEY (mail_id) REFERENCES account (id)
+);
+CREATE INDEX IF NOT EXISTS idx_account_mail_history_account_id ON account_mail_history (account_id);
+CREATE INDEX IF NOT EXISTS idx_account_mail_history_mail_id ON account_mail_history (mail_id);
+
+CREATE TABLE IF NOT EXISTS personal_token (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    name varchar(100) NOT NULL,
+    prefix varchar(20) NOT NULL,
+    hash varchar(64) NOT NULL UNIQUE,
+    scopes text NOT NULL,
+    sites text NOT NULL,
+    allowed_ips text NOT NULL,
+    expires_at timestamptz DEFAULT NULL,
+    last_used_at timestamptz DEFAULT NULL,
+    user_id bigint NOT NULL,
+    CONSTRAINT fk_personal_token_user FOREIGN KEY (user_id) REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_personal_token_hash ON personal_token (hash);
+CREATE INDEX IF NOT EXISTS idx_personal_token_user_id ON personal_token (user_id);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    action varchar(50) NOT NULL,
+    target varchar(255) NOT NULL,
+    success boolean NOT NULL,
+    detail varchar(255) NOT NULL,
+    user_id bigint NOT NULL,
+    token_id bigint DEFAULT NULL,
+    ip varchar(50) NOT NULL,
+    request_id varchar(50) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log (action);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user_id ON audit_log (user_id);
+
+CREATE TABLE IF NOT EXISTS user_token (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    purpose varchar(10) NOT NULL,
+    hash varchar(64) NOT NULL UNIQUE,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz DEFAULT NULL,
+    user_id bigint NOT NULL,
+    CONSTRAINT fk_user_token_user FOREIGN KEY (user_id) REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_user_token_purpose ON user_token (purpose);
+CREATE INDEX IF NOT EXISTS idx_user_token_hash ON user_token (hash);
+CREATE INDEX IF NOT EXISTS idx_user_token_user_id ON user_token (user_id);
+
+CREATE TABLE IF NOT EXISTS password_history (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    hash varchar(255) NOT NULL,
+    user_id bigint NOT NULL,
+    CONSTRAINT fk_password_history_user FOREIGN KEY (user_id) REFERENCES users (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_password_history_user_id ON password_history (user_id);
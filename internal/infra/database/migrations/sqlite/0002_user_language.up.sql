@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN language varchar(35) NOT NULL DEFAULT '';
@@ -3,39 +3,39 @@ package database
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
+	"github.com/raulaguila/go-pass/configs"
 	"github.com/raulaguila/go-pass/pkg/helpers"
 	"github.com/raulaguila/go-pass/pkg/pgerror"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-func pgConnect(dbName string) *gorm.DB {
-	uri := fmt.Sprintf("host=%s user=%s password=%s dbname=%v port=%s sslmode=disable TimeZone=%v", os.Getenv("POSTGRES_HOST"), os.Getenv("POSTGRES_USER"), os.Getenv("POSTGRES_PASS"), dbName, os.Getenv("POSTGRES_PORT"), time.Local.String())
+func pgConnect(cfg configs.PostgresConfig, dbName string) *gorm.DB {
+	uri := fmt.Sprintf("host=%s user=%s password=%s dbname=%v port=%s sslmode=disable TimeZone=%v", cfg.Host, cfg.User, cfg.Password, dbName, cfg.Port, time.Local.String())
 	db, err := gorm.Open(postgres.Open(uri), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger: queryLogger{},
 		NowFunc: func() time.Time {
 			return time.Now()
 		},
 		PrepareStmt: true,
 	})
 	helpers.PanicIfErr(err)
+	helpers.PanicIfErr(db.Use(tracingPlugin{system: "postgresql"}))
 
 	return db
 }
 
-func createDataBase() {
-	db := pgConnect("postgres")
+func createDataBase(cfg configs.PostgresConfig) {
+	db := pgConnect(cfg, "postgres")
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	con, err := db.WithContext(ctx).DB()
 	helpers.PanicIfErr(err)
 	defer con.Close()
 
-	if err := db.Exec(fmt.Sprintf("CREATE DATABASE %v;", os.Getenv("POSTGRES_BASE"))).Error; err != nil {
+	if err := db.Exec(fmt.Sprintf("CREATE DATABASE %v;", cfg.Base)).Error; err != nil {
 		switch pgerror.HandlerError(err) {
 		case pgerror.ErrDatabaseAlreadyExists:
 		default:
@@ -44,20 +44,9 @@ func createDataBase() {
 	}
 }
 
-func ConnectPostgresDB() (*gorm.DB, error) {
-	createDataBase()
+// OpenPostgresDB creates the database when missing and connects to it, without migrating.
+func OpenPostgresDB(cfg configs.PostgresConfig) *gorm.DB {
+	createDataBase(cfg)
 
-	db := pgConnect(os.Getenv("POSTGRES_BASE"))
-	postgresdb := db.WithContext(context.Background())
-	if err := postgresdb.Exec("CREATE EXTENSION IF NOT EXISTS unaccent;").Error; err != nil {
-		return nil, err
-	}
-
-	if err := postgresdb.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";").Error; err != nil {
-		return nil, err
-	}
-
-	autoMigrate(postgresdb)
-	go createDefaults(postgresdb)
-	return postgresdb, nil
+	return pgConnect(cfg, cfg.Base).WithContext(context.Background())
 }
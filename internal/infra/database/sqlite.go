@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/raulaguila/go-pass/configs"
+	"github.com/raulaguila/go-pass/pkg/helpers"
+	"gorm.io/gorm"
+)
+
+// The pragmas apply to every connection: foreign keys are off by default, and the
+// busy timeout with the WAL journal lets the readers run alongside the writer.
+const sqlitePragmas string = "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+// OpenSQLiteDB opens the database file, creating it when missing, without migrating.
+func OpenSQLiteDB(cfg configs.SQLiteConfig) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(cfg.Path+sqlitePragmas), &gorm.Config{
+		Logger: queryLogger{},
+		NowFunc: func() time.Time {
+			return time.Now()
+		},
+		PrepareStmt: true,
+	})
+	helpers.PanicIfErr(err)
+	helpers.PanicIfErr(db.Use(tracingPlugin{system: "sqlite"}))
+
+	return db.WithContext(context.Background())
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	tracerName  string = "gorm.io/gorm"
+	spanSetting string = "go-pass:span"
+)
+
+// tracingPlugin creates a child span of the context of each query, with the statement keeping its placeholders, so
+// the values like passwords or secrets are not exported. The spans are no-ops while tracing is disabled.
+type tracingPlugin struct {
+	system string
+}
+
+func (tracingPlugin) Name() string {
+	return "tracing"
+}
+
+// registerer is the callback returned by the Before and After of the gorm processors.
+type registerer interface {
+	Register(name string, fn func(*gorm.DB)) error
+}
+
+func (p tracingPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	hooks := []struct {
+		operation     string
+		before, after registerer
+	}{
+		{"create", callback.Create().Before("gorm:create"), callback.Create().After("gorm:create")},
+		{"query", callback.Query().Before("gorm:query"), callback.Query().After("gorm:query")},
+		{"update", callback.Update().Before("gorm:update"), callback.Update().After("gorm:update")},
+		{"delete", callback.Delete().Before("gorm:delete"), callback.Delete().After("gorm:delete")},
+		{"row", callback.Row().Before("gorm:row"), callback.Row().After("gorm:row")},
+		{"raw", callback.Raw().Before("gorm:raw"), callback.Raw().After("gorm:raw")},
+	}
+
+	for _, hook := range hooks {
+		if err := hook.before.Register("tracing:before_"+hook.operation, p.start(hook.operation)); err != nil {
+			return err
+		}
+		if err := hook.after.Register("tracing:after_"+hook.operation, p.end); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p tracingPlugin) start(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+
+		attrs := []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemKey.String(p.system), semconv.DBOperationName(operation)),
+		}
+		if db.Statement.Table != "" {
+			attrs = append(attrs, trace.WithAttributes(semconv.DBCollectionName(db.Statement.Table)))
+		}
+
+		ctx, span := otel.Tracer(tracerName).Start(db.Statement.Context, "gorm."+operation, attrs...)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanSetting, span)
+	}
+}
+
+func (tracingPlugin) end(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanSetting)
+	if !ok {
+		return
+	}
+
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(semconv.DBQueryText(db.Statement.SQL.String()))
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/raulaguila/go-pass/configs"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// go test -run TestTracingPlugin
+func TestTracingPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	db := OpenSQLiteDB(configs.SQLiteConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+
+	var value string
+	assert.Nil(t, db.WithContext(ctx).Raw("SELECT ?", "secret-value").Scan(&value).Error)
+	assert.Equal(t, "secret-value", value)
+	assert.NotNil(t, db.WithContext(ctx).Exec("SELECT * FROM missing").Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 3) {
+		t.FailNow()
+	}
+
+	for _, span := range spans[:2] {
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+
+		for _, attr := range span.Attributes() {
+			if attr.Key == semconv.DBQueryTextKey {
+				assert.NotContains(t, attr.Value.AsString(), "secret-value")
+			}
+		}
+	}
+	assert.Equal(t, "gorm.row", spans[0].Name())
+	assert.Equal(t, "gorm.raw", spans[1].Name())
+	assert.NotEmpty(t, spans[1].Events(), "the error is recorded")
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+)
+
+// go test -run TestAccountRoutes
+func TestAccountRoutes(t *testing.T) {
+	site := create(t, "/site", &dto.SiteInputDTO{Name: ptr("Account site"), URL: ptr("https://account.example.com")})
+	other := create(t, "/site", &dto.SiteInputDTO{Name: ptr("Other account site"), URL: ptr("https://other.example.com")})
+	operator := create(t, "/operator", &dto.OperatorInputDTO{Name: ptr("Account operator")})
+	phone := create(t, "/phone", &dto.PhoneInputDTO{Number: ptr("5531900000001"), OperatorID: &operator})
+	mail := create(t, "/account", &dto.AccountInputDTO{Username: ptr("mail@example.com"), Password: ptr("mail-secret"), SiteID: &other, PhoneID: &phone})
+	account := create(t, "/account", &dto.AccountInputDTO{Username: ptr("john"), Password: ptr("john-secret"), SiteID: &site, PhoneID: &phone})
+
+	readToken := personalToken(t, domain.ScopeAccountRead)
+	siteToken := createPersonalToken(t, &dto.PersonalTokenInputDTO{Name: ptr("Site token"), Scopes: []string{domain.ScopeAccountRead}, Sites: []uint{other}})
+	deleted := create(t, "/account", &dto.AccountInputDTO{Username: ptr("deleted"), Password: ptr("secret"), SiteID: &site, PhoneID: &phone})
+
+	runRouteTests(t, []routeTest{
+		{name: "list", method: fiber.MethodGet, path: "/account?search=joh", token: adminToken, status: http.StatusOK, check: hasCount(1)},
+		{name: "list by site", method: fiber.MethodGet, path: fmt.Sprintf("/account?site_id=%v", site), token: adminToken, status: http.StatusOK, check: hasCount(2)},
+		{name: "list restricted to the token sites", method: fiber.MethodGet, path: "/account?search=joh", token: siteToken, status: http.StatusOK, check: hasCount(0)},
+		{name: "get", method: fiber.MethodGet, path: idPath("/account", account), token: adminToken, status: http.StatusOK, check: hasField("username", "john")},
+		{name: "get outside the token sites", method: fiber.MethodGet, path: idPath("/account", account), token: siteToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrAccountNotFound }},
+		{name: "get missing", method: fiber.MethodGet, path: "/account/999999", token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrAccountNotFound }},
+		{name: "get password", method: fiber.MethodGet, path: idPath("/account", account) + "/pass", token: adminToken, status: http.StatusOK, check: hasField("password", "john-secret")},
+		{name: "get password without reveal scope", method: fiber.MethodGet, path: idPath("/account", account) + "/pass", token: readToken, status: http.StatusForbidden, message: func(tr *i18n.Translation) error { return tr.ErrTokenScope }},
+		{name: "create invalid body", method: fiber.MethodPost, path: "/account", token: adminToken, body: "{", status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidDatas }},
+		{name: "create without password", method: fiber.MethodPost, path: "/account", token: adminToken, body: &dto.AccountInputDTO{Username: ptr("jane"), SiteID: &site, PhoneID: &phone}, status: http.StatusBadRequest, check: hasMessage},
+		{name: "create missing site", method: fiber.MethodPost, path: "/account", token: adminToken, body: &dto.AccountInputDTO{Username: ptr("jane"), Password: ptr("secret"), SiteID: ptr(uint(999999)), PhoneID: &phone}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrSiteNotFound }},
+		{name: "create missing phone", method: fiber.MethodPost, path: "/account", token: adminToken, body: &dto.AccountInputDTO{Username: ptr("jane"), Password: ptr("secret"), SiteID: &site, PhoneID: ptr(uint(999999))}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrPhoneNotFound }},
+		{name: "create with personal token", method: fiber.MethodPost, path: "/account", token: readToken, body: &dto.AccountInputDTO{Username: ptr("jane"), Password: ptr("secret"), SiteID: &site, PhoneID: &phone}, status: http.StatusForbidden, message: func(tr *i18n.Translation) error { return tr.ErrTokenScope }},
+		{name: "update", method: fiber.MethodPut, path: idPath("/account", account), token: adminToken, body: &dto.AccountInputDTO{Username: ptr("jane"), MailID: &mail}, status: http.StatusOK, check: func(t *testing.T, data []byte) {
+			hasField("username", "jane")(t, data)
+			hasField("mail", map[string]interface{}{"id": float64(mail), "username": "mail@example.com"})(t, data)
+		}},
+		{name: "update missing", method: fiber.MethodPut, path: "/account/999999", token: adminToken, body: &dto.AccountInputDTO{}, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrAccountNotFound }},
+		{name: "delete used", method: fiber.MethodDelete, path: idPath("/account", mail), token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrAccountUsed }},
+		{name: "delete", method: fiber.MethodDelete, path: idPath("/account", deleted), token: adminToken, status: http.StatusNoContent},
+		{name: "delete missing", method: fiber.MethodDelete, path: idPath("/account", deleted), token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrAccountNotFound }},
+		{name: "invalid token", method: fiber.MethodGet, path: "/account", token: "invalid", status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+	})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/gofiber/swagger"
+	"github.com/raulaguila/go-pass/docs"
+	"github.com/raulaguila/go-pass/internal/api/middleware"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/internal/pkg/metrics"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+)
+
+// newApp creates the API with its middlewares and routes, without listening.
+func (s *Server) newApp() (*fiber.App, error) {
+	cfg := s.cfg
+	app := fiber.New(fiber.Config{
+		EnablePrintRoutes:     false,
+		Prefork:               cfg.System.Prefork,
+		CaseSensitive:         true,
+		StrictRouting:         true,
+		DisableStartupMessage: false,
+		AppName:               "Go - Expense API",
+		ReduceMemoryUsage:     false,
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			// The errors of fiber, like a body too large, keep their status, the others are unexpected.
+			if fiberErr := (*fiber.Error)(nil); errors.As(err, &fiberErr) {
+				return httphelper.NewHTTPResponse(c, fiberErr.Code, err)
+			}
+
+			httphelper.LogError(c, err)
+			if messages, ok := c.Locals(httphelper.LocalLang).(*i18n.Translation); ok {
+				err = messages.ErrGeneric
+			}
+			return httphelper.NewHTTPResponse(c, fiber.StatusInternalServerError, err)
+		},
+	})
+
+	// Outside the recover, so the panics are counted as errors.
+	if s.metrics != nil {
+		app.Use(middleware.Metrics(s.metrics))
+	}
+
+	app.Use(
+		recover.New(),
+		middleware.RequestLanguage(cfg.System.Language, cfg.System.Languages),
+		requestid.New(requestid.Config{ContextKey: httphelper.LocalRequestID}),
+	)
+
+	// Before the logger, so the logs of the request carry its trace id.
+	if s.tracing != nil {
+		app.Use(middleware.Trace)
+	}
+	app.Use(middleware.Logger(cfg.API.Logger))
+
+	app.Use(
+		cors.New(cors.Config{
+			AllowOrigins:     "*",
+			AllowMethods:     strings.Join([]string{fiber.MethodGet, fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete, fiber.MethodOptions}, ","),
+			AllowHeaders:     "*",
+			AllowCredentials: true,
+			ExposeHeaders:    "*",
+			MaxAge:           1,
+		}),
+		limiter.New(limiter.Config{
+			Max:        cfg.API.RateLimit,
+			Expiration: time.Minute,
+			LimitReached: func(c *fiber.Ctx) error {
+				s.metrics.RateLimited(metrics.LimiterAPI)
+				messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+				return httphelper.NewHTTPResponse(c, fiber.StatusTooManyRequests, messages.ErrManyRequest)
+			},
+		}),
+	)
+
+	if cfg.API.Swagger {
+		docs.SwaggerInfo.Version = cfg.Version
+
+		// 	// Config swagger
+		app.Get("/swagger/*", swagger.New(swagger.Config{
+			DisplayRequestDuration: true,
+			DocExpansion:           "none",
+			ValidatorUrl:           "none",
+		}))
+	}
+
+	// The server is ready once listening, until its shutdown starts.
+	app.Hooks().OnListen(func(data fiber.ListenData) error {
+		s.addr.Store(net.JoinHostPort(data.Host, data.Port))
+		s.ready.Store(true)
+		return nil
+	})
+
+	if err := s.initHandelrs(app); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
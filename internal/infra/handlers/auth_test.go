@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestAuthRoutes
+func TestAuthRoutes(t *testing.T) {
+	const (
+		changedPassword string = "Changed-Passw0rd"
+		resetPassword   string = "Reset-Passw0rd"
+	)
+
+	// The password changes end the sessions of the user, so the administrator is kept logged in.
+	session := login(userMail, userPassword)
+	credentials := func(mail, password string) string {
+		return `{"email":"` + mail + `","password":"` + password + `"}`
+	}
+
+	runRouteTests(t, []routeTest{
+		{name: "login", method: fiber.MethodPost, path: "/auth", body: credentials(userMail, userPassword), status: http.StatusOK, check: func(t *testing.T, data []byte) {
+			response := &tokens{}
+			assert.Nil(t, json.Unmarshal(data, response))
+			assert.NotEmpty(t, response.AccessToken)
+			assert.NotEmpty(t, response.RefreshToken)
+		}},
+		{name: "login wrong password", method: fiber.MethodPost, path: "/auth", body: credentials(userMail, "wrong"), status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrInvalidCredentials }},
+		{name: "login unknown email", method: fiber.MethodPost, path: "/auth", body: credentials("unknown@admin.com", userPassword), status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrInvalidCredentials }},
+		{name: "login invalid body", method: fiber.MethodPost, path: "/auth", body: "{", status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidDatas }},
+		{name: "me", method: fiber.MethodGet, path: "/auth", token: session.AccessToken, status: http.StatusOK, check: hasField("mail", userMail)},
+		{name: "me invalid token", method: fiber.MethodGet, path: "/auth", token: "invalid", status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+		{name: "refresh", method: fiber.MethodPut, path: "/auth", token: session.RefreshToken, status: http.StatusOK},
+		{name: "refresh with access token", method: fiber.MethodPut, path: "/auth", token: session.AccessToken, status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+		{name: "password unmatched", method: fiber.MethodPut, path: "/auth/password", token: session.AccessToken, body: &dto.PasswordInputDTO{Current: ptr(userPassword), Password: ptr(changedPassword), PasswordConfirm: ptr("other")}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrPassUnmatch }},
+		{name: "password wrong current", method: fiber.MethodPut, path: "/auth/password", token: session.AccessToken, body: &dto.PasswordInputDTO{Current: ptr("wrong"), Password: ptr(changedPassword), PasswordConfirm: ptr(changedPassword)}, status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrInvalidCredentials }},
+		{name: "password reused", method: fiber.MethodPut, path: "/auth/password", token: session.AccessToken, body: &dto.PasswordInputDTO{Current: ptr(userPassword), Password: ptr(userPassword), PasswordConfirm: ptr(userPassword)}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrPasswordReused }},
+		{name: "password", method: fiber.MethodPut, path: "/auth/password", token: session.AccessToken, body: &dto.PasswordInputDTO{Current: ptr(userPassword), Password: ptr(changedPassword), PasswordConfirm: ptr(changedPassword)}, status: http.StatusNoContent},
+		{name: "session ended", method: fiber.MethodGet, path: "/auth", token: session.AccessToken, status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+		{name: "login changed password", method: fiber.MethodPost, path: "/auth", body: credentials(userMail, changedPassword), status: http.StatusOK},
+		{name: "forgot invalid body", method: fiber.MethodPost, path: "/auth/forgot", body: "{", status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidDatas }},
+	})
+
+	// The reset link is emailed in background.
+	sent := len(mails.String())
+	status, data := request(t, fiber.MethodPost, "/auth/forgot", "", &dto.ForgotInputDTO{Email: userMail})
+	assert.Equal(t, http.StatusAccepted, status, string(data))
+	if !assert.Eventually(t, func() bool { return strings.Contains(mails.String()[sent:], userMail) }, time.Second, 10*time.Millisecond) {
+		t.FailNow()
+	}
+	resetToken := lastMailToken(t)
+
+	runRouteTests(t, []routeTest{
+		{name: "reset invalid token", method: fiber.MethodPost, path: "/auth/reset", body: &dto.PasswordInputDTO{Token: ptr("invalid"), Password: ptr(resetPassword), PasswordConfirm: ptr(resetPassword)}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidUserToken }},
+		{name: "reset", method: fiber.MethodPost, path: "/auth/reset", body: &dto.PasswordInputDTO{Token: &resetToken, Password: ptr(resetPassword), PasswordConfirm: ptr(resetPassword)}, status: http.StatusNoContent},
+		{name: "reset used token", method: fiber.MethodPost, path: "/auth/reset", body: &dto.PasswordInputDTO{Token: &resetToken, Password: ptr(resetPassword), PasswordConfirm: ptr(resetPassword)}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidUserToken }},
+		{name: "login reset password", method: fiber.MethodPost, path: "/auth", body: credentials(userMail, resetPassword), status: http.StatusOK},
+	})
+}
+
+// go test -run TestLoginLockout
+func TestLoginLockout(t *testing.T) {
+	// A second server with low thresholds, every request comes from the same IP.
+	cfg := *config
+	cfg.Auth.MaxAttempts, cfg.Auth.IPMaxAttempts, cfg.Auth.BackoffDelay = 2, 4, 0
+	server, err := NewServerWithRepositories(repositories, &cfg)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	login := func(mail, password string) int {
+		req := httptest.NewRequest(fiber.MethodPost, "/auth", strings.NewReader(`{"email":"`+mail+`","password":"`+password+`"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := server.App().Test(req, -1)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	// The email is locked from the IP, the other emails are not.
+	assert.Equal(t, http.StatusUnauthorized, login("locked@email.com", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, login("locked@email.com", "wrong"))
+	assert.Equal(t, http.StatusTooManyRequests, login("locked@email.com", "wrong"))
+	assert.Equal(t, http.StatusOK, login(adminMail, adminPassword))
+
+	// The IP is locked after its own threshold, for every email.
+	assert.Equal(t, http.StatusUnauthorized, login("other@email.com", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, login("another@email.com", "wrong"))
+	assert.Equal(t, http.StatusTooManyRequests, login(adminMail, adminPassword))
+}
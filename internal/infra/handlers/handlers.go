@@ -1,104 +1,331 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"log"
-	"os"
+	"log/slog"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/swagger"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/middleware/encryptcookie"
 
-	"github.com/raulaguila/go-pass/docs"
+	"github.com/raulaguila/go-pass/configs"
 	"github.com/raulaguila/go-pass/internal/api/handler"
 	"github.com/raulaguila/go-pass/internal/api/middleware"
 	"github.com/raulaguila/go-pass/internal/api/service"
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
 	"github.com/raulaguila/go-pass/internal/pkg/i18n"
 	"github.com/raulaguila/go-pass/internal/pkg/repository"
+	"github.com/raulaguila/go-pass/pkg/bruteforce"
 	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/raulaguila/go-pass/pkg/ldapauth"
+	"github.com/raulaguila/go-pass/pkg/mailer"
+	"github.com/raulaguila/go-pass/pkg/oidc"
+	"github.com/raulaguila/go-pass/pkg/passpolicy"
 
 	"gorm.io/gorm"
 )
 
-var (
-	profileRepository  domain.ProfileRepository
-	userRepository     domain.UserRepository
-	authRepository     domain.AuthRepository
-	siteRepository     domain.SiteRepository
-	operatorRepository domain.OperatorRepository
-	phoneRepository    domain.PhoneRepository
-	accountRepository  domain.AccountRepository
-
-	profileService  domain.ProfileService
-	userService     domain.UserService
-	authService     domain.AuthService
-	siteService     domain.SiteService
-	operatorService domain.OperatorService
-	phoneService    domain.PhoneService
-	accountService  domain.AccountService
-)
+// Services are the services shared by the API and the command line.
+type Services struct {
+	Profile       domain.ProfileService
+	User          domain.UserService
+	UserToken     domain.UserTokenService
+	Auth          domain.AuthService
+	Site          domain.SiteService
+	Operator      domain.OperatorService
+	Phone         domain.PhoneService
+	Account       domain.AccountService
+	PersonalToken domain.PersonalTokenService
+	Secret        domain.SecretService
+}
 
-func initRepositories(postgresdb *gorm.DB) {
-	// Create repositories.
-	profileRepository = repository.NewProfileRepository(postgresdb)
-	userRepository = repository.NewUserRepository(postgresdb)
-	authRepository = repository.NewAuthRepository(userRepository)
-	siteRepository = repository.NewSiteRepository(postgresdb)
-	operatorRepository = repository.NewOperatorRepository(postgresdb)
-	phoneRepository = repository.NewPhoneRepository(postgresdb)
-	accountRepository = repository.NewAccountRepository(postgresdb)
+// Repositories are the storages of the services, the API runs over any implementation of them.
+type Repositories struct {
+	Profile       domain.ProfileRepository
+	User          domain.UserRepository
+	Site          domain.SiteRepository
+	Operator      domain.OperatorRepository
+	Phone         domain.PhoneRepository
+	Account       domain.AccountRepository
+	PersonalToken domain.PersonalTokenRepository
+	Audit         domain.AuditRepository
+	Secret        domain.SecretRepository
+	UserToken     domain.UserTokenRepository
 }
 
-func initServices() {
-	// Create services.
-	profileService = service.NewProfileService(profileRepository)
-	userService = service.NewUserService(userRepository)
-	authService = service.NewAuthService(authRepository)
-	siteService = service.NewSiteService(siteRepository)
-	operatorService = service.NewOperatorService(operatorRepository)
-	phoneService = service.NewPhoneService(phoneRepository)
-	accountService = service.NewAccountService(accountRepository)
+func (s *Server) initKeyrings(cfg configs.TokensConfig) error {
+	var err error
+
+	// The first private key signs the tokens, the others keys are accepted until removed.
+	if s.accessKeyring, err = keyring.FromBase64(cfg.AccessPrivate, cfg.AccessPublic); err != nil {
+		return err
+	}
+
+	s.refreshKeyring, err = keyring.FromBase64(cfg.RefreshPrivate, cfg.RefreshPublic)
+	return err
 }
 
-func initHandelrs(app *fiber.App, postgresdb *gorm.DB) {
-	reqMid := middleware.NewRequesttMiddleware(postgresdb)
+// Enables the LDAP login when a directory URL is configured.
+func (s *Server) initDirectory(cfg configs.LDAPConfig) error {
+	if cfg.URL == "" {
+		return nil
+	}
 
-	// Initialize access middleares
-	middleware.MidAccess = middleware.Auth(os.Getenv("ACCESS_TOKEN_PUBLIC"), authService)
-	middleware.MidRefresh = middleware.Auth(os.Getenv("RFRESH_TOKEN_PUBLIC"), authService)
+	var err error
+	if s.directoryGroupMap, err = ldapauth.ParseGroupMap(cfg.GroupProfiles); err != nil {
+		return err
+	}
 
-	// Prepare endpoints for the API.
-	handler.NewMiscHandler(app.Group(""))
-	handler.NewAuthHandler(app.Group("/auth"), authService)
-	handler.NewProfileHandler(app.Group("/profile"), profileService, reqMid)
-	handler.NewUserHandler(app.Group("/user"), userService, reqMid)
-	handler.NewSiteHandler(app.Group("/site"), siteService, reqMid)
-	handler.NewOperatorHandler(app.Group("/operator"), operatorService, reqMid)
-	handler.NewPhoneHandler(app.Group("/phone"), phoneService, reqMid)
-	handler.NewAccountHandler(app.Group("/account"), accountService, reqMid)
+	s.directory = ldapauth.New(ldapauth.Config{
+		URL:          cfg.URL,
+		StartTLS:     cfg.StartTLS,
+		BindDN:       cfg.BindDN,
+		BindPassword: cfg.BindPassword,
+		UserBaseDN:   cfg.UserBaseDN,
+		UserFilter:   cfg.UserFilter,
+		MailAttr:     cfg.MailAttr,
+		NameAttr:     cfg.NameAttr,
+		GroupBaseDN:  cfg.GroupBaseDN,
+		GroupFilter:  cfg.GroupFilter,
+	})
+	return nil
+}
 
-	// Prepare an endpoint for 'Not Found'.
-	app.All("*", func(c *fiber.Ctx) error {
-		messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
-		return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, messages.ErrorNonexistentRoute)
+// Periodically disables the users removed from the directory, until the context is canceled.
+func (s *Server) startDirectorySync(ctx context.Context, interval time.Duration) {
+	// With prefork, only the parent process runs the sync.
+	if s.directory == nil || fiber.IsChild() || interval <= 0 {
+		return
+	}
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.services.Auth.SyncDirectory(ctx); err != nil {
+					slog.ErrorContext(ctx, "directory sync failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Delivers the emails by SMTP, or writes them to a file or to the log in development.
+func (s *Server) initMailer(cfg configs.MailConfig) error {
+	switch strings.ToLower(cfg.Driver) {
+	case "smtp":
+		s.mailSender = mailer.NewSMTP(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUser,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+			TLS:      cfg.SMTPTLS,
+		})
+	case "file":
+		var err error
+		if s.mailSender, err = mailer.NewFile(cfg.File, cfg.From); err != nil {
+			return err
+		}
+	default:
+		s.mailSender = mailer.NewLog(log.Writer(), cfg.From)
+	}
+
+	return nil
+}
+
+func newPasswordPolicy(cfg configs.PasswordConfig) *passpolicy.Policy {
+	policy := passpolicy.Default()
+	policy.MinLength = cfg.MinLength
+	policy.MinClasses = cfg.MinClasses
+	policy.History = cfg.History
+	policy.Common = cfg.CheckCommon
+
+	return policy
+}
+
+// Creates the repositories over the database, after the keyrings and the mailer.
+func (s *Server) newRepositories() *Repositories {
+	policy := newPasswordPolicy(s.cfg.Password)
+	users := func(db *gorm.DB) domain.UserRepository { return repository.NewUserRepository(db, policy) }
+
+	repositories := &Repositories{
+		Profile:       repository.NewProfileRepository(s.db),
+		User:          users(s.db),
+		Site:          repository.NewSiteRepository(s.db),
+		Operator:      repository.NewOperatorRepository(s.db),
+		Phone:         repository.NewPhoneRepository(s.db),
+		Account:       repository.NewAccountRepository(s.db),
+		PersonalToken: repository.NewPersonalTokenRepository(s.db),
+		Audit:         repository.NewAuditRepository(s.db),
+	}
+	repositories.Secret = repository.NewSecretRepository(s.db, repositories.Audit)
+	repositories.UserToken = repository.NewUserTokenRepository(s.db, users, s.accessKeyring, s.mailSender, s.cfg.API.AppURL, s.cfg.Tokens.InviteExpire, s.cfg.Tokens.ResetExpire)
+
+	return repositories
+}
+
+func (s *Server) initServices() {
+	authRepository := repository.NewAuthRepository(s.repositories.User, s.repositories.Profile, s.accessKeyring, s.refreshKeyring, s.cfg.Tokens.AccessExpire, s.cfg.Tokens.RefreshExpire, s.directory, s.directoryGroupMap)
+
+	s.services = &Services{
+		Profile:       service.NewProfileService(s.repositories.Profile),
+		User:          service.NewUserService(s.repositories.User),
+		UserToken:     service.NewUserTokenService(s.repositories.UserToken),
+		Auth:          service.NewAuthService(authRepository),
+		Site:          service.NewSiteService(s.repositories.Site),
+		Operator:      service.NewOperatorService(s.repositories.Operator),
+		Phone:         service.NewPhoneService(s.repositories.Phone),
+		Account:       service.NewAccountService(s.repositories.Account),
+		PersonalToken: service.NewPersonalTokenService(s.repositories.PersonalToken),
+		Secret:        service.NewSecretService(s.repositories.Secret),
+	}
+}
+
+// initDependencies creates the keyrings, the directory and the mailer of the configuration.
+func (s *Server) initDependencies() error {
+	if err := s.initKeyrings(s.cfg.Tokens); err != nil {
+		return err
+	}
+	if err := s.initDirectory(s.cfg.LDAP); err != nil {
+		return err
+	}
+
+	return s.initMailer(s.cfg.Mail)
+}
+
+// NewServices creates the services over the database without the API, for the command line.
+func NewServices(db *gorm.DB, cfg *configs.Config) (*Services, error) {
+	s := &Server{cfg: cfg, db: db}
+	if err := s.initDependencies(); err != nil {
+		return nil, err
+	}
+
+	s.repositories = s.newRepositories()
+	s.initServices()
+	return s.services, nil
+}
+
+// Notifies the administrators when an email or IP gets locked by failed logins.
+func notifyLockout(webhook, key string, failures int, until time.Time) {
+	slog.Warn("login locked after failed attempts", "key", key, "failures", failures, "until", until)
+
+	if webhook == "" {
+		return
+	}
+
+	body, err := json.Marshal(&fiber.Map{"key": key, "failures": failures, "until": until})
+	if err != nil {
+		slog.Error("could not encode the lockout notification", "error", err)
+		return
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Post(webhook, fiber.MIMEApplicationJSON, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("could not send the lockout notification", "error", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// newLoginGuard locks the keys after maxAttempts failed logins, delaying the attempts before by the backoff.
+func newLoginGuard(cfg configs.AuthConfig, maxAttempts int, backoff time.Duration) *bruteforce.Guard {
+	return bruteforce.New(bruteforce.Config{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   backoff,
+		MaxDelay:    cfg.LockoutTime,
+		LockoutTime: cfg.LockoutTime,
+		OnLockout: func(key string, failures int, until time.Time) {
+			notifyLockout(cfg.LockoutWebhook, key, failures, until)
+		},
+	})
+}
+
+// Enables the OpenID Connect login when an issuer is configured.
+func (s *Server) initOIDCHandler(route fiber.Router, cfg configs.OIDCConfig) error {
+	if cfg.Issuer == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	provider, err := oidc.New(ctx, oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       cfg.Scopes,
 	})
+	if err != nil {
+		return err
+	}
+
+	// Without a configured key, the logins in progress are lost on restarts and each prefork child uses its own key.
+	cookieKey := cfg.CookieKey
+	if cookieKey == "" {
+		cookieKey = encryptcookie.GenerateKey()
+	}
+
+	handler.NewOIDCHandler(route, s.services.Auth, provider, cfg.ProvisionProfile, cookieKey)
+	return nil
 }
 
-func HandleRequests(app *fiber.App, postgresdb *gorm.DB) {
-	if strings.ToLower(os.Getenv("API_SWAGGO")) == "true" {
-		docs.SwaggerInfo.Version = os.Getenv("SYS_VERSION")
+func (s *Server) initHandelrs(app *fiber.App) error {
+	services := s.services
+	reqMid := middleware.NewRequesttMiddleware(services.Profile, services.User, services.Site, services.Operator, services.Phone, services.Account)
+
+	// The access tokens and the personal tokens authenticate the routes, the refresh tokens only renew the sessions.
+	access := middleware.Auth(s.accessKeyring, services.Auth, services.PersonalToken)
+	refresh := middleware.Auth(s.refreshKeyring, services.Auth, nil)
+
+	// The lists are sorted by default as configured.
+	sort, order := s.cfg.API.DefaultSort, strings.ToLower(s.cfg.API.DefaultOrder)
+	list := middleware.GenericFilter(sort, order)
 
-		// 	// Config swagger
-		app.Get("/swagger/*", swagger.New(swagger.Config{
-			DisplayRequestDuration: true,
-			DocExpansion:           "none",
-			ValidatorUrl:           "none",
-		}))
+	readiness, err := s.newReadiness()
+	if err != nil {
+		return err
 	}
 
-	initRepositories(postgresdb)
-	initServices()
-	initHandelrs(app, postgresdb)
+	// Prepare endpoints for the API.
+	handler.NewMiscHandler(app.Group(""), access, s.accessKeyring, readiness)
+	if s.metrics != nil && s.cfg.Metrics.Address == "" {
+		app.Get("/metrics", adaptor.HTTPHandler(s.metrics.Handler(s.cfg.Metrics.Token)))
+	}
+	handler.NewAuthHandler(app.Group("/auth"), access, refresh, services.Auth, services.User, services.UserToken, newLoginGuard(s.cfg.Auth, s.cfg.Auth.MaxAttempts, s.cfg.Auth.BackoffDelay), newLoginGuard(s.cfg.Auth, s.cfg.Auth.IPMaxAttempts, 0), s.metrics)
+	if err := s.initOIDCHandler(app.Group("/auth/oidc"), s.cfg.OIDC); err != nil {
+		return err
+	}
+	handler.NewProfileHandler(app.Group("/profile"), access, list, services.Profile, reqMid)
+	handler.NewUserHandler(app.Group("/user"), access, middleware.UserFilter(sort, order), services.User, services.UserToken, reqMid)
+	handler.NewSiteHandler(app.Group("/site"), access, list, services.Site, reqMid)
+	handler.NewOperatorHandler(app.Group("/operator"), access, list, services.Operator, reqMid)
+	handler.NewPhoneHandler(app.Group("/phone"), access, list, services.Phone, reqMid)
+	handler.NewAccountHandler(app.Group("/account"), access, middleware.AccountFilter(sort, order), services.Account, reqMid, s.metrics)
+	handler.NewPersonalTokenHandler(app.Group("/token"), access, services.PersonalToken)
+	handler.NewSecretHandler(app.Group("/secrets"), access, services.Secret, s.metrics)
+
+	// Prepare an endpoint for 'Not Found'.
+	app.All("*", func(c *fiber.Ctx) error {
+		messages := c.Locals(httphelper.LocalLang).(*i18n.Translation)
+		return httphelper.NewHTTPResponse(c, fiber.StatusNotFound, messages.ErrorNonexistentRoute)
+	})
 
-	log.Fatal(app.Listen(":" + os.Getenv("API_PORT")))
+	return nil
 }
@@ -0,0 +1,396 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/configs"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/internal/pkg/repository/memory"
+	"github.com/raulaguila/go-pass/pkg/health"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/raulaguila/go-pass/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	adminMail     string = "admin@admin.com"
+	adminPassword string = "Admin-Passw0rd"
+	userMail      string = "user@admin.com"
+	userPassword  string = "User-Passw0rd"
+)
+
+var (
+	app          *fiber.App
+	config       *configs.Config
+	db           *memory.DB
+	repositories *Repositories
+	languages    []string
+	mails        = &mailbox{}
+
+	rootProfileID, userProfileID uint
+	adminID, userID              uint
+	adminToken                   string
+)
+
+// mailbox keeps the emails sent by the app, the password recovery sends them in background.
+type mailbox struct {
+	mu   sync.Mutex
+	data bytes.Buffer
+}
+
+func (s *mailbox) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data.Write(p)
+}
+
+func (s *mailbox) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data.String()
+}
+
+var mailToken = regexp.MustCompile(`[?&]token=([^\s&]+)`)
+
+// lastMailToken returns the token of the last emailed link.
+func lastMailToken(t *testing.T) string {
+	matches := mailToken.FindAllStringSubmatch(mails.String(), -1)
+	if !assert.NotEmpty(t, matches) {
+		t.FailNow()
+	}
+
+	token, err := url.QueryUnescape(matches[len(matches)-1][1])
+	assert.Nil(t, err)
+	return token
+}
+
+func base64Key() string {
+	key, err := keyring.Generate("EdDSA")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	private, err := key.PrivateBase64()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return private
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// seed creates the ROOT and USER profiles, the administrator and a user of the USER profile.
+func seed(repositories *Repositories) error {
+	ctx := context.Background()
+
+	root, err := repositories.Profile.CreateProfile(ctx, &dto.ProfileInputDTO{Name: ptr("ROOT"), Permissions: dto.PermissionsInputDTO{UserModule: ptr(true), ProfileModule: ptr(true)}})
+	if err != nil {
+		return err
+	}
+	profile, err := repositories.Profile.CreateProfile(ctx, &dto.ProfileInputDTO{Name: ptr("USER")})
+	if err != nil {
+		return err
+	}
+	rootProfileID, userProfileID = root.Id, profile.Id
+
+	users := []struct {
+		id       *uint
+		name     string
+		mail     string
+		password string
+		profile  uint
+	}{
+		{&adminID, "Administrator", adminMail, adminPassword, root.Id},
+		{&userID, "Common user", userMail, userPassword, profile.Id},
+	}
+	for _, item := range users {
+		user, err := repositories.User.CreateUser(ctx, &dto.UserInputDTO{Name: &item.name, Email: &item.mail, Status: ptr(true), ProfileID: &item.profile})
+		if err != nil {
+			return err
+		}
+		if err := repositories.User.PasswordUser(ctx, user, &dto.PasswordInputDTO{Password: &item.password, PasswordConfirm: &item.password}); err != nil {
+			return err
+		}
+		*item.id = user.Id
+	}
+
+	return nil
+}
+
+// Every test shares one app, seeded once and backed by the in-memory repositories.
+func TestMain(m *testing.M) {
+	var err error
+	config, err = configs.Load([]string{
+		"-tokens.access_private", base64Key(),
+		"-tokens.refresh_private", base64Key(),
+		"-database.driver", "sqlite",
+		"-admin.mail", adminMail,
+		"-admin.password", adminPassword,
+		"-password.hasher", "bcrypt",
+		"-password.bcrypt_cost", "4",
+		"-api.logger", "false",
+		"-api.rate_limit", "100000",
+		"-auth.backoff_delay", "0",
+		"-auth.max_attempts", "1000",
+		"-auth.ip_max_attempts", "1000",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := config.Apply(); err != nil {
+		log.Fatal(err)
+	}
+	languages = config.System.Languages
+
+	db = memory.NewDB()
+	userRepository := memory.NewUserRepository(db, newPasswordPolicy(config.Password))
+	auditRepository := memory.NewAuditRepository(db)
+	repositories = &Repositories{
+		Profile:       memory.NewProfileRepository(db),
+		User:          userRepository,
+		Site:          memory.NewSiteRepository(db),
+		Operator:      memory.NewOperatorRepository(db),
+		Phone:         memory.NewPhoneRepository(db),
+		Account:       memory.NewAccountRepository(db),
+		PersonalToken: memory.NewPersonalTokenRepository(db),
+		Audit:         auditRepository,
+		Secret:        memory.NewSecretRepository(db, auditRepository),
+		UserToken:     memory.NewUserTokenRepository(db, userRepository, mailer.NewLog(mails, config.Mail.From), config.API.AppURL, config.Tokens.InviteExpire, config.Tokens.ResetExpire),
+	}
+	if err := seed(repositories); err != nil {
+		log.Fatal(err)
+	}
+
+	server, err := NewServerWithRepositories(repositories, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app = server.App()
+	adminToken = login(adminMail, adminPassword).AccessToken
+
+	m.Run()
+}
+
+type tokens struct {
+	AccessToken  string `json:"accesstoken"`
+	RefreshToken string `json:"refreshtoken"`
+}
+
+// login returns the tokens of the user, without expiration.
+func login(mail, password string) *tokens {
+	req := httptest.NewRequest(fiber.MethodPost, "/auth", strings.NewReader(`{"email":"`+mail+`","password":"`+password+`"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	response := &tokens{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil || response.AccessToken == "" {
+		log.Fatalf("login of %v failed: %v", mail, resp.Status)
+	}
+
+	return response
+}
+
+// request sends the request to the app, the body is encoded as JSON unless it is a string.
+func request(t *testing.T, method, path, token string, body interface{}) (int, []byte) {
+	var reader io.Reader
+	switch value := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(value)
+	default:
+		data, err := json.Marshal(value)
+		assert.Nil(t, err)
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+
+	resp, err := app.Test(req, -1)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	return resp.StatusCode, data
+}
+
+// create sends the item to the route and returns the id of the created item.
+func create(t *testing.T, path string, body interface{}) uint {
+	status, data := request(t, fiber.MethodPost, path, adminToken, body)
+	if !assert.Equal(t, http.StatusCreated, status, string(data)) {
+		t.FailNow()
+	}
+
+	item := &struct {
+		ID uint `json:"id"`
+	}{}
+	assert.Nil(t, json.Unmarshal(data, item))
+	return item.ID
+}
+
+// personalToken creates a personal token of the administrator with the scopes.
+func personalToken(t *testing.T, scopes ...string) string {
+	return createPersonalToken(t, &dto.PersonalTokenInputDTO{Name: ptr("Test token"), Scopes: scopes})
+}
+
+// createPersonalToken creates a personal token of the administrator and returns its secret.
+func createPersonalToken(t *testing.T, input *dto.PersonalTokenInputDTO) string {
+	status, data := request(t, fiber.MethodPost, "/token", adminToken, input)
+	if !assert.Equal(t, http.StatusCreated, status, string(data)) {
+		t.FailNow()
+	}
+
+	token := &struct {
+		Token string `json:"token"`
+	}{}
+	assert.Nil(t, json.Unmarshal(data, token))
+	return token.Token
+}
+
+func idPath(prefix string, id uint) string {
+	return prefix + "/" + strconv.FormatUint(uint64(id), 10)
+}
+
+func withLanguage(path, lang string) string {
+	if strings.Contains(path, "?") {
+		return path + "&lang=" + lang
+	}
+	return path + "?lang=" + lang
+}
+
+// hasCount checks the count of a list response.
+func hasCount(count int64) func(*testing.T, []byte) {
+	return func(t *testing.T, data []byte) {
+		response := &struct {
+			Count int64 `json:"count"`
+		}{}
+		assert.Nil(t, json.Unmarshal(data, response))
+		assert.Equal(t, count, response.Count)
+	}
+}
+
+// hasField checks a field of an object response.
+func hasField(field string, value interface{}) func(*testing.T, []byte) {
+	return func(t *testing.T, data []byte) {
+		response := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(data, &response))
+		assert.EqualValues(t, value, response[field])
+	}
+}
+
+// hasMessage checks that the error response has a detail, for the errors without translation.
+func hasMessage(t *testing.T, data []byte) {
+	response := &httphelper.Problem{}
+	assert.Nil(t, json.Unmarshal(data, response))
+	assert.NotEmpty(t, response.Detail)
+}
+
+type routeTest struct {
+	name   string
+	method string
+	path   string
+	token  string
+	body   interface{}
+	status int
+
+	// message is the error of the response, the request is repeated for each language to check its translation.
+	message func(*i18n.Translation) error
+	// check validates the body of the response, when the request is sent once.
+	check func(*testing.T, []byte)
+}
+
+// runRouteTests runs the tests in order, so the later tests see the changes of the previous ones.
+func runRouteTests(t *testing.T, tests []routeTest) {
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.message == nil {
+				status, data := request(t, test.method, test.path, test.token, test.body)
+				assert.Equal(t, test.status, status, string(data))
+				if test.check != nil {
+					test.check(t, data)
+				}
+				return
+			}
+
+			// The code of the problem does not depend on the language.
+			code := ""
+			for _, lang := range languages {
+				status, data := request(t, test.method, withLanguage(test.path, lang), test.token, test.body)
+				assert.Equal(t, test.status, status, lang)
+
+				response := &httphelper.Problem{}
+				assert.Nil(t, json.Unmarshal(data, response), lang)
+				assert.Equal(t, test.status, response.Status, lang)
+				assert.Equal(t, test.message(i18n.I18nTranslations[lang]).Error(), response.Detail, lang)
+				assert.NotEmpty(t, response.Code, lang)
+				if code != "" {
+					assert.Equal(t, code, response.Code, lang)
+				}
+				code = response.Code
+			}
+		})
+	}
+}
+
+// go test -run TestTranslations
+func TestTranslations(t *testing.T) {
+	// The route tests compare the messages of every language, they must be translated.
+	for _, lang := range languages[1:] {
+		assert.NotEqual(t, i18n.I18nTranslations[languages[0]].ErrSiteNotFound.Error(), i18n.I18nTranslations[lang].ErrSiteNotFound.Error(), lang)
+	}
+}
+
+// go test -run TestMiscRoutes
+func TestMiscRoutes(t *testing.T) {
+	runRouteTests(t, []routeTest{
+		{name: "health", method: fiber.MethodGet, path: "/", status: http.StatusOK},
+		{name: "liveness", method: fiber.MethodGet, path: "/healthz", status: http.StatusOK, check: hasField("status", health.StatusUp)},
+		{name: "readiness without listening", method: fiber.MethodGet, path: "/readyz", status: http.StatusServiceUnavailable, check: func(t *testing.T, data []byte) {
+			report := &health.Report{}
+			assert.Nil(t, json.Unmarshal(data, report))
+			assert.Equal(t, health.StatusDown, report.Status)
+			assert.Equal(t, map[string]health.Component{
+				"server": {Status: health.StatusDown, Error: errNotListening.Error()},
+				"keys":   {Status: health.StatusUp},
+			}, report.Components)
+		}},
+		{name: "monitor without token", method: fiber.MethodGet, path: "/monitor", status: http.StatusUnauthorized},
+		{name: "monitor", method: fiber.MethodGet, path: "/monitor", token: adminToken, status: http.StatusOK},
+		{name: "jwks", method: fiber.MethodGet, path: "/.well-known/jwks.json", status: http.StatusOK, check: func(t *testing.T, data []byte) {
+			assert.Contains(t, string(data), `"keys"`)
+		}},
+		{name: "nonexistent route", method: fiber.MethodGet, path: "/nonexistent", status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrorNonexistentRoute }},
+	})
+}
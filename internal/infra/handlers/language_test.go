@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestRequestLanguage
+func TestRequestLanguage(t *testing.T) {
+	const (
+		mail     string = "portuguese@admin.com"
+		password string = "Portuguese-Passw0rd"
+	)
+
+	user := create(t, "/user", &dto.UserInputDTO{Name: ptr("Portuguese user"), Email: ptr(mail), Status: ptr(true), ProfileID: &userProfileID, Language: ptr("pt-BR")})
+	status, data := request(t, fiber.MethodPatch, "/user/"+mail+"/passw", "", &dto.PasswordInputDTO{Token: ptr(lastMailToken(t)), Password: ptr(password), PasswordConfirm: ptr(password)})
+	if !assert.Equal(t, http.StatusOK, status, string(data)) {
+		t.FailNow()
+	}
+	token := login(mail, password).AccessToken
+
+	detail := func(path, token, acceptLanguage string) string {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		}
+		if acceptLanguage != "" {
+			req.Header.Set(fiber.HeaderAcceptLanguage, acceptLanguage)
+		}
+
+		resp, err := app.Test(req, -1)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		defer resp.Body.Close()
+
+		problem := &httphelper.Problem{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(problem))
+		return problem.Detail
+	}
+
+	english := i18n.I18nTranslations["en"].ErrAccountNotFound.Error()
+	portuguese := i18n.I18nTranslations["pt"].ErrAccountNotFound.Error()
+	tests := []struct {
+		name           string
+		path           string
+		token          string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "default", path: "/account/999999", token: adminToken, expected: english},
+		{name: "query", path: "/account/999999?lang=pt", token: adminToken, expected: portuguese},
+		{name: "query with region", path: "/account/999999?lang=pt_BR", token: adminToken, expected: portuguese},
+		{name: "one character query", path: "/account/999999?lang=p", token: adminToken, expected: english},
+		{name: "unsupported query", path: "/account/999999?lang=de", token: adminToken, expected: english},
+		{name: "header", path: "/account/999999", token: adminToken, acceptLanguage: "pt-BR,pt;q=0.9,en;q=0.8", expected: portuguese},
+		{name: "header weights", path: "/account/999999", token: adminToken, acceptLanguage: "de;q=1.0,en;q=0.9,pt;q=0.5", expected: english},
+		{name: "query over header", path: "/account/999999?lang=en", token: adminToken, acceptLanguage: "pt-BR", expected: english},
+		{name: "user preference", path: "/account/999999", token: token, expected: portuguese},
+		{name: "header over user preference", path: "/account/999999", token: token, acceptLanguage: "en-US", expected: english},
+		{name: "query over user preference", path: "/account/999999?lang=en", token: token, expected: english},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, detail(test.path, test.token, test.acceptLanguage))
+		})
+	}
+
+	runRouteTests(t, []routeTest{
+		{name: "update language", method: fiber.MethodPut, path: idPath("/user", user), token: adminToken, body: &dto.UserInputDTO{Language: ptr("en")}, status: http.StatusOK, check: hasField("language", "en")},
+		{name: "update invalid language", method: fiber.MethodPut, path: idPath("/user", user), token: adminToken, body: &dto.UserInputDTO{Language: ptr("not a language")}, status: http.StatusBadRequest, check: hasMessage},
+	})
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestRequestLogger
+func TestRequestLogger(t *testing.T) {
+	output := &bytes.Buffer{}
+	logger, err := logging.New(output, "info", logging.FormatJSON)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(logger)
+
+	// A second server over the same repositories, the shared app is created without the request logs.
+	cfg := *config
+	cfg.API.Logger = true
+	server, err := NewServerWithRepositories(repositories, &cfg)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	req := httptest.NewRequest(fiber.MethodGet, "/user/999999", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+adminToken)
+	req.Header.Set(fiber.HeaderXRequestID, "request-1")
+	resp, err := server.App().Test(req, -1)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	assert.NotContains(t, output.String(), adminToken)
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	record := map[string]any{}
+	if !assert.Nil(t, json.Unmarshal([]byte(lines[len(lines)-1]), &record)) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "request", record["msg"])
+	assert.Equal(t, "request-1", record["request_id"])
+	assert.Equal(t, float64(adminID), record["user_id"])
+	assert.Equal(t, "/user/:id", record["route"])
+	assert.Equal(t, float64(http.StatusNotFound), record["status"])
+	assert.Contains(t, record, "latency")
+}
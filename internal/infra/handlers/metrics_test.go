@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestMetricsRoute
+func TestMetricsRoute(t *testing.T) {
+	const metricsToken string = "metrics-token"
+
+	// A second server over the same repositories, the shared app is created without metrics.
+	cfg := *config
+	cfg.Metrics.Enabled = true
+	cfg.Metrics.Token = metricsToken
+	server, err := NewServerWithRepositories(repositories, &cfg)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	send := func(req *http.Request) (int, string) {
+		resp, err := server.App().Test(req, -1)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	req := httptest.NewRequest(fiber.MethodPost, "/auth", strings.NewReader(`{"email":"`+adminMail+`","password":"wrong"}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	status, _ := send(req)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = send(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	req = httptest.NewRequest(fiber.MethodGet, "/metrics", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+metricsToken)
+	status, body := send(req)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `gopass_http_requests_total{method="POST",route="/auth",status="401"} 1`)
+	assert.Contains(t, body, `gopass_logins_total{result="failure"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+
+	status, _ = request(t, fiber.MethodGet, "/metrics", adminToken, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+)
+
+// go test -run TestOperatorRoutes
+func TestOperatorRoutes(t *testing.T) {
+	operator := create(t, "/operator", &dto.OperatorInputDTO{Name: ptr("Vivo")})
+	used := create(t, "/operator", &dto.OperatorInputDTO{Name: ptr("Claro")})
+	create(t, "/phone", &dto.PhoneInputDTO{Number: ptr("5511900000002"), OperatorID: &used})
+	readToken := personalToken(t, domain.ScopeOperatorRead)
+
+	runRouteTests(t, []routeTest{
+		{name: "list", method: fiber.MethodGet, path: "/operator?search=viv", token: adminToken, status: http.StatusOK, check: hasCount(1)},
+		{name: "get", method: fiber.MethodGet, path: idPath("/operator", operator), token: adminToken, status: http.StatusOK, check: hasField("name", "Vivo")},
+		{name: "get with personal token", method: fiber.MethodGet, path: idPath("/operator", operator), token: readToken, status: http.StatusOK, check: hasField("name", "Vivo")},
+		{name: "get missing", method: fiber.MethodGet, path: "/operator/999999", token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrOperatorNotFound }},
+		{name: "get invalid id", method: fiber.MethodGet, path: "/operator/0", token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidId }},
+		{name: "create invalid body", method: fiber.MethodPost, path: "/operator", token: adminToken, body: "{", status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidDatas }},
+		{name: "create short name", method: fiber.MethodPost, path: "/operator", token: adminToken, body: &dto.OperatorInputDTO{Name: ptr("X")}, status: http.StatusBadRequest, check: hasMessage},
+		{name: "create duplicated", method: fiber.MethodPost, path: "/operator", token: adminToken, body: &dto.OperatorInputDTO{Name: ptr("Vivo")}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrOperatorRegistered }},
+		{name: "create with personal token", method: fiber.MethodPost, path: "/operator", token: readToken, body: &dto.OperatorInputDTO{Name: ptr("Oi")}, status: http.StatusForbidden, message: func(tr *i18n.Translation) error { return tr.ErrTokenScope }},
+		{name: "update", method: fiber.MethodPut, path: idPath("/operator", operator), token: adminToken, body: &dto.OperatorInputDTO{Name: ptr("Vivo S.A.")}, status: http.StatusOK, check: hasField("name", "Vivo S.A.")},
+		{name: "update duplicated", method: fiber.MethodPut, path: idPath("/operator", operator), token: adminToken, body: &dto.OperatorInputDTO{Name: ptr("Claro")}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrOperatorRegistered }},
+		{name: "delete used", method: fiber.MethodDelete, path: idPath("/operator", used), token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrOperatorUsed }},
+		{name: "delete", method: fiber.MethodDelete, path: idPath("/operator", operator), token: adminToken, status: http.StatusNoContent},
+		{name: "delete missing", method: fiber.MethodDelete, path: idPath("/operator", operator), token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrOperatorNotFound }},
+		{name: "invalid token", method: fiber.MethodGet, path: "/operator", token: "invalid", status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+	})
+}
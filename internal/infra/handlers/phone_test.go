@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+)
+
+// go test -run TestPhoneRoutes
+func TestPhoneRoutes(t *testing.T) {
+	operator := create(t, "/operator", &dto.OperatorInputDTO{Name: ptr("TIM")})
+	phone := create(t, "/phone", &dto.PhoneInputDTO{Number: ptr("5521900000003"), OperatorID: &operator})
+	used := create(t, "/phone", &dto.PhoneInputDTO{Number: ptr("5521900000004"), OperatorID: &operator})
+	site := create(t, "/site", &dto.SiteInputDTO{Name: ptr("Phone site"), URL: ptr("https://phone.example.com")})
+	create(t, "/account", &dto.AccountInputDTO{Username: ptr("phone-user"), Password: ptr("secret"), SiteID: &site, PhoneID: &used})
+	readToken := personalToken(t, domain.ScopePhoneRead)
+
+	runRouteTests(t, []routeTest{
+		{name: "list", method: fiber.MethodGet, path: "/phone?search=5521", token: adminToken, status: http.StatusOK, check: hasCount(2)},
+		{name: "get", method: fiber.MethodGet, path: idPath("/phone", phone), token: adminToken, status: http.StatusOK, check: hasField("number", "5521900000003")},
+		{name: "get with personal token", method: fiber.MethodGet, path: idPath("/phone", phone), token: readToken, status: http.StatusOK, check: hasField("number", "5521900000003")},
+		{name: "get missing", method: fiber.MethodGet, path: "/phone/999999", token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrPhoneNotFound }},
+		{name: "create invalid body", method: fiber.MethodPost, path: "/phone", token: adminToken, body: "{", status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidDatas }},
+		{name: "create without operator", method: fiber.MethodPost, path: "/phone", token: adminToken, body: &dto.PhoneInputDTO{Number: ptr("5521900000005")}, status: http.StatusBadRequest, check: hasMessage},
+		{name: "create missing operator", method: fiber.MethodPost, path: "/phone", token: adminToken, body: &dto.PhoneInputDTO{Number: ptr("5521900000005"), OperatorID: ptr(uint(999999))}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrPhoneNotFound }},
+		{name: "create duplicated", method: fiber.MethodPost, path: "/phone", token: adminToken, body: &dto.PhoneInputDTO{Number: ptr("5521900000003"), OperatorID: &operator}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrPhoneRegistered }},
+		{name: "create with personal token", method: fiber.MethodPost, path: "/phone", token: readToken, body: &dto.PhoneInputDTO{Number: ptr("5521900000005"), OperatorID: &operator}, status: http.StatusForbidden, message: func(tr *i18n.Translation) error { return tr.ErrTokenScope }},
+		{name: "update", method: fiber.MethodPut, path: idPath("/phone", phone), token: adminToken, body: &dto.PhoneInputDTO{Number: ptr("5521900000006")}, status: http.StatusOK, check: hasField("number", "5521900000006")},
+		{name: "update duplicated", method: fiber.MethodPut, path: idPath("/phone", phone), token: adminToken, body: &dto.PhoneInputDTO{Number: ptr("5521900000004")}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrPhoneRegistered }},
+		{name: "delete used", method: fiber.MethodDelete, path: idPath("/phone", used), token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrPhoneUsed }},
+		{name: "delete", method: fiber.MethodDelete, path: idPath("/phone", phone), token: adminToken, status: http.StatusNoContent},
+		{name: "delete missing", method: fiber.MethodDelete, path: idPath("/phone", phone), token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrPhoneNotFound }},
+		{name: "invalid token", method: fiber.MethodGet, path: "/phone", token: "invalid", status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	httphelper "github.com/raulaguila/go-pass/pkg/http-helper"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestProblemResponses
+func TestProblemResponses(t *testing.T) {
+	send := func(method, path string, body interface{}) (*http.Response, *httphelper.Problem) {
+		data, err := json.Marshal(body)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest(method, path, bytes.NewReader(data))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+adminToken)
+		req.Header.Set(fiber.HeaderXRequestID, "problem-request")
+		resp, err := app.Test(req, -1)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		defer resp.Body.Close()
+
+		problem := &httphelper.Problem{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(problem))
+		return resp, problem
+	}
+
+	resp, problem := send(fiber.MethodGet, "/user/999999?lang=pt", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, httphelper.MIMEProblemJSON, resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, &httphelper.Problem{
+		Type:      "urn:go-pass:problem:user_not_found",
+		Title:     "Não encontrado",
+		Status:    http.StatusNotFound,
+		Detail:    "Usuário não encontrado.",
+		Instance:  "/user/999999",
+		Code:      "user_not_found",
+		RequestID: "problem-request",
+	}, problem)
+
+	// Every broken rule is listed.
+	resp, problem = send(fiber.MethodPost, "/user?lang=en", &dto.UserInputDTO{Name: ptr("abc"), Email: ptr("invalid"), Status: ptr(true), ProfileID: &userProfileID})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "invalid_fields", problem.Code)
+	assert.Equal(t, "Bad request", problem.Title)
+	if assert.Len(t, problem.Errors, 2) {
+		assert.Equal(t, "name", problem.Errors[0].Field)
+		assert.Equal(t, "min", problem.Errors[0].Rule)
+		assert.Equal(t, "5", problem.Errors[0].Param)
+		assert.Equal(t, "name must have at least 5 characters.", problem.Errors[0].Message)
+		assert.Equal(t, "mail", problem.Errors[1].Field)
+		assert.Equal(t, "email", problem.Errors[1].Rule)
+		assert.Equal(t, "mail must be a valid email address.", problem.Errors[1].Message)
+	}
+
+	// The messages of the rules follow the language of the request.
+	resp, problem = send(fiber.MethodPost, "/user?lang=pt", &dto.UserInputDTO{Name: ptr("abc"), Email: ptr("invalid"), Status: ptr(true), ProfileID: &userProfileID})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	if assert.Len(t, problem.Errors, 2) {
+		assert.Equal(t, "name deve ter no mínimo 5 caracteres.", problem.Errors[0].Message)
+		assert.Equal(t, "mail deve ser um endereço de email válido.", problem.Errors[1].Message)
+	}
+
+	// The errors without their own code, like the missing token, are coded by the status.
+	req := httptest.NewRequest(fiber.MethodGet, "/user", nil)
+	resp, err := app.Test(req, -1)
+	if assert.Nil(t, err) {
+		defer resp.Body.Close()
+		problem := &httphelper.Problem{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(problem))
+		assert.Equal(t, http.StatusUnauthorized, problem.Status)
+		assert.Equal(t, "unauthorized", problem.Code)
+		assert.NotEmpty(t, problem.RequestID)
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestProfileRoutes
+func TestProfileRoutes(t *testing.T) {
+	profile := create(t, "/profile", &dto.ProfileInputDTO{Name: ptr("AUDITOR"), Permissions: dto.PermissionsInputDTO{UserModule: ptr(true)}})
+	readToken := personalToken(t, domain.ScopeProfileRead)
+
+	runRouteTests(t, []routeTest{
+		{name: "list", method: fiber.MethodGet, path: "/profile?search=audit", token: adminToken, status: http.StatusOK, check: hasCount(1)},
+		{name: "get", method: fiber.MethodGet, path: idPath("/profile", rootProfileID), token: adminToken, status: http.StatusOK, check: hasField("name", "ROOT")},
+		{name: "get with personal token", method: fiber.MethodGet, path: idPath("/profile", rootProfileID), token: readToken, status: http.StatusOK, check: hasField("name", "ROOT")},
+		{name: "get missing", method: fiber.MethodGet, path: "/profile/999999", token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrProfileNotFound }},
+		{name: "get invalid id", method: fiber.MethodGet, path: "/profile/-1", token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidId }},
+		{name: "create invalid body", method: fiber.MethodPost, path: "/profile", token: adminToken, body: "{", status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidDatas }},
+		{name: "create short name", method: fiber.MethodPost, path: "/profile", token: adminToken, body: &dto.ProfileInputDTO{Name: ptr("ABC")}, status: http.StatusBadRequest, check: hasMessage},
+		{name: "create duplicated", method: fiber.MethodPost, path: "/profile", token: adminToken, body: &dto.ProfileInputDTO{Name: ptr("ROOT")}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrProfileRegistered }},
+		{name: "create with personal token", method: fiber.MethodPost, path: "/profile", token: readToken, body: &dto.ProfileInputDTO{Name: ptr("GUEST")}, status: http.StatusForbidden, message: func(tr *i18n.Translation) error { return tr.ErrTokenScope }},
+		{name: "update", method: fiber.MethodPut, path: idPath("/profile", profile), token: adminToken, body: &dto.ProfileInputDTO{Name: ptr("AUDITORS"), Permissions: dto.PermissionsInputDTO{ProfileModule: ptr(true)}}, status: http.StatusOK, check: func(t *testing.T, data []byte) {
+			hasField("name", "AUDITORS")(t, data)
+			hasField("permissions", map[string]interface{}{"user_module": true, "profile_module": true})(t, data)
+		}},
+		{name: "update duplicated", method: fiber.MethodPut, path: idPath("/profile", profile), token: adminToken, body: &dto.ProfileInputDTO{Name: ptr("USER")}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrProfileRegistered }},
+		{name: "delete used", method: fiber.MethodDelete, path: idPath("/profile", userProfileID), token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrProfileUsed }},
+		{name: "delete", method: fiber.MethodDelete, path: idPath("/profile", profile), token: adminToken, status: http.StatusNoContent},
+		{name: "delete missing", method: fiber.MethodDelete, path: idPath("/profile", profile), token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrProfileNotFound }},
+		{name: "without token", method: fiber.MethodGet, path: "/profile", status: http.StatusUnauthorized, check: hasMessage},
+		{name: "invalid token", method: fiber.MethodGet, path: "/profile", token: "invalid", status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+	})
+}
+
+// go test -run TestListDefaultOrder
+func TestListDefaultOrder(t *testing.T) {
+	// A second server listing by name in ascending order, the shared app keeps its own defaults.
+	cfg := *config
+	cfg.API.DefaultSort, cfg.API.DefaultOrder = "name", "ASC"
+	server, err := NewServerWithRepositories(repositories, &cfg)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	names := func(app *fiber.App, path string) []string {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+adminToken)
+		resp, err := app.Test(req, -1)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, string(data))
+
+		response := &struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+		}{}
+		assert.Nil(t, json.Unmarshal(data, response))
+		names := []string{}
+		for _, item := range response.Items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	ascending := names(server.App(), "/profile")
+	assert.Greater(t, len(ascending), 1)
+	assert.True(t, sort.StringsAreSorted(ascending), ascending)
+
+	descending := names(app, "/profile?sort=name")
+	assert.True(t, sort.SliceIsSorted(descending, func(i, j int) bool { return descending[i] > descending[j] }), descending)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestSecretRoutes
+func TestSecretRoutes(t *testing.T) {
+	const (
+		ref        string = "gopass://site/vaultsite/account/deploy#password"
+		invalidRef string = "gopass://site/vaultsite"
+		missingRef string = "gopass://site/vaultsite/account/missing#password"
+		dupRef     string = "gopass://site/dupsite/account/deploy#password"
+	)
+
+	site := create(t, "/site", &dto.SiteInputDTO{Name: ptr("Vaultsite"), URL: ptr("https://vault.example.com")})
+	operator := create(t, "/operator", &dto.OperatorInputDTO{Name: ptr("Secret operator")})
+	phone := create(t, "/phone", &dto.PhoneInputDTO{Number: ptr("5531900000003"), OperatorID: &operator})
+	create(t, "/account", &dto.AccountInputDTO{Username: ptr("deploy"), Password: ptr("deploy-secret"), SiteID: &site, PhoneID: &phone})
+	for _, name := range []string{"Dupsite", "DUPSITE"} {
+		dup := create(t, "/site", &dto.SiteInputDTO{Name: ptr(name), URL: ptr("https://" + name + ".example.com")})
+		create(t, "/account", &dto.AccountInputDTO{Username: ptr("deploy"), Password: ptr("deploy-secret"), SiteID: &dup, PhoneID: &phone})
+	}
+
+	revealToken := personalToken(t, domain.ScopeAccountRead, domain.ScopeAccountReveal)
+	readToken := personalToken(t, domain.ScopeAccountRead)
+	secrets := func(refs ...string) *dto.SecretResolveInputDTO {
+		input := &dto.SecretResolveInputDTO{}
+		for _, ref := range refs {
+			input.Secrets = append(input.Secrets, dto.SecretRefInputDTO{Ref: ref})
+		}
+		return input
+	}
+
+	runRouteTests(t, []routeTest{
+		{name: "resolve", method: fiber.MethodPost, path: "/secrets/resolve", token: revealToken, body: secrets(ref), status: http.StatusOK, check: func(t *testing.T, data []byte) {
+			assert.Contains(t, string(data), `"deploy-secret"`)
+
+			logs := db.AuditLogs()
+			if assert.NotEmpty(t, logs) {
+				assert.Equal(t, ref, logs[len(logs)-1].Target)
+				assert.True(t, logs[len(logs)-1].Success)
+			}
+		}},
+		{name: "resolve dotenv", method: fiber.MethodPost, path: "/secrets/resolve?format=dotenv", token: adminToken, body: secrets(ref), status: http.StatusOK, check: func(t *testing.T, data []byte) {
+			assert.Equal(t, "VAULTSITE_DEPLOY_PASSWORD=\"deploy-secret\"\n", string(data))
+		}},
+		{name: "invalid format", method: fiber.MethodPost, path: "/secrets/resolve?format=xml", token: adminToken, body: secrets(ref), status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidFormat }},
+		{name: "invalid reference", method: fiber.MethodPost, path: "/secrets/resolve", token: adminToken, body: secrets(ref, invalidRef), status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return fmt.Errorf("%v (%v)", tr.ErrInvalidSecretRef, invalidRef) }},
+		{name: "failed batch", method: fiber.MethodPost, path: "/secrets/resolve", token: adminToken, body: secrets(ref, missingRef), status: http.StatusNotFound, check: func(t *testing.T, data []byte) {
+			logs := db.AuditLogs()
+			if assert.GreaterOrEqual(t, len(logs), 2) {
+				for i, target := range []string{ref, missingRef} {
+					log := logs[len(logs)-2+i]
+					assert.Equal(t, target, log.Target)
+					assert.False(t, log.Success)
+					assert.Contains(t, log.Detail, missingRef)
+				}
+			}
+		}},
+		{name: "ambiguous reference", method: fiber.MethodPost, path: "/secrets/resolve", token: adminToken, body: secrets(dupRef), status: http.StatusConflict, message: func(tr *i18n.Translation) error { return fmt.Errorf("%v (%v)", tr.ErrAmbiguousSecretRef, dupRef) }},
+		{name: "without secrets", method: fiber.MethodPost, path: "/secrets/resolve", token: adminToken, body: secrets(), status: http.StatusBadRequest, check: hasMessage},
+		{name: "missing account", method: fiber.MethodPost, path: "/secrets/resolve", token: adminToken, body: secrets(missingRef), status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return fmt.Errorf("%v (%v)", tr.ErrAccountNotFound, missingRef) }},
+		{name: "without reveal scope", method: fiber.MethodPost, path: "/secrets/resolve", token: readToken, body: secrets(ref), status: http.StatusForbidden, message: func(tr *i18n.Translation) error { return tr.ErrTokenScope }},
+	})
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/configs"
+	"github.com/raulaguila/go-pass/internal/infra/database"
+	"github.com/raulaguila/go-pass/internal/pkg/metrics"
+	"github.com/raulaguila/go-pass/pkg/health"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/raulaguila/go-pass/pkg/ldapauth"
+	"github.com/raulaguila/go-pass/pkg/mailer"
+	"github.com/raulaguila/go-pass/pkg/tracing"
+	"gorm.io/gorm"
+)
+
+// Time limit of the readiness checks, below the probe timeouts.
+const readinessTimeout time.Duration = 3 * time.Second
+
+var errNotListening error = errors.New("not listening or shutting down")
+
+// Server is the API with its configuration, database, repositories and services.
+type Server struct {
+	cfg          *configs.Config
+	db           *gorm.DB
+	repositories *Repositories
+	services     *Services
+	app          *fiber.App
+
+	accessKeyring     *keyring.Keyring
+	refreshKeyring    *keyring.Keyring
+	directory         *ldapauth.Directory
+	directoryGroupMap ldapauth.GroupMap
+	mailSender        mailer.Sender
+
+	metrics       *metrics.Metrics
+	metricsServer *http.Server
+	tracing       *tracing.Provider
+
+	ready    atomic.Bool
+	addr     atomic.Value
+	jobs     sync.WaitGroup
+	stopJobs context.CancelFunc
+}
+
+// NewServer creates the API over the database, without listening.
+func NewServer(db *gorm.DB, cfg *configs.Config) (*Server, error) {
+	s := &Server{cfg: cfg, db: db}
+	if err := s.initDependencies(); err != nil {
+		return nil, err
+	}
+
+	return s, s.init(s.newRepositories())
+}
+
+// NewServerWithRepositories creates the API over the given repositories, like the in-memory ones of the tests.
+func NewServerWithRepositories(repositories *Repositories, cfg *configs.Config) (*Server, error) {
+	s := &Server{cfg: cfg}
+	if err := s.initDependencies(); err != nil {
+		return nil, err
+	}
+
+	return s, s.init(repositories)
+}
+
+func (s *Server) init(repositories *Repositories) error {
+	s.repositories = repositories
+	s.initServices()
+	if err := s.initMetrics(); err != nil {
+		return err
+	}
+	if err := s.initTracing(); err != nil {
+		return err
+	}
+
+	var err error
+	s.app, err = s.newApp()
+	return err
+}
+
+// initMetrics creates the collectors when the metrics are enabled, with the pool stats of the database.
+func (s *Server) initMetrics() error {
+	if !s.cfg.Metrics.Enabled {
+		return nil
+	}
+
+	var sqlDB *sql.DB
+	if s.db != nil {
+		var err error
+		if sqlDB, err = s.db.DB(); err != nil {
+			return err
+		}
+	}
+
+	s.metrics = metrics.New(sqlDB)
+	return nil
+}
+
+// initTracing sets the global tracer provider when an exporter is configured, the spans are no-ops otherwise.
+func (s *Server) initTracing() error {
+	var err error
+	s.tracing, err = tracing.New(context.Background(), tracing.Options{
+		Exporter:       s.cfg.Tracing.Exporter,
+		Endpoint:       s.cfg.Tracing.Endpoint,
+		Insecure:       s.cfg.Tracing.Insecure,
+		ServiceName:    "go-pass",
+		ServiceVersion: s.cfg.Version,
+	})
+	return err
+}
+
+// startMetricsServer serves the metrics on their own address, when configured, instead of the API port.
+func (s *Server) startMetricsServer() {
+	if s.metrics == nil || s.cfg.Metrics.Address == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.metrics.Handler(s.cfg.Metrics.Token))
+	s.metricsServer = &http.Server{Addr: s.cfg.Metrics.Address, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := s.metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("could not serve the metrics", "address", s.cfg.Metrics.Address, "error", err)
+		}
+	}()
+}
+
+// App returns the API, to serve it without the server lifecycle, like the app.Test of the tests.
+func (s *Server) App() *fiber.App {
+	return s.app
+}
+
+// Ready reports whether the server is listening and not shutting down.
+func (s *Server) Ready() bool {
+	return s.ready.Load()
+}
+
+// Addr returns the address the server listens on, empty before listening.
+func (s *Server) Addr() string {
+	addr, _ := s.addr.Load().(string)
+	return addr
+}
+
+// newReadiness checks whether the instance can serve: listening, with the database reachable and migrated and the
+// signing keys parsed. The database is not checked when the server runs over other repositories.
+func (s *Server) newReadiness() (*health.Checker, error) {
+	checker := health.New(readinessTimeout)
+	checker.Add("server", func(context.Context) error {
+		if !s.Ready() {
+			return errNotListening
+		}
+		return nil
+	})
+	checker.Add("keys", func(context.Context) error {
+		if s.accessKeyring == nil || s.accessKeyring.Active() == nil || s.refreshKeyring == nil || s.refreshKeyring.Active() == nil {
+			return keyring.ErrNoSigningKey
+		}
+		return nil
+	})
+
+	if s.db == nil {
+		return checker, nil
+	}
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := database.NewMigrator(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	checker.Add("database", sqlDB.PingContext)
+	checker.Add("migrations", func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%v pending migrations", len(pending))
+		}
+		return nil
+	})
+
+	return checker, nil
+}
+
+// Start listens on the API port and runs the background jobs until the context is canceled or the process receives
+// an interrupt or terminate signal, then shuts down the server within the configured timeout.
+func (s *Server) Start(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	jobs, stopJobs := context.WithCancel(context.Background())
+	s.stopJobs = stopJobs
+	s.startDirectorySync(jobs, s.cfg.LDAP.SyncInterval)
+	s.startMetricsServer()
+
+	listened := make(chan error, 1)
+	go func() {
+		listened <- s.app.Listen(":" + s.cfg.API.Port)
+	}()
+
+	select {
+	case err := <-listened:
+		// The listen failed, like on a port in use, there is nothing to drain.
+		return errors.Join(err, s.Shutdown(context.Background()))
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, waiting for the requests in progress")
+	shutdown, cancel := context.WithTimeout(context.Background(), s.cfg.API.ShutdownTimeout)
+	defer cancel()
+
+	err := s.Shutdown(shutdown)
+	return errors.Join(err, <-listened)
+}
+
+// Shutdown stops accepting connections and waits for the requests in progress and the background jobs until the
+// context is done, then closes the database pool.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.ready.Store(false)
+
+	errs := []error{}
+	if err := s.app.ShutdownWithContext(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := s.tracing.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if s.stopJobs != nil {
+		s.stopJobs()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		errs = append(errs, ctx.Err())
+	}
+
+	if s.db != nil {
+		sqlDB, err := s.db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
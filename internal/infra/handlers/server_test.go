@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/infra/database"
+	"github.com/raulaguila/go-pass/pkg/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func freePort(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer ln.Close()
+
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	assert.Nil(t, err)
+	return port
+}
+
+// go test -run TestServerLifecycle
+func TestServerLifecycle(t *testing.T) {
+	// A second server over the same repositories, the shared app keeps the middlewares it was created with.
+	cfg := *config
+	cfg.API.Port = freePort(t)
+	server, err := NewServerWithRepositories(repositories, &cfg)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.False(t, server.Ready())
+	assert.Empty(t, server.Addr())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- server.Start(ctx)
+	}()
+
+	if !assert.Eventually(t, server.Ready, 5*time.Second, 10*time.Millisecond) {
+		t.FailNow()
+	}
+	assert.NotEmpty(t, server.Addr())
+
+	resp, err := http.Get("http://127.0.0.1:" + cfg.API.Port + "/readyz")
+	if assert.Nil(t, err) {
+		report := &health.Report{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(report))
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.True(t, report.Up(), report.Components)
+	}
+
+	cancel()
+	select {
+	case err := <-stopped:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server did not stop")
+	}
+	assert.False(t, server.Ready())
+
+	_, err = http.Get("http://127.0.0.1:" + cfg.API.Port + "/")
+	assert.NotNil(t, err)
+}
+
+// go test -run TestServerReadiness
+func TestServerReadiness(t *testing.T) {
+	cfg := *config
+	cfg.SQLite.Path = filepath.Join(t.TempDir(), "test.db")
+	sqlDB := database.OpenSQLiteDB(cfg.SQLite)
+
+	server, err := NewServer(sqlDB, &cfg)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer server.Shutdown(context.Background())
+
+	readiness := func() *health.Report {
+		req := httptest.NewRequest(fiber.MethodGet, "/readyz", nil)
+		resp, err := server.App().Test(req, -1)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		defer resp.Body.Close()
+
+		report := &health.Report{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(report))
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		return report
+	}
+
+	report := readiness()
+	assert.Equal(t, health.StatusUp, report.Components["database"].Status)
+	assert.Equal(t, health.StatusDown, report.Components["migrations"].Status)
+
+	migrator, err := database.NewMigrator(sqlDB)
+	assert.Nil(t, err)
+	_, err = migrator.Up(context.Background())
+	assert.Nil(t, err)
+
+	report = readiness()
+	assert.Equal(t, health.StatusUp, report.Components["database"].Status)
+	assert.Equal(t, health.StatusUp, report.Components["migrations"].Status)
+	assert.Equal(t, health.StatusDown, report.Components["server"].Status)
+}
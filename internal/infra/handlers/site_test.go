@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+)
+
+// go test -run TestSiteRoutes
+func TestSiteRoutes(t *testing.T) {
+	site := create(t, "/site", &dto.SiteInputDTO{Name: ptr("Forgelab"), URL: ptr("https://forgelab.com")})
+	used := create(t, "/site", &dto.SiteInputDTO{Name: ptr("Forgebucket"), URL: ptr("https://forgebucket.org")})
+	operator := create(t, "/operator", &dto.OperatorInputDTO{Name: ptr("Site operator")})
+	phone := create(t, "/phone", &dto.PhoneInputDTO{Number: ptr("5511900000001"), OperatorID: &operator})
+	create(t, "/account", &dto.AccountInputDTO{Username: ptr("site-user"), Password: ptr("secret"), SiteID: &used, PhoneID: &phone})
+	readToken := personalToken(t, domain.ScopeSiteRead)
+
+	runRouteTests(t, []routeTest{
+		{name: "list", method: fiber.MethodGet, path: "/site?search=forgel", token: adminToken, status: http.StatusOK, check: hasCount(1)},
+		{name: "list page", method: fiber.MethodGet, path: "/site?search=forge&page=1&limit=1", token: adminToken, status: http.StatusOK, check: hasCount(2)},
+		{name: "list with personal token", method: fiber.MethodGet, path: "/site?search=forgel", token: readToken, status: http.StatusOK, check: hasCount(1)},
+		{name: "get", method: fiber.MethodGet, path: idPath("/site", site), token: adminToken, status: http.StatusOK, check: hasField("name", "Forgelab")},
+		{name: "get missing", method: fiber.MethodGet, path: "/site/999999", token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrSiteNotFound }},
+		{name: "get invalid id", method: fiber.MethodGet, path: "/site/abc", token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidId }},
+		{name: "create invalid body", method: fiber.MethodPost, path: "/site", token: adminToken, body: "{", status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidDatas }},
+		{name: "create without url", method: fiber.MethodPost, path: "/site", token: adminToken, body: &dto.SiteInputDTO{Name: ptr("Gitea")}, status: http.StatusBadRequest, check: hasMessage},
+		{name: "create duplicated", method: fiber.MethodPost, path: "/site", token: adminToken, body: &dto.SiteInputDTO{Name: ptr("Forgelab"), URL: ptr("https://forgelab.org")}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrSiteRegistered }},
+		{name: "create with personal token", method: fiber.MethodPost, path: "/site", token: readToken, body: &dto.SiteInputDTO{Name: ptr("Gitea"), URL: ptr("https://gitea.com")}, status: http.StatusForbidden, message: func(tr *i18n.Translation) error { return tr.ErrTokenScope }},
+		{name: "update", method: fiber.MethodPut, path: idPath("/site", site), token: adminToken, body: &dto.SiteInputDTO{Name: ptr("ForgeLab")}, status: http.StatusOK, check: hasField("name", "ForgeLab")},
+		{name: "update duplicated", method: fiber.MethodPut, path: idPath("/site", site), token: adminToken, body: &dto.SiteInputDTO{URL: ptr("https://forgebucket.org")}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrSiteRegistered }},
+		{name: "update missing", method: fiber.MethodPut, path: "/site/999999", token: adminToken, body: &dto.SiteInputDTO{}, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrSiteNotFound }},
+		{name: "delete used", method: fiber.MethodDelete, path: idPath("/site", used), token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrSiteUsed }},
+		{name: "delete", method: fiber.MethodDelete, path: idPath("/site", site), token: adminToken, status: http.StatusNoContent},
+		{name: "get deleted", method: fiber.MethodGet, path: idPath("/site", site), token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrSiteNotFound }},
+		{name: "without token", method: fiber.MethodGet, path: "/site", status: http.StatusUnauthorized, check: hasMessage},
+		{name: "invalid token", method: fiber.MethodGet, path: "/site", token: "invalid", status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+	})
+}
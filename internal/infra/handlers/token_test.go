@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestTokenRoutes
+func TestTokenRoutes(t *testing.T) {
+	secret := personalToken(t, domain.ScopeAccountRead)
+	status, data := request(t, fiber.MethodGet, "/token", adminToken, nil)
+	assert.Equal(t, http.StatusOK, status, string(data))
+
+	response := &struct {
+		Count int64 `json:"count"`
+	}{}
+	assert.Nil(t, json.Unmarshal(data, response))
+	deleted := create(t, "/token", &dto.PersonalTokenInputDTO{Name: ptr("Deleted token"), Scopes: []string{domain.ScopeAccountRead}})
+
+	runRouteTests(t, []routeTest{
+		{name: "list", method: fiber.MethodGet, path: "/token", token: adminToken, status: http.StatusOK, check: hasCount(response.Count + 1)},
+		{name: "create", method: fiber.MethodPost, path: "/token", token: adminToken, body: &dto.PersonalTokenInputDTO{Name: ptr("Created token"), Scopes: []string{domain.ScopeAccountRead}}, status: http.StatusCreated, check: func(t *testing.T, data []byte) {
+			token := &struct {
+				Token string `json:"token"`
+			}{}
+			assert.Nil(t, json.Unmarshal(data, token))
+			assert.True(t, strings.HasPrefix(token.Token, "gpp_"), token.Token)
+		}},
+		{name: "create invalid body", method: fiber.MethodPost, path: "/token", token: adminToken, body: "{", status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidDatas }},
+		{name: "create expired", method: fiber.MethodPost, path: "/token", token: adminToken, body: &dto.PersonalTokenInputDTO{Name: ptr("Expired token"), Scopes: []string{domain.ScopeAccountRead}, ExpiresAt: ptr(time.Now().Add(-time.Hour))}, status: http.StatusBadRequest, check: hasMessage},
+		{name: "create invalid scope", method: fiber.MethodPost, path: "/token", token: adminToken, body: &dto.PersonalTokenInputDTO{Name: ptr("Invalid token"), Scopes: []string{"invalid"}}, status: http.StatusBadRequest, check: hasMessage},
+		{name: "create with personal token", method: fiber.MethodPost, path: "/token", token: secret, body: &dto.PersonalTokenInputDTO{Name: ptr("Nested token"), Scopes: []string{domain.ScopeAccountRead}}, status: http.StatusForbidden, message: func(tr *i18n.Translation) error { return tr.ErrTokenScope }},
+		{name: "delete", method: fiber.MethodDelete, path: idPath("/token", deleted), token: adminToken, status: http.StatusNoContent},
+		{name: "delete missing", method: fiber.MethodDelete, path: idPath("/token", deleted), token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrTokenNotFound }},
+		{name: "delete invalid id", method: fiber.MethodDelete, path: "/token/invalid", token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidId }},
+	})
+}
+
+// go test -run TestPersonalTokenRevocation
+func TestPersonalTokenRevocation(t *testing.T) {
+	ctx := context.Background()
+	mail, password := "token.owner@email.com", "Token#Owner123"
+	user, err := repositories.User.CreateUser(ctx, &dto.UserInputDTO{Name: ptr("Token owner"), Email: ptr(mail), Status: ptr(true), ProfileID: &userProfileID})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Nil(t, repositories.User.PasswordUser(ctx, user, &dto.PasswordInputDTO{Password: ptr(password), PasswordConfirm: ptr(password)}))
+
+	// newToken creates a personal token of the user, authenticated by a new session.
+	newToken := func() string {
+		status, data := request(t, fiber.MethodPost, "/token", login(mail, password).AccessToken, &dto.PersonalTokenInputDTO{Name: ptr("Owner token"), Scopes: []string{domain.ScopeAccountRead}})
+		if !assert.Equal(t, http.StatusCreated, status, string(data)) {
+			t.FailNow()
+		}
+
+		token := &struct {
+			Token string `json:"token"`
+		}{}
+		assert.Nil(t, json.Unmarshal(data, token))
+		return token.Token
+	}
+
+	disabled := newToken()
+	assert.Nil(t, repositories.User.UpdateUser(ctx, user, &dto.UserInputDTO{Status: ptr(false)}))
+	runRouteTests(t, []routeTest{
+		{name: "disabled user", method: fiber.MethodGet, path: "/auth", token: disabled, status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrDisabledUser }},
+	})
+
+	assert.Nil(t, repositories.User.UpdateUser(ctx, user, &dto.UserInputDTO{Status: ptr(true)}))
+	changed := newToken()
+	password = "Token#Changed123"
+	assert.Nil(t, repositories.User.PasswordUser(ctx, user, &dto.PasswordInputDTO{Password: ptr(password), PasswordConfirm: ptr(password)}))
+	reset := newToken()
+	assert.Nil(t, repositories.User.ResetUser(ctx, user))
+
+	runRouteTests(t, []routeTest{
+		{name: "password changed", method: fiber.MethodGet, path: "/auth", token: changed, status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+		{name: "password reset", method: fiber.MethodGet, path: "/auth", token: reset, status: http.StatusUnauthorized, message: func(tr *i18n.Translation) error { return tr.ErrExpiredToken }},
+	})
+}
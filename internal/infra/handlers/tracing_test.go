@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// go test -run TestTraceMiddleware
+func TestTraceMiddleware(t *testing.T) {
+	const (
+		traceID string = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  string = "00f067aa0ba902b7"
+	)
+
+	// A second server over the same repositories, the shared app is created without tracing.
+	cfg := *config
+	cfg.Tracing.Exporter = "stdout"
+	server, err := NewServerWithRepositories(repositories, &cfg)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	// The spans are recorded instead of written by the stdout exporter of the server.
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer server.tracing.Shutdown(context.Background())
+
+	req := httptest.NewRequest(fiber.MethodGet, "/user/999999", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer "+adminToken)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+spanID+"-01")
+	resp, err := server.App().Test(req, -1)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 1) {
+		t.FailNow()
+	}
+
+	span := spans[0]
+	assert.Equal(t, "GET /user/:id", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+	assert.Equal(t, spanID, span.Parent().SpanID().String())
+	assert.Contains(t, span.Attributes(), semconv.HTTPRoute("/user/:id"))
+	assert.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCode(http.StatusNotFound))
+}
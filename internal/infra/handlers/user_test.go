@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestUserRoutes
+func TestUserRoutes(t *testing.T) {
+	const (
+		invitedMail     string = "invited@admin.com"
+		invitedPassword string = "Invited-Passw0rd"
+	)
+
+	invited := create(t, "/user", &dto.UserInputDTO{Name: ptr("Invited user"), Email: ptr(invitedMail), Status: ptr(true), ProfileID: &userProfileID})
+	inviteToken := lastMailToken(t)
+	deleted := create(t, "/user", &dto.UserInputDTO{Name: ptr("Deleted user"), Email: ptr("deleted@admin.com"), Status: ptr(true), ProfileID: &userProfileID})
+	readToken := personalToken(t, domain.ScopeAccountRead)
+
+	runRouteTests(t, []routeTest{
+		{name: "list", method: fiber.MethodGet, path: fmt.Sprintf("/user?search=invited&profile_id=%v", userProfileID), token: adminToken, status: http.StatusOK, check: hasCount(1)},
+		{name: "get", method: fiber.MethodGet, path: idPath("/user", invited), token: adminToken, status: http.StatusOK, check: func(t *testing.T, data []byte) {
+			hasField("mail", invitedMail)(t, data)
+			hasField("new", true)(t, data)
+		}},
+		{name: "get missing", method: fiber.MethodGet, path: "/user/999999", token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrUserNotFound }},
+		{name: "get invalid id", method: fiber.MethodGet, path: "/user/invalid", token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidId }},
+		{name: "create invalid body", method: fiber.MethodPost, path: "/user", token: adminToken, body: "{", status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidDatas }},
+		{name: "create invalid email", method: fiber.MethodPost, path: "/user", token: adminToken, body: &dto.UserInputDTO{Name: ptr("Invalid"), Email: ptr("invalid"), Status: ptr(true), ProfileID: &userProfileID}, status: http.StatusBadRequest, check: hasMessage},
+		{name: "create missing profile", method: fiber.MethodPost, path: "/user", token: adminToken, body: &dto.UserInputDTO{Name: ptr("Missing"), Email: ptr("missing@admin.com"), Status: ptr(true), ProfileID: ptr(uint(999999))}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrProfileNotFound }},
+		{name: "create duplicated", method: fiber.MethodPost, path: "/user", token: adminToken, body: &dto.UserInputDTO{Name: ptr("Duplicated"), Email: ptr(invitedMail), Status: ptr(true), ProfileID: &userProfileID}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrUserRegistered }},
+		{name: "create with personal token", method: fiber.MethodPost, path: "/user", token: readToken, body: &dto.UserInputDTO{Name: ptr("Scoped"), Email: ptr("scoped@admin.com"), Status: ptr(true), ProfileID: &userProfileID}, status: http.StatusForbidden, message: func(tr *i18n.Translation) error { return tr.ErrTokenScope }},
+		{name: "update", method: fiber.MethodPut, path: idPath("/user", invited), token: adminToken, body: &dto.UserInputDTO{Name: ptr("Invited")}, status: http.StatusOK, check: hasField("name", "Invited")},
+		{name: "update duplicated", method: fiber.MethodPut, path: idPath("/user", invited), token: adminToken, body: &dto.UserInputDTO{Email: ptr(adminMail)}, status: http.StatusConflict, message: func(tr *i18n.Translation) error { return tr.ErrUserRegistered }},
+		{name: "accept invite unmatched", method: fiber.MethodPatch, path: "/user/" + invitedMail + "/passw", body: &dto.PasswordInputDTO{Token: &inviteToken, Password: ptr(invitedPassword), PasswordConfirm: ptr("other")}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrPassUnmatch }},
+		{name: "accept invite invalid token", method: fiber.MethodPatch, path: "/user/" + invitedMail + "/passw", body: &dto.PasswordInputDTO{Token: ptr("invalid"), Password: ptr(invitedPassword), PasswordConfirm: ptr(invitedPassword)}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidUserToken }},
+		{name: "accept invite", method: fiber.MethodPatch, path: "/user/" + invitedMail + "/passw", body: &dto.PasswordInputDTO{Token: &inviteToken, Password: ptr(invitedPassword), PasswordConfirm: ptr(invitedPassword)}, status: http.StatusOK, check: hasField("new", false)},
+		{name: "accept invite again", method: fiber.MethodPatch, path: "/user/" + invitedMail + "/passw", body: &dto.PasswordInputDTO{Token: &inviteToken, Password: ptr(invitedPassword), PasswordConfirm: ptr(invitedPassword)}, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrInvalidUserToken }},
+		{name: "invite user with password", method: fiber.MethodPost, path: idPath("/user", invited) + "/invite", token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrUserHasPass }},
+	})
+
+	// The accounts belong to the user who created them, so the invited user can no longer be deleted.
+	site := create(t, "/site", &dto.SiteInputDTO{Name: ptr("User site"), URL: ptr("https://user.example.com")})
+	operator := create(t, "/operator", &dto.OperatorInputDTO{Name: ptr("User operator")})
+	phone := create(t, "/phone", &dto.PhoneInputDTO{Number: ptr("5531900000002"), OperatorID: &operator})
+	status, data := request(t, fiber.MethodPost, "/account", login(invitedMail, invitedPassword).AccessToken, &dto.AccountInputDTO{Username: ptr("invited"), Password: ptr("secret"), SiteID: &site, PhoneID: &phone})
+	assert.Equal(t, http.StatusCreated, status, string(data))
+
+	runRouteTests(t, []routeTest{
+		{name: "reset", method: fiber.MethodPatch, path: idPath("/user", invited) + "/reset", token: adminToken, status: http.StatusOK, check: hasField("new", true)},
+		{name: "invite", method: fiber.MethodPost, path: idPath("/user", invited) + "/invite", token: adminToken, status: http.StatusNoContent},
+		{name: "delete used", method: fiber.MethodDelete, path: idPath("/user", invited), token: adminToken, status: http.StatusBadRequest, message: func(tr *i18n.Translation) error { return tr.ErrUserUsed }},
+		{name: "delete", method: fiber.MethodDelete, path: idPath("/user", deleted), token: adminToken, status: http.StatusNoContent},
+		{name: "delete missing", method: fiber.MethodDelete, path: idPath("/user", deleted), token: adminToken, status: http.StatusNotFound, message: func(tr *i18n.Translation) error { return tr.ErrUserNotFound }},
+	})
+}
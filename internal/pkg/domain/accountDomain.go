@@ -43,7 +43,7 @@ type (
 
 	AccountRepository interface {
 		GetAccountByID(context.Context, uint, uint) (*Account, error)
-		GetAccountsOutputDTO(context.Context, *filter.Filter, uint) (*dto.ItemsOutputDTO, error)
+		GetAccountsOutputDTO(context.Context, *filter.AccountFilter, uint) (*dto.ItemsOutputDTO, error)
 		CreateAccount(context.Context, *dto.AccountInputDTO, uint) (*Account, error)
 		UpdateAccount(context.Context, *Account, *dto.AccountInputDTO, uint) (*Account, error)
 		DeleteAccount(context.Context, *Account, uint) error
@@ -51,7 +51,7 @@ type (
 
 	AccountService interface {
 		GetAccountByID(context.Context, uint, uint) (*Account, error)
-		GetAccountsOutputDTO(context.Context, *filter.Filter, uint) (*dto.ItemsOutputDTO, error)
+		GetAccountsOutputDTO(context.Context, *filter.AccountFilter, uint) (*dto.ItemsOutputDTO, error)
 		CreateAccount(context.Context, *dto.AccountInputDTO, uint) (*Account, error)
 		UpdateAccount(context.Context, *Account, *dto.AccountInputDTO, uint) (*Account, error)
 		DeleteAccount(context.Context, *Account, uint) error
@@ -105,11 +105,18 @@ func (s *Account) Bind(p *dto.AccountInputDTO) error {
 }
 
 func (s Account) ToMap() *map[string]interface{} {
-	return &map[string]interface{}{
+	mapped := &map[string]interface{}{
 		"username": s.Username,
 		"password": s.Password,
 		"site_id":  s.SiteID,
 		"phone_id": s.PhoneID,
-		"mail_id":  s.MailID,
+		"mail_id":  nil,
 	}
+
+	// Without mail the column is null, zero would break its foreign key.
+	if s.MailID != 0 {
+		(*mapped)["mail_id"] = s.MailID
+	}
+
+	return mapped
 }
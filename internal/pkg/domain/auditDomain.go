@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+)
+
+const (
+	AuditTableName string = "audit_log"
+
+	AuditSecretResolve string = "secret.resolve"
+)
+
+type (
+	AuditLog struct {
+		Base
+		Action    string `json:"action" gorm:"column:action;type:varchar(50);not null;index;"`
+		Target    string `json:"target" gorm:"column:target;type:varchar(255);not null;"`
+		Success   bool   `json:"success" gorm:"column:success;type:bool;not null;"`
+		Detail    string `json:"detail" gorm:"column:detail;type:varchar(255);not null;"`
+		UserID    uint   `json:"user_id" gorm:"column:user_id;type:bigint;not null;index;"`
+		TokenID   *uint  `json:"token_id" gorm:"column:token_id;type:bigint;default:null;"`
+		IP        string `json:"ip" gorm:"column:ip;type:varchar(50);not null;"`
+		RequestID string `json:"request_id" gorm:"column:request_id;type:varchar(50);not null;"`
+	}
+
+	AuditRepository interface {
+		CreateAuditLog(context.Context, *AuditLog) error
+	}
+)
+
+func (AuditLog) TableName() string {
+	return AuditTableName
+}
@@ -4,10 +4,17 @@ import (
 	"context"
 	"errors"
 
+	"github.com/raulaguila/go-pass/pkg/keyring"
 	"github.com/raulaguila/go-pass/pkg/validator"
 )
 
-var ErrInvalidIpAssociation error = errors.New("invalid ip source")
+var (
+	ErrInvalidIpAssociation error = errors.New("invalid ip source")
+	ErrDirectoryDisabled    error = errors.New("directory authentication disabled")
+	ErrDisabledUser         error = errors.New("disabled user")
+	ErrDirectoryEmpty       error = errors.New("directory returned no users")
+	ErrExternalUserConflict error = errors.New("email registered by another user source")
+)
 
 type (
 	TokensResponse struct {
@@ -20,18 +27,33 @@ type (
 		TokensResponse
 	}
 
+	// ExternalIdentity is a user authenticated by an external identity provider.
+	ExternalIdentity struct {
+		Email string
+		Name  string
+		// Profile name of the users provisioned on the first login, empty disables the provisioning.
+		Profile string
+		Source  string
+	}
+
 	AuthRepository interface {
 		Login(context.Context, *User, string) (*AuthResponse, error)
-		Me(context.Context, string, string, string) (*User, error)
+		Me(context.Context, string, *keyring.Keyring, string) (*User, error)
 		Refresh(context.Context, *User, string) (*TokensResponse, error)
 		GetUserByMail(context.Context, string) (*User, error)
+		GetExternalUser(context.Context, *ExternalIdentity) (*User, error)
+		DirectoryLogin(context.Context, string, string) (*User, error)
+		SyncDirectory(context.Context) error
 	}
 
 	AuthService interface {
 		Login(context.Context, *User, string) (*AuthResponse, error)
-		Me(context.Context, string, string, string) (*User, error)
+		Me(context.Context, string, *keyring.Keyring, string) (*User, error)
 		Refresh(context.Context, *User, string) (*TokensResponse, error)
 		GetUserByMail(context.Context, string) (*User, error)
+		GetExternalUser(context.Context, *ExternalIdentity) (*User, error)
+		DirectoryLogin(context.Context, string, string) (*User, error)
+		SyncDirectory(context.Context) error
 	}
 )
 
@@ -26,6 +26,7 @@ type (
 
 	ProfileRepository interface {
 		GetProfileByID(context.Context, uint) (*Profile, error)
+		GetProfileByName(context.Context, string) (*Profile, error)
 		GetProfilesOutputDTO(context.Context, *filter.Filter) (*dto.ItemsOutputDTO, error)
 		CreateProfile(context.Context, *dto.ProfileInputDTO) (*Profile, error)
 		UpdateProfile(context.Context, *Profile, *dto.ProfileInputDTO) error
@@ -34,6 +35,7 @@ type (
 
 	ProfileService interface {
 		GetProfileByID(context.Context, uint) (*Profile, error)
+		GetProfileByName(context.Context, string) (*Profile, error)
 		GetProfilesOutputDTO(context.Context, *filter.Filter) (*dto.ItemsOutputDTO, error)
 		CreateProfile(context.Context, *dto.ProfileInputDTO) (*Profile, error)
 		UpdateProfile(context.Context, *Profile, *dto.ProfileInputDTO) error
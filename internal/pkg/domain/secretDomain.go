@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/secretref"
+)
+
+var (
+	ErrSecretForbidden error = errors.New("secret outside of the token sites")
+	ErrSecretAmbiguous error = errors.New("reference matches more than one account")
+)
+
+type (
+	// SecretRequester identifies who resolves the secrets, for authorization and audit.
+	SecretRequester struct {
+		User      *User
+		Token     *PersonalToken
+		IP        string
+		RequestID string
+	}
+
+	// SecretError identifies the reference that could not be resolved.
+	SecretError struct {
+		Ref string
+		Err error
+	}
+
+	SecretRepository interface {
+		ResolveSecrets(context.Context, *dto.SecretResolveInputDTO, *SecretRequester) ([]secretref.Secret, error)
+	}
+
+	SecretService interface {
+		ResolveSecrets(context.Context, *dto.SecretResolveInputDTO, *SecretRequester) ([]secretref.Secret, error)
+	}
+)
+
+func (s *SecretError) Error() string {
+	return s.Ref + ": " + s.Err.Error()
+}
+
+func (s *SecretError) Unwrap() error {
+	return s.Err
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/validator"
+)
+
+const (
+	PersonalTokenTableName string = "personal_token"
+	PersonalTokenPrefix    string = "gpp_"
+
+	ScopeAccountRead   string = "account:read"
+	ScopeAccountWrite  string = "account:write"
+	ScopeAccountReveal string = "account:reveal"
+	ScopeSiteRead      string = "site:read"
+	ScopeSiteWrite     string = "site:write"
+	ScopePhoneRead     string = "phone:read"
+	ScopePhoneWrite    string = "phone:write"
+	ScopeOperatorRead  string = "operator:read"
+	ScopeOperatorWrite string = "operator:write"
+	ScopeProfileRead   string = "profile:read"
+	ScopeProfileWrite  string = "profile:write"
+	ScopeUserRead      string = "user:read"
+	ScopeUserWrite     string = "user:write"
+)
+
+var ErrPersonalTokenExpired error = errors.New("expired personal token")
+
+type (
+	PersonalToken struct {
+		Base
+		Name       string     `json:"name" gorm:"column:name;type:varchar(100);not null;" validate:"required,min=2"`
+		Prefix     string     `json:"prefix" gorm:"column:prefix;type:varchar(20);not null;"`
+		Hash       string     `json:"-" gorm:"column:hash;type:varchar(64);not null;unique;index;"`
+		Scopes     []string   `json:"scopes" gorm:"column:scopes;type:text;not null;serializer:json;" validate:"required,min=1,dive,oneof=account:read account:write account:reveal site:read site:write phone:read phone:write operator:read operator:write profile:read profile:write user:read user:write"`
+		SiteIDs    []uint     `json:"sites" gorm:"column:sites;type:text;not null;serializer:json;" validate:"dive,min=1"`
+		AllowedIPs []string   `json:"allowed_ips" gorm:"column:allowed_ips;type:text;not null;serializer:json;" validate:"dive,cidr|ip"`
+		ExpiresAt  *time.Time `json:"expires_at" gorm:"column:expires_at;default:null;" validate:"omitempty,gt"`
+		LastUsedAt *time.Time `json:"last_used_at" gorm:"column:last_used_at;default:null;"`
+		UserID     uint       `json:"-" gorm:"column:user_id;type:bigint;not null;index;"`
+		User       *User      `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	}
+
+	PersonalTokenResponse struct {
+		*PersonalToken
+		Token string `json:"token"`
+	}
+
+	PersonalTokenRepository interface {
+		GetPersonalTokenByID(context.Context, uint, uint) (*PersonalToken, error)
+		GetPersonalTokensOutputDTO(context.Context, uint) (*dto.ItemsOutputDTO, error)
+		CreatePersonalToken(context.Context, *dto.PersonalTokenInputDTO, uint) (*PersonalTokenResponse, error)
+		DeletePersonalToken(context.Context, *PersonalToken) error
+		Authenticate(context.Context, string, string) (*PersonalToken, error)
+	}
+
+	PersonalTokenService interface {
+		GetPersonalTokenByID(context.Context, uint, uint) (*PersonalToken, error)
+		GetPersonalTokensOutputDTO(context.Context, uint) (*dto.ItemsOutputDTO, error)
+		CreatePersonalToken(context.Context, *dto.PersonalTokenInputDTO, uint) (*PersonalTokenResponse, error)
+		DeletePersonalToken(context.Context, *PersonalToken) error
+		Authenticate(context.Context, string, string) (*PersonalToken, error)
+	}
+)
+
+func (PersonalToken) TableName() string {
+	return PersonalTokenTableName
+}
+
+func HashPersonalToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateSecret creates a new random token, only its hash is stored.
+func (s *PersonalToken) GenerateSecret() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	token := PersonalTokenPrefix + base64.RawURLEncoding.EncodeToString(random)
+	s.Prefix = token[:len(PersonalTokenPrefix)+8]
+	s.Hash = HashPersonalToken(token)
+	return token, nil
+}
+
+func (s *PersonalToken) Bind(p *dto.PersonalTokenInputDTO) error {
+	if p.Name != nil {
+		s.Name = *p.Name
+	}
+
+	s.Scopes = slices.Clone(p.Scopes)
+	slices.Sort(s.Scopes)
+	s.Scopes = slices.Compact(s.Scopes)
+	s.SiteIDs = p.Sites
+	s.AllowedIPs = p.AllowedIPs
+	s.ExpiresAt = p.ExpiresAt
+
+	if s.SiteIDs == nil {
+		s.SiteIDs = []uint{}
+	}
+	if s.AllowedIPs == nil {
+		s.AllowedIPs = []string{}
+	}
+
+	return validator.StructValidator.Validate(s)
+}
+
+func (s *PersonalToken) Expired() bool {
+	return s.ExpiresAt != nil && time.Now().After(*s.ExpiresAt)
+}
+
+func (s *PersonalToken) HasScope(scope string) bool {
+	return slices.Contains(s.Scopes, scope)
+}
+
+// AllowsIP reports if the ip is in the allowlist, an empty allowlist accepts any ip.
+func (s *PersonalToken) AllowsIP(ip string) bool {
+	if len(s.AllowedIPs) == 0 {
+		return true
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, allowed := range s.AllowedIPs {
+		if strings.Contains(allowed, "/") {
+			if _, network, err := net.ParseCIDR(allowed); err == nil && network.Contains(parsed) {
+				return true
+			}
+			continue
+		}
+
+		if net.ParseIP(allowed).Equal(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsSite reports if the site is visible to the token, tokens without sites see every site.
+func (s *PersonalToken) AllowsSite(siteID uint) bool {
+	return len(s.SiteIDs) == 0 || slices.Contains(s.SiteIDs, siteID)
+}
@@ -2,19 +2,30 @@ package domain
 
 import (
 	"context"
-	"encoding/base64"
-	"fmt"
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/raulaguila/go-pass/internal/pkg/dto"
 	"github.com/raulaguila/go-pass/pkg/filter"
-	"github.com/raulaguila/go-pass/pkg/helpers"
+	"github.com/raulaguila/go-pass/pkg/hasher"
+	"github.com/raulaguila/go-pass/pkg/keyring"
 	"github.com/raulaguila/go-pass/pkg/validator"
-	"golang.org/x/crypto/bcrypt"
 )
 
-const UserTableName string = "users"
+const (
+	UserTableName            string = "users"
+	PasswordHistoryTableName string = "password_history"
+)
+
+var ErrPasswordReused error = errors.New("password used recently")
+
+// Origin of the user, the directory sync only manages the users created by the directory.
+const (
+	UserSourceLocal string = "local"
+	UserSourceOIDC  string = "oidc"
+	UserSourceLDAP  string = "ldap"
+)
 
 type (
 	User struct {
@@ -26,8 +37,23 @@ type (
 		ProfileID uint     `json:"-" gorm:"column:profile_id;type:bigint;not null;index;" validate:"required,min=1"`
 		Token     *string  `json:"-" gorm:"column:token;type:varchar(255);unique;index"`
 		Password  *string  `json:"-" gorm:"column:password;type:varchar(255);"`
+		Source    string   `json:"source" gorm:"column:source;type:varchar(10);not null;default:local;"`
+		Language  string   `json:"language" gorm:"column:language;type:varchar(35);not null;default:'';" validate:"omitempty,bcp47_language_tag"`
 		Profile   *Profile `json:"profile,omitempty"`
 		Expire    bool     `json:"-" gorm:"-"`
+		Rehashed  bool     `json:"-" gorm:"-"`
+
+		// ChangePassword blocks every route but the password change until the user picks a new password.
+		ChangePassword bool `json:"change_password" gorm:"column:change_password;type:bool;not null;default:false;"`
+	}
+
+	// PasswordHistory keeps the previous password hashes of the users to deny their reuse.
+	PasswordHistory struct {
+		Id        uint      `json:"-" gorm:"primarykey"`
+		CreatedAt time.Time `json:"-" gorm:"autoCreateTime"`
+		Hash      string    `json:"-" gorm:"column:hash;type:varchar(255);not null;"`
+		UserID    uint      `json:"-" gorm:"column:user_id;type:bigint;not null;index;"`
+		User      *User     `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	}
 
 	UserRepository interface {
@@ -39,7 +65,10 @@ type (
 		UpdateUser(context.Context, *User, *dto.UserInputDTO) error
 		DeleteUser(context.Context, *User) error
 		ResetUser(context.Context, *User) error
+		CheckPassword(context.Context, *User, string) error
 		PasswordUser(context.Context, *User, *dto.PasswordInputDTO) error
+		RehashUser(context.Context, *User) error
+		ActivateUser(context.Context, *User) error
 	}
 
 	UserService interface {
@@ -51,7 +80,9 @@ type (
 		UpdateUser(context.Context, *User, *dto.UserInputDTO) error
 		DeleteUser(context.Context, *User) error
 		ResetUser(context.Context, *User) error
+		CheckPassword(context.Context, *User, string) error
 		PasswordUser(context.Context, *User, *dto.PasswordInputDTO) error
+		RehashUser(context.Context, *User) error
 	}
 )
 
@@ -59,19 +90,27 @@ func (User) TableName() string {
 	return UserTableName
 }
 
+func (PasswordHistory) TableName() string {
+	return PasswordHistoryTableName
+}
+
 func (u *User) ToMap() *map[string]interface{} {
 	mapped := &map[string]interface{}{
-		"name":       u.Name,
-		"mail":       u.Email,
-		"status":     u.Status,
-		"profile_id": u.ProfileID,
-		"new":        u.New,
-		"token":      nil,
-		"password":   nil,
+		"name":            u.Name,
+		"mail":            u.Email,
+		"status":          u.Status,
+		"profile_id":      u.ProfileID,
+		"new":             u.New,
+		"change_password": u.ChangePassword,
+		"language":        u.Language,
+		"token":           nil,
+		"password":        nil,
 	}
 
-	if u.Password != nil {
+	if u.Token != nil {
 		(*mapped)["token"] = *u.Token
+	}
+	if u.Password != nil {
 		(*mapped)["password"] = *u.Password
 	}
 
@@ -91,25 +130,37 @@ func (s *User) Bind(u *dto.UserInputDTO) error {
 	if u.ProfileID != nil {
 		s.ProfileID = *u.ProfileID
 	}
+	if u.Language != nil {
+		s.Language = *u.Language
+	}
 
 	return validator.StructValidator.Validate(s)
 }
 
+// ValidatePassword compares the password with the user hash. When the hash uses an old algorithm or parameters,
+// it is replaced by a new one of the default hasher and Rehashed is set, so the caller can store it.
 func (u *User) ValidatePassword(password string) bool {
-	return bcrypt.CompareHashAndPassword([]byte(*u.Password), []byte(password)) == nil
-}
+	if u.Password == nil {
+		return false
+	}
 
-func (u *User) GenerateToken(expire, originalKey, ip string) (string, error) {
-	decodedKey, err := base64.StdEncoding.DecodeString(originalKey)
-	if err != nil {
-		return "", fmt.Errorf("could not decode key: %v", err.Error())
+	ok, rehash, err := hasher.Verify(*u.Password, password)
+	if err != nil || !ok {
+		return false
 	}
 
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(decodedKey)
-	if err != nil {
-		return "", fmt.Errorf("could not parse key: %v", err.Error())
+	if rehash {
+		if hash, err := hasher.Hash(password); err == nil {
+			u.Password = &hash
+			u.Rehashed = true
+		}
 	}
 
+	return true
+}
+
+// GenerateToken signs a token of the user, a zero life creates a token without expiration.
+func (u *User) GenerateToken(life time.Duration, keys *keyring.Keyring, ip string) (string, error) {
 	now := time.Now()
 	claims := jwt.MapClaims{
 		"token": u.Token,
@@ -117,12 +168,10 @@ func (u *User) GenerateToken(expire, originalKey, ip string) (string, error) {
 		"iat":   now.Unix(),
 	}
 
-	life, err := helpers.DurationFromString(expire, time.Minute)
-	if err == nil {
+	if life > 0 {
 		claims["exp"] = now.Add(life).Unix()
 	}
-	claims["expire"] = err == nil
+	claims["expire"] = life > 0
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	return token.SignedString(key)
+	return keys.Sign(claims)
 }
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+)
+
+const (
+	UserTokenTableName string = "user_token"
+
+	UserTokenInvite string = "invite"
+	UserTokenReset  string = "reset"
+)
+
+var (
+	ErrInvalidUserToken error = errors.New("invalid, used or expired user token")
+	ErrUserHasPassword  error = errors.New("user already has a password")
+)
+
+type (
+	// UserToken tracks the signed single-use links emailed to the users, like the invitations.
+	UserToken struct {
+		Base
+		Purpose   string     `json:"-" gorm:"column:purpose;type:varchar(10);not null;index;"`
+		Hash      string     `json:"-" gorm:"column:hash;type:varchar(64);not null;unique;index;"`
+		ExpiresAt time.Time  `json:"-" gorm:"column:expires_at;not null;"`
+		UsedAt    *time.Time `json:"-" gorm:"column:used_at;default:null;"`
+		UserID    uint       `json:"-" gorm:"column:user_id;type:bigint;not null;index;"`
+		User      *User      `json:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	}
+
+	UserTokenRepository interface {
+		InviteUser(context.Context, *User, *i18n.Translation) error
+		AcceptInvite(context.Context, string, string, *dto.PasswordInputDTO) (*User, error)
+		ForgotPassword(context.Context, string, *i18n.Translation) error
+		ResetPassword(context.Context, string, *dto.PasswordInputDTO) (*User, error)
+	}
+
+	UserTokenService interface {
+		InviteUser(context.Context, *User, *i18n.Translation) error
+		AcceptInvite(context.Context, string, string, *dto.PasswordInputDTO) (*User, error)
+		ForgotPassword(context.Context, string, *i18n.Translation) error
+		ResetPassword(context.Context, string, *dto.PasswordInputDTO) (*User, error)
+	}
+)
+
+func (UserToken) TableName() string {
+	return UserTokenTableName
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type (
 	PermissionsInputDTO struct {
 		UserModule    *bool `json:"user_module" example:"true"`
@@ -16,11 +18,18 @@ type (
 		Email     *string `json:"email" example:"john.cena@email.com"`
 		Status    *bool   `json:"status" example:"true"`
 		ProfileID *uint   `json:"profile_id" example:"1"`
+		Language  *string `json:"language" example:"pt-BR"`
 	}
 
 	PasswordInputDTO struct {
-		Password        *string `json:"password" example:"secret"`
-		PasswordConfirm *string `json:"password_confirm" example:"secret"`
+		Token           *string `json:"token" example:"eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9..."`
+		Current         *string `json:"current_password" example:"old-secret-01"`
+		Password        *string `json:"password" example:"new-secret-02"`
+		PasswordConfirm *string `json:"password_confirm" example:"new-secret-02"`
+	}
+
+	ForgotInputDTO struct {
+		Email string `json:"email" example:"john.cena@email.com"`
 	}
 
 	LoginInputDTO struct {
@@ -51,6 +60,23 @@ type (
 		PhoneID  *uint   `json:"phone_id" example:"1"`
 		MailID   *uint   `json:"mail_id" example:"1"`
 	}
+
+	PersonalTokenInputDTO struct {
+		Name       *string    `json:"name" example:"CI deploy"`
+		Scopes     []string   `json:"scopes" example:"account:read,account:reveal"`
+		Sites      []uint     `json:"sites" example:"1"`
+		AllowedIPs []string   `json:"allowed_ips" example:"10.0.0.0/8"`
+		ExpiresAt  *time.Time `json:"expires_at" example:"2030-01-01T00:00:00Z"`
+	}
+
+	SecretRefInputDTO struct {
+		Name string `json:"name" example:"DEPLOY_PASSWORD"`
+		Ref  string `json:"ref" example:"gopass://site/github/account/deploy-bot#password"`
+	}
+
+	SecretResolveInputDTO struct {
+		Secrets []SecretRefInputDTO `json:"secrets" validate:"required,min=1"`
+	}
 )
 
 func (p PasswordInputDTO) IsValid() bool {
@@ -58,9 +84,5 @@ func (p PasswordInputDTO) IsValid() bool {
 		return false
 	}
 
-	if len(*p.Password) < 5 || len(*p.PasswordConfirm) < 5 {
-		return false
-	}
-
 	return *p.Password == *p.PasswordConfirm
 }
@@ -2,28 +2,117 @@ package i18n
 
 import (
 	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/raulaguila/go-pass/pkg/passpolicy"
+	"github.com/raulaguila/go-pass/pkg/validator"
 )
 
 var I18nTranslations map[string]*Translation = map[string]*Translation{}
 
-func NewTranslation(localizer *i18n.Localizer) *Translation {
-	translation := &Translation{}
+// Messages of the password policy violations.
+var policyMessages map[string]string = map[string]string{
+	passpolicy.CodeTooShort: "ErrPasswordTooShort",
+	passpolicy.CodeTooLong:  "ErrPasswordTooLong",
+	passpolicy.CodeClasses:  "ErrPasswordClasses",
+	passpolicy.CodeCommon:   "ErrPasswordCommon",
+}
+
+// Messages of the validation rules, keyed like the translations of the validator.
+var validationMessages map[string]string = map[string]string{
+	"required":            "ValidationRequired",
+	"min":                 "ValidationMin",
+	"min_string":          "ValidationMinString",
+	"min_items":           "ValidationMinItems",
+	"max":                 "ValidationMax",
+	"max_string":          "ValidationMaxString",
+	"max_items":           "ValidationMaxItems",
+	"email":               "ValidationEmail",
+	"jwt":                 "ValidationJwt",
+	"oneof":               "ValidationOneof",
+	"cidr":                "ValidationCidr",
+	"ip":                  "ValidationIp",
+	"cidr|ip":             "ValidationCidrOrIp",
+	"bcp47_language_tag":  "ValidationBcp47LanguageTag",
+	validator.DefaultRule: "ValidationDefault",
+}
+
+// Error is a localized message with a code that does not depend on the language, the snake case of its message id
+// without the Err prefix, like user_not_found for ErrUserNotFound.
+type Error struct {
+	code    string
+	message string
+}
+
+func newError(localizer *i18n.Localizer, id string) error {
+	message := localize(localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: id}})
+	return &Error{code: errorCode(id), message: message}
+}
+
+// localize is like MustLocalize, but the messages missing from the bundle of the language fall back to the english
+// ones, so the bundles loaded at startup can be partial.
+func localize(localizer *i18n.Localizer, config *i18n.LocalizeConfig) string {
+	message, err := localizer.Localize(config)
+	notFound := &i18n.MessageNotFoundErr{}
+	if err != nil && (!errors.As(err, &notFound) || message == "") {
+		panic(err)
+	}
+
+	return message
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Code() string {
+	return e.code
+}
+
+func errorCode(id string) string {
+	id = strings.TrimPrefix(strings.TrimPrefix(id, "Error"), "Err")
+
+	code := strings.Builder{}
+	for i, r := range id {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				code.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		code.WriteRune(r)
+	}
+
+	return code.String()
+}
+
+func NewTranslation(lang string, localizer *i18n.Localizer) *Translation {
+	translation := &Translation{lang: lang, localizer: localizer}
 	translation.loadTranslations(localizer)
 	return translation
 }
 
 type Translation struct {
+	lang      string
+	localizer *i18n.Localizer
+
 	ErrGeneric              error
 	ErrInvalidId            error
 	ErrInvalidDatas         error
+	ErrInvalidFields        error
 	ErrManyRequest          error
 	ErrorNonexistentRoute   error
 	ErrUndefinedColumn      error
 	ErrExpiredToken         error
 	ErrDisabledUser         error
-	ErrIncorrectPassword    error
+	ErrInvalidCredentials   error
+	ErrLoginLocked          error
+	ErrInvalidLoginSession  error
+	ErrExternalUserConflict error
 	ErrPassUnmatch          error
 	ErrUserHasPass          error
 	ErrInvalidIpAssociation error
@@ -51,43 +140,112 @@ type Translation struct {
 	ErrAccountUsed       error
 	ErrAccountNotFound   error
 	ErrAccountRegistered error
+
+	ErrTokenScope    error
+	ErrTokenNotFound error
+
+	ErrInvalidSecretRef   error
+	ErrAmbiguousSecretRef error
+	ErrInvalidFormat      error
+
+	ErrInvalidUserToken error
+
+	ErrPasswordReused error
+	ErrPasswordChange error
+}
+
+// Message localizes a message with template data, like the emails.
+func (s *Translation) Message(id string, data interface{}) string {
+	return localize(s.localizer, &i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: id}, TemplateData: data})
+}
+
+// PasswordPolicy localizes every rule broken by a password.
+func (s *Translation) PasswordPolicy(err *passpolicy.Error) error {
+	messages := make([]string, len(err.Violations))
+	for i, violation := range err.Violations {
+		messages[i] = s.Message(policyMessages[violation.Code], map[string]interface{}{"Value": violation.Value})
+	}
+
+	return &Error{code: "password_policy", message: strings.Join(messages, " ")}
+}
+
+// ValidationMessages localizes the messages of the validation rules, to be registered in the validator.
+func (s *Translation) ValidationMessages() map[string]string {
+	messages := make(map[string]string, len(validationMessages))
+	for rule, id := range validationMessages {
+		messages[rule] = s.Message(id, nil)
+	}
+
+	return messages
+}
+
+// Violation localizes the message of a validation rule broken by a field.
+func (s *Translation) Violation(err *validator.ValidatorError) string {
+	return err.Translate(s.lang)
+}
+
+// Title localizes the title of the error responses with the status, the status text of net/http is the fallback.
+func (s *Translation) Title(status int) string {
+	title, err := s.localizer.Localize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "Status" + strconv.Itoa(status)}})
+	notFound := &i18n.MessageNotFoundErr{}
+	if err != nil && (!errors.As(err, &notFound) || title == "") {
+		return http.StatusText(status)
+	}
+
+	return title
 }
 
 func (s *Translation) loadTranslations(localizer *i18n.Localizer) {
-	s.ErrGeneric = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrGeneric"}, PluralCount: 1}))
-	s.ErrInvalidId = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrInvalidId"}, PluralCount: 1}))
-	s.ErrInvalidDatas = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrInvalidDatas"}, PluralCount: 1}))
-	s.ErrManyRequest = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrManyRequest"}, PluralCount: 1}))
-	s.ErrorNonexistentRoute = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrorNonexistentRoute"}, PluralCount: 1}))
-	s.ErrUndefinedColumn = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrUndefinedColumn"}, PluralCount: 1}))
-	s.ErrExpiredToken = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrExpiredToken"}, PluralCount: 1}))
-	s.ErrDisabledUser = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrDisabledUser"}, PluralCount: 1}))
-	s.ErrIncorrectPassword = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrIncorrectPassword"}, PluralCount: 1}))
-	s.ErrPassUnmatch = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrPassUnmatch"}, PluralCount: 1}))
-	s.ErrUserHasPass = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrUserHasPass"}, PluralCount: 1}))
-	s.ErrInvalidIpAssociation = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrInvalidIpAssociation"}, PluralCount: 1}))
-
-	s.ErrProfileUsed = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrProfileUsed"}, PluralCount: 1}))
-	s.ErrProfileNotFound = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrProfileNotFound"}, PluralCount: 1}))
-	s.ErrProfileRegistered = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrProfileRegistered"}, PluralCount: 1}))
-
-	s.ErrUserUsed = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrUserUsed"}, PluralCount: 1}))
-	s.ErrUserNotFound = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrUserNotFound"}, PluralCount: 1}))
-	s.ErrUserRegistered = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrUserRegistered"}, PluralCount: 1}))
-
-	s.ErrSiteUsed = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrSiteUsed"}, PluralCount: 1}))
-	s.ErrSiteNotFound = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrSiteNotFound"}, PluralCount: 1}))
-	s.ErrSiteRegistered = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrSiteRegistered"}, PluralCount: 1}))
-
-	s.ErrPhoneUsed = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrPhoneUsed"}, PluralCount: 1}))
-	s.ErrPhoneNotFound = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrPhoneNotFound"}, PluralCount: 1}))
-	s.ErrPhoneRegistered = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrPhoneRegistered"}, PluralCount: 1}))
-
-	s.ErrOperatorUsed = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrOperatorUsed"}, PluralCount: 1}))
-	s.ErrOperatorNotFound = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrOperatorNotFound"}, PluralCount: 1}))
-	s.ErrOperatorRegistered = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrOperatorRegistered"}, PluralCount: 1}))
-
-	s.ErrAccountUsed = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrAccountUsed"}, PluralCount: 1}))
-	s.ErrAccountNotFound = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrAccountNotFound"}, PluralCount: 1}))
-	s.ErrAccountRegistered = errors.New(localizer.MustLocalize(&i18n.LocalizeConfig{DefaultMessage: &i18n.Message{ID: "ErrAccountRegistered"}, PluralCount: 1}))
+	s.ErrGeneric = newError(localizer, "ErrGeneric")
+	s.ErrInvalidId = newError(localizer, "ErrInvalidId")
+	s.ErrInvalidDatas = newError(localizer, "ErrInvalidDatas")
+	s.ErrInvalidFields = newError(localizer, "ErrInvalidFields")
+	s.ErrManyRequest = newError(localizer, "ErrManyRequest")
+	s.ErrorNonexistentRoute = newError(localizer, "ErrorNonexistentRoute")
+	s.ErrUndefinedColumn = newError(localizer, "ErrUndefinedColumn")
+	s.ErrExpiredToken = newError(localizer, "ErrExpiredToken")
+	s.ErrDisabledUser = newError(localizer, "ErrDisabledUser")
+	s.ErrInvalidCredentials = newError(localizer, "ErrInvalidCredentials")
+	s.ErrLoginLocked = newError(localizer, "ErrLoginLocked")
+	s.ErrInvalidLoginSession = newError(localizer, "ErrInvalidLoginSession")
+	s.ErrExternalUserConflict = newError(localizer, "ErrExternalUserConflict")
+	s.ErrPassUnmatch = newError(localizer, "ErrPassUnmatch")
+	s.ErrUserHasPass = newError(localizer, "ErrUserHasPass")
+	s.ErrInvalidIpAssociation = newError(localizer, "ErrInvalidIpAssociation")
+
+	s.ErrProfileUsed = newError(localizer, "ErrProfileUsed")
+	s.ErrProfileNotFound = newError(localizer, "ErrProfileNotFound")
+	s.ErrProfileRegistered = newError(localizer, "ErrProfileRegistered")
+
+	s.ErrUserUsed = newError(localizer, "ErrUserUsed")
+	s.ErrUserNotFound = newError(localizer, "ErrUserNotFound")
+	s.ErrUserRegistered = newError(localizer, "ErrUserRegistered")
+
+	s.ErrSiteUsed = newError(localizer, "ErrSiteUsed")
+	s.ErrSiteNotFound = newError(localizer, "ErrSiteNotFound")
+	s.ErrSiteRegistered = newError(localizer, "ErrSiteRegistered")
+
+	s.ErrPhoneUsed = newError(localizer, "ErrPhoneUsed")
+	s.ErrPhoneNotFound = newError(localizer, "ErrPhoneNotFound")
+	s.ErrPhoneRegistered = newError(localizer, "ErrPhoneRegistered")
+
+	s.ErrOperatorUsed = newError(localizer, "ErrOperatorUsed")
+	s.ErrOperatorNotFound = newError(localizer, "ErrOperatorNotFound")
+	s.ErrOperatorRegistered = newError(localizer, "ErrOperatorRegistered")
+
+	s.ErrAccountUsed = newError(localizer, "ErrAccountUsed")
+	s.ErrAccountNotFound = newError(localizer, "ErrAccountNotFound")
+	s.ErrAccountRegistered = newError(localizer, "ErrAccountRegistered")
+
+	s.ErrTokenScope = newError(localizer, "ErrTokenScope")
+	s.ErrTokenNotFound = newError(localizer, "ErrTokenNotFound")
+
+	s.ErrInvalidSecretRef = newError(localizer, "ErrInvalidSecretRef")
+	s.ErrAmbiguousSecretRef = newError(localizer, "ErrAmbiguousSecretRef")
+	s.ErrInvalidFormat = newError(localizer, "ErrInvalidFormat")
+
+	s.ErrInvalidUserToken = newError(localizer, "ErrInvalidUserToken")
+
+	s.ErrPasswordReused = newError(localizer, "ErrPasswordReused")
+	s.ErrPasswordChange = newError(localizer, "ErrPasswordChange")
 }
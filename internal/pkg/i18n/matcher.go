@@ -0,0 +1,39 @@
+package i18n
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Matcher negotiates the language of the requests among the supported ones, by BCP 47 matching.
+type Matcher struct {
+	languages []string
+	matcher   language.Matcher
+}
+
+// NewMatcher creates the matcher of the supported languages, their BCP 47 tags like en or pt-BR.
+func NewMatcher(languages []string) *Matcher {
+	tags := make([]language.Tag, len(languages))
+	for i, lang := range languages {
+		tags[i] = language.Make(lang)
+	}
+
+	return &Matcher{languages: languages, matcher: language.NewMatcher(tags)}
+}
+
+// Match returns the supported language closest to the preferences, a tag like pt_BR or an Accept-Language list like
+// pt-BR,pt;q=0.9,en;q=0.8. It is false when no preference is close to a supported language.
+func (m *Matcher) Match(preferences string) (string, bool) {
+	tags, _, err := language.ParseAcceptLanguage(strings.ReplaceAll(preferences, "_", "-"))
+	if err != nil || len(tags) == 0 {
+		return "", false
+	}
+
+	_, index, confidence := m.matcher.Match(tags...)
+	if confidence == language.No {
+		return "", false
+	}
+
+	return m.languages[index], true
+}
@@ -0,0 +1,155 @@
+// Package metrics holds the Prometheus collectors of the API.
+//
+// The methods of a nil *Metrics do nothing, so the handlers record the events whether the metrics are enabled or not.
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace string = "gopass"
+
+// Results of the login and refresh counters.
+const (
+	ResultSuccess string = "success"
+	ResultFailure string = "failure"
+	ResultLocked  string = "locked"
+)
+
+// Sources of the secret reveals and limiters of the rate-limit rejections.
+const (
+	SourceAccount string = "account"
+	SourceResolve string = "resolve"
+
+	LimiterAPI      string = "api"
+	LimiterRecovery string = "recovery"
+)
+
+type Metrics struct {
+	registry    *prometheus.Registry
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	logins      *prometheus.CounterVec
+	reveals     *prometheus.CounterVec
+	rateLimited *prometheus.CounterVec
+	refreshes   *prometheus.CounterVec
+}
+
+// New registers the API collectors with the Go runtime and process ones, and the pool stats of the database when given.
+func New(db *sql.DB) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Password logins by result: success, failure or locked.",
+		}, []string{"result"}),
+		reveals: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "secret_reveals_total",
+			Help:      "Account passwords revealed by source: account or resolve.",
+		}, []string{"source"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limited_total",
+			Help:      "Requests rejected by a rate limiter: api or recovery.",
+		}, []string{"limiter"}),
+		refreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "token_refreshes_total",
+			Help:      "Token refreshes by result: success or failure.",
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.duration, m.logins, m.reveals, m.rateLimited, m.refreshes,
+	)
+	if db != nil {
+		m.registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+	}
+
+	return m
+}
+
+// Request records a served request, the route is the registered path, like /account/:id, to bound the series.
+func (m *Metrics) Request(method, route string, status int, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+
+	code := strconv.Itoa(status)
+	m.requests.WithLabelValues(method, route, code).Inc()
+	m.duration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) Login(result string) {
+	if m == nil {
+		return
+	}
+
+	m.logins.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) Reveal(source string, count int) {
+	if m == nil {
+		return
+	}
+
+	m.reveals.WithLabelValues(source).Add(float64(count))
+}
+
+func (m *Metrics) RateLimited(limiter string) {
+	if m == nil {
+		return
+	}
+
+	m.rateLimited.WithLabelValues(limiter).Inc()
+}
+
+func (m *Metrics) Refresh(result string) {
+	if m == nil {
+		return
+	}
+
+	m.refreshes.WithLabelValues(result).Inc()
+}
+
+// Handler exposes the metrics in the Prometheus format, requiring the bearer token when not empty.
+func (m *Metrics) Handler(token string) http.Handler {
+	handler := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return handler
+	}
+
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
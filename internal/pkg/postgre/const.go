@@ -8,7 +8,9 @@ const (
 	Site        string = "Site"
 	Mail        string = "Mail"
 	Phone       string = "Phone"
+	User        string = "User"
 
 	PhoneOperator     string = Phone + "." + Operator
 	ProfilePermission string = Profile + "." + Permissions
+	UserProfile       string = User + "." + ProfilePermission
 )
@@ -20,9 +20,12 @@ type accountRepository struct {
 	postgres *gorm.DB
 }
 
-func (s *accountRepository) applyFilter(ctx context.Context, filter *filter.Filter) *gorm.DB {
+func (s *accountRepository) applyFilter(ctx context.Context, filter *filter.AccountFilter) *gorm.DB {
 	postgres := s.postgres.WithContext(ctx)
-	postgres = filter.ApplySearchLike(postgres, "name")
+	if len(filter.SiteIDs) > 0 {
+		postgres = postgres.Where("site_id IN ?", filter.SiteIDs)
+	}
+	postgres = filter.ApplySearchLike(postgres, "username")
 	postgres = filter.ApplyOrder(postgres)
 
 	return postgres
@@ -38,7 +41,7 @@ func (s *accountRepository) listAccounts(postgres *gorm.DB) (*[]domain.Account,
 	return accounts, postgres.Preload(postgre.Site).Preload(postgre.Mail).Preload(postgre.PhoneOperator).Find(accounts).Error
 }
 
-func (s *accountRepository) GetAccountsOutputDTO(ctx context.Context, filter *filter.Filter, userID uint) (*dto.ItemsOutputDTO, error) {
+func (s *accountRepository) GetAccountsOutputDTO(ctx context.Context, filter *filter.AccountFilter, userID uint) (*dto.ItemsOutputDTO, error) {
 	postgres := s.applyFilter(ctx, filter)
 	count, err := s.countAccounts(postgres)
 	if err != nil {
@@ -68,7 +71,7 @@ func (s *accountRepository) addAccountEmailHistory(ctx context.Context, accountI
 }
 
 func (s *accountRepository) CreateAccount(ctx context.Context, datas *dto.AccountInputDTO, userID uint) (*domain.Account, error) {
-	account := &domain.Account{}
+	account := &domain.Account{UserID: userID}
 	if err := account.Bind(datas); err != nil {
 		return nil, err
 	}
@@ -94,7 +97,7 @@ func (s *accountRepository) UpdateAccount(ctx context.Context, account *domain.A
 		return nil, err
 	}
 
-	if account.MailID != mailID {
+	if account.MailID != mailID && account.MailID != 0 {
 		s.addAccountEmailHistory(ctx, account.Id, account.MailID)
 	}
 
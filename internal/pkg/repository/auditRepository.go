@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"gorm.io/gorm"
+)
+
+func NewAuditRepository(postgres *gorm.DB) domain.AuditRepository {
+	return &auditRepository{
+		postgres: postgres,
+	}
+}
+
+type auditRepository struct {
+	postgres *gorm.DB
+}
+
+func (s *auditRepository) CreateAuditLog(ctx context.Context, audit *domain.AuditLog) error {
+	return s.postgres.WithContext(ctx).Create(audit).Error
+}
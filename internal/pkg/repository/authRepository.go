@@ -2,37 +2,61 @@ package repository
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
-	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/filter"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/raulaguila/go-pass/pkg/ldapauth"
+	"gorm.io/gorm"
 )
 
-func NewAuthRepository(userRepository domain.UserRepository) domain.AuthRepository {
+// A nil directory disables the directory login and sync, the token lives apply only to the logins that expire.
+func NewAuthRepository(userRepository domain.UserRepository, profileRepository domain.ProfileRepository, accessKeys, refreshKeys *keyring.Keyring, accessLife, refreshLife time.Duration, directory *ldapauth.Directory, groupMap ldapauth.GroupMap) domain.AuthRepository {
 	return &authRepository{
-		userRepository: userRepository,
+		userRepository:    userRepository,
+		profileRepository: profileRepository,
+		accessKeys:        accessKeys,
+		refreshKeys:       refreshKeys,
+		accessLife:        accessLife,
+		refreshLife:       refreshLife,
+		directory:         directory,
+		groupMap:          groupMap,
 	}
 }
 
 type authRepository struct {
-	userRepository domain.UserRepository
+	userRepository    domain.UserRepository
+	profileRepository domain.ProfileRepository
+	accessKeys        *keyring.Keyring
+	refreshKeys       *keyring.Keyring
+	accessLife        time.Duration
+	refreshLife       time.Duration
+	directory         *ldapauth.Directory
+	groupMap          ldapauth.GroupMap
 }
 
 func (s *authRepository) Login(ctx context.Context, user *domain.User, ip string) (*domain.AuthResponse, error) {
-	accessTime, refreshTime := "-", "-"
+	if err := s.userRepository.RehashUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	var accessTime, refreshTime time.Duration
 	if user.Expire {
-		accessTime = os.Getenv("ACCESS_TOKEN_EXPIRE")
-		refreshTime = os.Getenv("RFRESH_TOKEN_EXPIRE")
+		accessTime, refreshTime = s.accessLife, s.refreshLife
 	}
 
-	accessToken, err := user.GenerateToken(accessTime, os.Getenv("ACCESS_TOKEN_PRIVAT"), ip)
+	accessToken, err := user.GenerateToken(accessTime, s.accessKeys, ip)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := user.GenerateToken(refreshTime, os.Getenv("RFRESH_TOKEN_PRIVAT"), ip)
+	refreshToken, err := user.GenerateToken(refreshTime, s.refreshKeys, ip)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +76,13 @@ func (s *authRepository) claims2user(ctx context.Context, parsedToken *jwt.Token
 		return nil, errors.New("invalid token")
 	}
 
-	usr, err := s.userRepository.GetUserByToken(ctx, claims["token"].(string))
+	// Other tokens signed by the same keys, like the invitations, have no user token.
+	token, ok := claims["token"].(string)
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+
+	usr, err := s.userRepository.GetUserByToken(ctx, token)
 	if err != nil {
 		return nil, err
 	}
@@ -64,60 +94,175 @@ func (s *authRepository) claims2user(ctx context.Context, parsedToken *jwt.Token
 	}
 
 	if !usr.Status {
-		return nil, errors.New("disabled user")
+		return nil, domain.ErrDisabledUser
 	}
 
 	return usr, nil
 }
 
-func (s *authRepository) Me(ctx context.Context, userToken, base64Key, ip string) (*domain.User, error) {
-	decodedKey, err := base64.StdEncoding.DecodeString(base64Key)
+func (s *authRepository) Me(ctx context.Context, userToken string, keys *keyring.Keyring, ip string) (*domain.User, error) {
+	parsedToken, err := keys.Parse(userToken, jwt.MapClaims{})
 	if err != nil {
 		return nil, err
 	}
 
-	parsedKey, err := jwt.ParseRSAPublicKeyFromPEM(decodedKey)
+	return s.claims2user(ctx, parsedToken, ip)
+}
+
+func (s *authRepository) Refresh(ctx context.Context, user *domain.User, ip string) (*domain.TokensResponse, error) {
+	var accessTime, refreshTime time.Duration
+	if user.Expire {
+		accessTime, refreshTime = s.accessLife, s.refreshLife
+	}
+
+	accessToken, err := user.GenerateToken(accessTime, s.accessKeys, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := user.GenerateToken(refreshTime, s.refreshKeys, ip)
 	if err != nil {
 		return nil, err
 	}
 
-	parsedToken, err := jwt.Parse(userToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+	return &domain.TokensResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *authRepository) GetUserByMail(ctx context.Context, userMail string) (*domain.User, error) {
+	return s.userRepository.GetUserByMail(ctx, userMail)
+}
+
+// GetExternalUser returns the user of the identity, provisioning it in the identity profile on the first login.
+func (s *authRepository) GetExternalUser(ctx context.Context, identity *domain.ExternalIdentity) (*domain.User, error) {
+	user, err := s.userRepository.GetUserByMail(ctx, identity.Email)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) || identity.Profile == "" {
 			return nil, err
 		}
 
-		return parsedKey, nil
-	})
+		profile, err := s.profileRepository.GetProfileByName(ctx, identity.Profile)
+		if err != nil {
+			return nil, err
+		}
+
+		name, status := identity.Name, true
+		if utf8.RuneCountInString(name) < 5 {
+			name = identity.Email
+		}
+
+		user, err = s.userRepository.CreateUser(ctx, &dto.UserInputDTO{Name: &name, Email: &identity.Email, Status: &status, ProfileID: &profile.Id})
+		if err != nil {
+			return nil, err
+		}
+		user.Source = identity.Source
+	}
+
+	// Only the users provisioned by the same source are linked, the local accounts keep their own login.
+	if user.Source != identity.Source {
+		return nil, domain.ErrExternalUserConflict
+	}
+
+	if user.Status && user.Token == nil {
+		if err := s.userRepository.ActivateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// syncDirectoryUser applies the directory profile to a directory user, disabling it when no group is mapped.
+func (s *authRepository) syncDirectoryUser(ctx context.Context, user *domain.User, profileName string) error {
+	if user.Source != domain.UserSourceLDAP {
+		return nil
+	}
+
+	if profileName == "" {
+		if !user.Status {
+			return nil
+		}
+
+		status := false
+		return s.userRepository.UpdateUser(ctx, user, &dto.UserInputDTO{Status: &status})
+	}
+
+	if user.Profile != nil && user.Profile.Name == profileName {
+		return nil
+	}
+
+	profile, err := s.profileRepository.GetProfileByName(ctx, profileName)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return s.claims2user(ctx, parsedToken, ip)
+	if err := s.userRepository.UpdateUser(ctx, user, &dto.UserInputDTO{ProfileID: &profile.Id}); err != nil {
+		return err
+	}
+
+	user.Profile = profile
+	return nil
 }
 
-func (s *authRepository) Refresh(ctx context.Context, user *domain.User, ip string) (*domain.TokensResponse, error) {
-	accessTime, refreshTime := "-", "-"
-	if user.Expire {
-		accessTime = os.Getenv("ACCESS_TOKEN_EXPIRE")
-		refreshTime = os.Getenv("RFRESH_TOKEN_EXPIRE")
+// DirectoryLogin authenticates the user in the directory, provisioning it in the profile mapped from its groups.
+func (s *authRepository) DirectoryLogin(ctx context.Context, login, password string) (*domain.User, error) {
+	if s.directory == nil {
+		return nil, domain.ErrDirectoryDisabled
 	}
 
-	accessToken, err := user.GenerateToken(accessTime, os.Getenv("ACCESS_TOKEN_PRIVAT"), ip)
+	entry, err := s.directory.Authenticate(login, password)
 	if err != nil {
 		return nil, err
 	}
+	if entry.Email == "" {
+		return nil, ldapauth.ErrInvalidCredentials
+	}
 
-	refreshToken, err := user.GenerateToken(refreshTime, os.Getenv("RFRESH_TOKEN_PRIVAT"), ip)
+	profileName := s.groupMap.Profile(entry.Groups)
+	user, err := s.GetExternalUser(ctx, &domain.ExternalIdentity{Email: entry.Email, Name: entry.Name, Profile: profileName, Source: domain.UserSourceLDAP})
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.TokensResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}, nil
+	return user, s.syncDirectoryUser(ctx, user, profileName)
 }
 
-func (s *authRepository) GetUserByMail(ctx context.Context, userMail string) (*domain.User, error) {
-	return s.userRepository.GetUserByMail(ctx, userMail)
+// SyncDirectory disables the directory users removed from the directory or from the mapped groups.
+func (s *authRepository) SyncDirectory(ctx context.Context) error {
+	if s.directory == nil {
+		return domain.ErrDirectoryDisabled
+	}
+
+	entries, err := s.directory.Users()
+	if err != nil {
+		return err
+	}
+	// An empty answer is more likely a filter or permission change than every user removed, so nobody is disabled.
+	if len(entries) == 0 {
+		return domain.ErrDirectoryEmpty
+	}
+
+	profiles := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		profiles[strings.ToLower(entry.Email)] = s.groupMap.Profile(entry.Groups)
+	}
+
+	response, err := s.userRepository.GetUsersOutputDTO(ctx, &filter.UserFilter{
+		Filter: filter.Filter{Sort: domain.UserTableName + ".id", Order: "asc"},
+		Source: domain.UserSourceLDAP,
+	})
+	if err != nil {
+		return err
+	}
+
+	users := *response.Items.(*[]domain.User)
+	for i := range users {
+		if err := s.syncDirectoryUser(ctx, &users[i], profiles[strings.ToLower(users[i].Email)]); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
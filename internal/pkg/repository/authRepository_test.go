@@ -0,0 +1,219 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
+	"github.com/raulaguila/go-pass/configs"
+	"github.com/raulaguila/go-pass/internal/infra/database"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/repository"
+	"github.com/raulaguila/go-pass/pkg/ldapauth"
+	"github.com/raulaguila/go-pass/pkg/passpolicy"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+const (
+	testUserDN  string = "ou=people,dc=example,dc=org"
+	testGroupDN string = "ou=groups,dc=example,dc=org"
+)
+
+// newTestDB opens a migrated SQLite database with the ROOT and USER profiles.
+func newTestDB(t *testing.T) *gorm.DB {
+	db := database.OpenSQLiteDB(configs.SQLiteConfig{Path: filepath.Join(t.TempDir(), "gopass.db")})
+	migrator, err := database.NewMigrator(db)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	if _, err := migrator.Up(context.Background()); !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	profiles := repository.NewProfileRepository(db)
+	for _, name := range []string{"ROOT", "USER"} {
+		if _, err := profiles.CreateProfile(context.Background(), &dto.ProfileInputDTO{Name: &name}); !assert.Nil(t, err) {
+			t.FailNow()
+		}
+	}
+
+	return db
+}
+
+// newTestDirectory starts a directory where alice is in the admins group and bob in the users group.
+func newTestDirectory(t *testing.T) *ldapauth.Directory {
+	td := testdirectory.Start(t, testdirectory.WithNoTLS(t), testdirectory.WithDefaults(t, &testdirectory.Defaults{
+		UserDN:  testUserDN,
+		GroupDN: testGroupDN,
+	}))
+
+	td.SetUsers(
+		gldap.NewEntry("cn=service,dc=example,dc=org", map[string][]string{"password": {"service-pass"}}),
+		gldap.NewEntry("uid=alice,"+testUserDN, map[string][]string{
+			"mail":     {"alice@example.com"},
+			"cn":       {"Alice Smith"},
+			"password": {"alice-pass"},
+			"memberOf": {"cn=admins," + testGroupDN},
+		}),
+		gldap.NewEntry("uid=bob,"+testUserDN, map[string][]string{
+			"mail":     {"bob@example.com"},
+			"cn":       {"Bob Jones"},
+			"password": {"bob-pass"},
+			"memberOf": {"cn=users," + testGroupDN},
+		}),
+	)
+
+	return ldapauth.New(ldapauth.Config{
+		URL:          fmt.Sprintf("ldap://%v:%v", td.Host(), td.Port()),
+		BindDN:       "cn=service,dc=example,dc=org",
+		BindPassword: "service-pass",
+		UserBaseDN:   testUserDN,
+		UserFilter:   "(uid=%s)",
+	})
+}
+
+// newEmptyDirectory starts a directory accepting every bind, whose searches succeed without entries.
+func newEmptyDirectory(t *testing.T) *ldapauth.Directory {
+	mux, err := gldap.NewMux()
+	assert.Nil(t, err)
+	assert.Nil(t, mux.Bind(func(w *gldap.ResponseWriter, r *gldap.Request) {
+		_ = w.Write(r.NewBindResponse(gldap.WithResponseCode(gldap.ResultSuccess)))
+	}))
+	assert.Nil(t, mux.Search(func(w *gldap.ResponseWriter, r *gldap.Request) {
+		_ = w.Write(r.NewSearchDoneResponse(gldap.WithResponseCode(gldap.ResultSuccess)))
+	}))
+
+	server, err := gldap.NewServer()
+	assert.Nil(t, err)
+	assert.Nil(t, server.Router(mux))
+	port := testdirectory.FreePort(t)
+	go func() { _ = server.Run(fmt.Sprintf("127.0.0.1:%v", port)) }()
+	t.Cleanup(func() { _ = server.Stop() })
+	for !server.Ready() {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return ldapauth.New(ldapauth.Config{URL: fmt.Sprintf("ldap://127.0.0.1:%v", port), UserBaseDN: testUserDN})
+}
+
+func newAuthRepository(t *testing.T, db *gorm.DB, directory *ldapauth.Directory) domain.AuthRepository {
+	groupMap, err := ldapauth.ParseGroupMap("admins:ROOT,users:USER")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	return repository.NewAuthRepository(repository.NewUserRepository(db, passpolicy.Default()), repository.NewProfileRepository(db), nil, nil, 0, 0, directory, groupMap)
+}
+
+// go test -run TestDirectoryLogin
+func TestDirectoryLogin(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	auth := newAuthRepository(t, db, newTestDirectory(t))
+
+	user, err := auth.DirectoryLogin(ctx, "alice", "alice-pass")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "alice@example.com", user.Email)
+		assert.Equal(t, "Alice Smith", user.Name)
+		assert.Equal(t, domain.UserSourceLDAP, user.Source)
+		assert.Equal(t, "ROOT", user.Profile.Name)
+		assert.True(t, user.Status)
+		assert.NotNil(t, user.Token)
+	}
+
+	again, err := auth.DirectoryLogin(ctx, "alice", "alice-pass")
+	if assert.Nil(t, err) {
+		assert.Equal(t, user.Id, again.Id)
+	}
+
+	_, err = auth.DirectoryLogin(ctx, "alice", "wrong-pass")
+	assert.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+
+	_, err = auth.DirectoryLogin(ctx, "carol", "carol-pass")
+	assert.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+
+	_, err = newAuthRepository(t, db, nil).DirectoryLogin(ctx, "alice", "alice-pass")
+	assert.ErrorIs(t, err, domain.ErrDirectoryDisabled)
+}
+
+// go test -run TestExternalUserConflict
+func TestExternalUserConflict(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	auth := newAuthRepository(t, db, newTestDirectory(t))
+	users := repository.NewUserRepository(db, passpolicy.Default())
+
+	// alice was invited as a local user and bob logged in by the identity provider.
+	profile, err := repository.NewProfileRepository(db).GetProfileByName(ctx, "ROOT")
+	assert.Nil(t, err)
+	invited, err := users.CreateUser(ctx, &dto.UserInputDTO{Name: ptr("Alice Local"), Email: ptr("alice@example.com"), Status: ptr(true), ProfileID: &profile.Id})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	_, err = auth.GetExternalUser(ctx, &domain.ExternalIdentity{Email: "bob@example.com", Name: "Bob Jones", Profile: "USER", Source: domain.UserSourceOIDC})
+	assert.Nil(t, err)
+
+	for _, login := range []string{"alice", "bob"} {
+		_, err := auth.DirectoryLogin(ctx, login, login+"-pass")
+		assert.ErrorIs(t, err, domain.ErrExternalUserConflict, login)
+	}
+
+	user, err := users.GetUserByID(ctx, invited.Id)
+	if assert.Nil(t, err) {
+		assert.Equal(t, domain.UserSourceLocal, user.Source)
+		assert.Nil(t, user.Token)
+	}
+}
+
+// go test -run TestSyncDirectory
+func TestSyncDirectory(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	auth := newAuthRepository(t, db, newTestDirectory(t))
+	users := repository.NewUserRepository(db, passpolicy.Default())
+
+	for _, login := range []string{"alice", "bob"} {
+		_, err := auth.DirectoryLogin(ctx, login, login+"-pass")
+		assert.Nil(t, err)
+	}
+
+	// carol left the directory, dave is a local user.
+	profile, err := repository.NewProfileRepository(db).GetProfileByName(ctx, "USER")
+	assert.Nil(t, err)
+	for _, item := range []struct{ name, mail, source string }{{"Carol White", "carol@example.com", domain.UserSourceLDAP}, {"Dave Brown", "dave@example.com", ""}} {
+		user, err := users.CreateUser(ctx, &dto.UserInputDTO{Name: &item.name, Email: &item.mail, Status: ptr(true), ProfileID: &profile.Id})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		user.Source = item.source
+		assert.Nil(t, users.ActivateUser(ctx, user))
+	}
+
+	assert.ErrorIs(t, newAuthRepository(t, db, newEmptyDirectory(t)).SyncDirectory(ctx), domain.ErrDirectoryEmpty)
+	for _, mail := range []string{"alice@example.com", "bob@example.com", "carol@example.com", "dave@example.com"} {
+		user, err := users.GetUserByMail(ctx, mail)
+		if assert.Nil(t, err) {
+			assert.True(t, user.Status, mail)
+		}
+	}
+
+	assert.Nil(t, auth.SyncDirectory(ctx))
+	for mail, status := range map[string]bool{"alice@example.com": true, "bob@example.com": true, "carol@example.com": false, "dave@example.com": true} {
+		user, err := users.GetUserByMail(ctx, mail)
+		if assert.Nil(t, err) {
+			assert.Equal(t, status, user.Status, mail)
+		}
+	}
+
+	assert.ErrorIs(t, newAuthRepository(t, db, nil).SyncDirectory(ctx), domain.ErrDirectoryDisabled)
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
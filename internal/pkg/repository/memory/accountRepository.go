@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/filter"
+	"github.com/raulaguila/go-pass/pkg/pgerror"
+)
+
+func NewAccountRepository(db *DB) domain.AccountRepository {
+	return &accountRepository{
+		db: db,
+	}
+}
+
+type accountRepository struct {
+	db *DB
+}
+
+func (s *accountRepository) GetAccountsOutputDTO(ctx context.Context, filter *filter.AccountFilter, userID uint) (*dto.ItemsOutputDTO, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	ids := rows(s.db.accounts, &filter.Filter, func(_ uint, account domain.Account) bool {
+		if len(filter.SiteIDs) > 0 && !slices.Contains(filter.SiteIDs, account.SiteID) {
+			return false
+		}
+
+		return matches(&filter.Filter, account.Username)
+	})
+
+	items := []domain.Account{}
+	for _, id := range paginate(ids, &filter.Filter) {
+		account, _ := s.db.account(id)
+		items = append(items, *account)
+	}
+
+	return &dto.ItemsOutputDTO{
+		Items: &items,
+		Count: int64(len(ids)),
+	}, nil
+}
+
+func (s *accountRepository) GetAccountByID(ctx context.Context, accountID, userID uint) (*domain.Account, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return s.db.account(accountID)
+}
+
+// save writes the account, keeping its site, phone, mail and user existing.
+func (s *accountRepository) save(account *domain.Account) error {
+	// Named like the constraints of the schema, the handlers tell the missing relation by the name.
+	if _, ok := s.db.sites[account.SiteID]; !ok {
+		return fmt.Errorf("%w: fk_account_site", pgerror.ErrForeignKeyViolated)
+	}
+	if _, ok := s.db.phones[account.PhoneID]; !ok {
+		return fmt.Errorf("%w: fk_account_phone", pgerror.ErrForeignKeyViolated)
+	}
+	if _, ok := s.db.accounts[account.MailID]; account.MailID != 0 && !ok {
+		return fmt.Errorf("%w: fk_account_mail", pgerror.ErrForeignKeyViolated)
+	}
+	if _, ok := s.db.users[account.UserID]; !ok {
+		return fmt.Errorf("%w: fk_account_user", pgerror.ErrForeignKeyViolated)
+	}
+
+	if account.Id == 0 {
+		account.Id = s.db.nextID()
+	}
+
+	touch(&account.Base)
+	stored := *account
+	stored.Site, stored.Phone, stored.Mail, stored.User = nil, nil, nil, nil
+	s.db.accounts[account.Id] = stored
+	return nil
+}
+
+func (s *accountRepository) CreateAccount(ctx context.Context, datas *dto.AccountInputDTO, userID uint) (*domain.Account, error) {
+	account := &domain.Account{UserID: userID}
+	if err := account.Bind(datas); err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if err := s.save(account); err != nil {
+		return nil, err
+	}
+
+	return s.db.account(account.Id)
+}
+
+func (s *accountRepository) UpdateAccount(ctx context.Context, account *domain.Account, datas *dto.AccountInputDTO, userID uint) (*domain.Account, error) {
+	mailID := account.MailID
+	if err := account.Bind(datas); err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.accounts[account.Id]; ok {
+		if err := s.save(account); err != nil {
+			return nil, err
+		}
+	}
+
+	if account.MailID != mailID && account.MailID != 0 {
+		history := domain.AccountMailHistory{AccountID: account.Id, MailID: account.MailID}
+		history.Id = s.db.nextID()
+		touch(&history.Base)
+		s.db.mailHistory = append(s.db.mailHistory, history)
+	}
+
+	return s.db.account(account.Id)
+}
+
+func (s *accountRepository) DeleteAccount(ctx context.Context, account *domain.Account, userID uint) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.accounts[account.Id]
+	if !ok || stored.UserID != userID {
+		return nil
+	}
+
+	for _, other := range s.db.accounts {
+		if other.MailID == account.Id {
+			return pgerror.ErrForeignKeyViolated
+		}
+	}
+	for _, history := range s.db.mailHistory {
+		if history.AccountID == account.Id || history.MailID == account.Id {
+			return pgerror.ErrForeignKeyViolated
+		}
+	}
+
+	delete(s.db.accounts, account.Id)
+	return nil
+}
@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+)
+
+func NewAuditRepository(db *DB) domain.AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+type auditRepository struct {
+	db *DB
+}
+
+func (s *auditRepository) CreateAuditLog(ctx context.Context, audit *domain.AuditLog) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	audit.Id = s.db.nextID()
+	touch(&audit.Base)
+	s.db.auditLogs = append(s.db.auditLogs, *audit)
+	return nil
+}
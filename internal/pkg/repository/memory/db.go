@@ -0,0 +1,188 @@
+// Package memory implements the domain repositories without a database, to run the API in the tests.
+//
+// The repositories behave like the database ones: the unique and foreign keys of the schema are enforced with the
+// errors of the pgerror package, and the missing rows return gorm.ErrRecordNotFound. The lists are ordered by id,
+// the sort column of the filters is ignored.
+package memory
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/pkg/filter"
+	"gorm.io/gorm"
+)
+
+// DB is the storage shared by the repositories, each table keeps copies of the rows without their relations.
+type DB struct {
+	mu     sync.Mutex
+	lastID uint
+
+	profiles        map[uint]domain.Profile
+	users           map[uint]domain.User
+	passwordHistory map[uint][]domain.PasswordHistory
+	operators       map[uint]domain.Operator
+	phones          map[uint]domain.Phone
+	sites           map[uint]domain.Site
+	accounts        map[uint]domain.Account
+	mailHistory     []domain.AccountMailHistory
+	personalTokens  map[uint]domain.PersonalToken
+	auditLogs       []domain.AuditLog
+	userTokens      map[uint]domain.UserToken
+}
+
+func NewDB() *DB {
+	return &DB{
+		profiles:        map[uint]domain.Profile{},
+		users:           map[uint]domain.User{},
+		passwordHistory: map[uint][]domain.PasswordHistory{},
+		operators:       map[uint]domain.Operator{},
+		phones:          map[uint]domain.Phone{},
+		sites:           map[uint]domain.Site{},
+		accounts:        map[uint]domain.Account{},
+		personalTokens:  map[uint]domain.PersonalToken{},
+		userTokens:      map[uint]domain.UserToken{},
+	}
+}
+
+// AuditLogs returns the audit logs in the order they were created.
+func (db *DB) AuditLogs() []domain.AuditLog {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return slices.Clone(db.auditLogs)
+}
+
+// nextID returns a new id, unique among every table.
+func (db *DB) nextID() uint {
+	db.lastID++
+	return db.lastID
+}
+
+// touch sets the timestamps of a row being written, like the autoCreateTime and autoUpdateTime of gorm.
+func touch(base *domain.Base) {
+	now := time.Now()
+	if base.CreatedAt.IsZero() {
+		base.CreatedAt = now
+	}
+	base.UpdatedAt = now
+}
+
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+
+	cloned := *value
+	return &cloned
+}
+
+func (db *DB) profile(id uint) (*domain.Profile, error) {
+	profile, ok := db.profiles[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &profile, nil
+}
+
+func (db *DB) user(id uint) (*domain.User, error) {
+	user, ok := db.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	user.Token = clonePointer(user.Token)
+	user.Password = clonePointer(user.Password)
+	user.Profile, _ = db.profile(user.ProfileID)
+	return &user, nil
+}
+
+func (db *DB) operator(id uint) (*domain.Operator, error) {
+	operator, ok := db.operators[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &operator, nil
+}
+
+func (db *DB) phone(id uint) (*domain.Phone, error) {
+	phone, ok := db.phones[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	phone.Operator, _ = db.operator(phone.OperatorID)
+	return &phone, nil
+}
+
+func (db *DB) site(id uint) (*domain.Site, error) {
+	site, ok := db.sites[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &site, nil
+}
+
+// account returns the account with its site, mail and the phone with its operator, like the account preloads.
+func (db *DB) account(id uint) (*domain.Account, error) {
+	account, ok := db.accounts[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	account.Site, _ = db.site(account.SiteID)
+	account.Phone, _ = db.phone(account.PhoneID)
+	if mail, ok := db.accounts[account.MailID]; ok {
+		account.Mail = &mail
+	}
+	return &account, nil
+}
+
+// rows returns the rows of a table matching the keep function, ordered by id in the filter order.
+func rows[T any](table map[uint]T, f *filter.Filter, keep func(uint, T) bool) []uint {
+	ids := []uint{}
+	for id, row := range table {
+		if keep(id, row) {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+	if f != nil && f.Descending() {
+		slices.Reverse(ids)
+	}
+
+	return ids
+}
+
+// paginate returns the page of ids selected by the filter, like its ApplyPagination.
+func paginate(ids []uint, f *filter.Filter) []uint {
+	if f.Page <= 0 || f.Limit <= 0 {
+		return ids
+	}
+
+	start := min((f.Page-1)*f.Limit, len(ids))
+	return ids[start:min(start+f.Limit, len(ids))]
+}
+
+// matches reports whether any value contains the search of the filter, ignoring the case, like its ApplySearchLike.
+func matches(f *filter.Filter, values ...string) bool {
+	if f.Search == "" {
+		return true
+	}
+
+	search := strings.ToLower(f.Search)
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), search) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/filter"
+	"github.com/raulaguila/go-pass/pkg/pgerror"
+)
+
+func NewOperatorRepository(db *DB) domain.OperatorRepository {
+	return &operatorRepository{
+		db: db,
+	}
+}
+
+type operatorRepository struct {
+	db *DB
+}
+
+func (s *operatorRepository) GetOperatorsOutputDTO(ctx context.Context, filter *filter.Filter) (*dto.ItemsOutputDTO, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	ids := rows(s.db.operators, filter, func(_ uint, operator domain.Operator) bool {
+		return matches(filter, operator.Name)
+	})
+
+	items := []domain.Operator{}
+	for _, id := range paginate(ids, filter) {
+		operator, _ := s.db.operator(id)
+		items = append(items, *operator)
+	}
+
+	return &dto.ItemsOutputDTO{
+		Items: &items,
+		Count: int64(len(ids)),
+	}, nil
+}
+
+func (s *operatorRepository) GetOperatorByID(ctx context.Context, operatorID uint) (*domain.Operator, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return s.db.operator(operatorID)
+}
+
+// save writes the operator, keeping the names unique.
+func (s *operatorRepository) save(operator *domain.Operator) error {
+	for id, stored := range s.db.operators {
+		if id != operator.Id && stored.Name == operator.Name {
+			return pgerror.ErrDuplicatedKey
+		}
+	}
+
+	if operator.Id == 0 {
+		operator.Id = s.db.nextID()
+	}
+
+	touch(&operator.Base)
+	s.db.operators[operator.Id] = *operator
+	return nil
+}
+
+func (s *operatorRepository) CreateOperator(ctx context.Context, datas *dto.OperatorInputDTO) (*domain.Operator, error) {
+	operator := &domain.Operator{}
+	if err := operator.Bind(datas); err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return operator, s.save(operator)
+}
+
+func (s *operatorRepository) UpdateOperator(ctx context.Context, operator *domain.Operator, datas *dto.OperatorInputDTO) error {
+	if err := operator.Bind(datas); err != nil {
+		return err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.operators[operator.Id]; !ok {
+		return nil
+	}
+
+	return s.save(operator)
+}
+
+func (s *operatorRepository) DeleteOperator(ctx context.Context, operator *domain.Operator) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for _, phone := range s.db.phones {
+		if phone.OperatorID == operator.Id {
+			return pgerror.ErrForeignKeyViolated
+		}
+	}
+
+	delete(s.db.operators, operator.Id)
+	return nil
+}
@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/filter"
+	"github.com/raulaguila/go-pass/pkg/pgerror"
+)
+
+func NewPhoneRepository(db *DB) domain.PhoneRepository {
+	return &phoneRepository{
+		db: db,
+	}
+}
+
+type phoneRepository struct {
+	db *DB
+}
+
+func (s *phoneRepository) GetPhonesOutputDTO(ctx context.Context, filter *filter.Filter) (*dto.ItemsOutputDTO, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	ids := rows(s.db.phones, filter, func(_ uint, phone domain.Phone) bool {
+		return matches(filter, phone.Number)
+	})
+
+	items := []domain.Phone{}
+	for _, id := range paginate(ids, filter) {
+		phone, _ := s.db.phone(id)
+		items = append(items, *phone)
+	}
+
+	return &dto.ItemsOutputDTO{
+		Items: &items,
+		Count: int64(len(ids)),
+	}, nil
+}
+
+func (s *phoneRepository) GetPhoneByID(ctx context.Context, phoneID uint) (*domain.Phone, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return s.db.phone(phoneID)
+}
+
+// save writes the phone, keeping the numbers unique and the operator existing.
+func (s *phoneRepository) save(phone *domain.Phone) error {
+	if _, ok := s.db.operators[phone.OperatorID]; !ok {
+		return pgerror.ErrForeignKeyViolated
+	}
+
+	for id, stored := range s.db.phones {
+		if id != phone.Id && stored.Number == phone.Number {
+			return pgerror.ErrDuplicatedKey
+		}
+	}
+
+	if phone.Id == 0 {
+		phone.Id = s.db.nextID()
+	}
+
+	touch(&phone.Base)
+	stored := *phone
+	stored.Operator = nil
+	s.db.phones[phone.Id] = stored
+	return nil
+}
+
+func (s *phoneRepository) CreatePhone(ctx context.Context, datas *dto.PhoneInputDTO) (*domain.Phone, error) {
+	phone := &domain.Phone{}
+	if err := phone.Bind(datas); err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if err := s.save(phone); err != nil {
+		return nil, err
+	}
+
+	return s.db.phone(phone.Id)
+}
+
+func (s *phoneRepository) UpdatePhone(ctx context.Context, phone *domain.Phone, datas *dto.PhoneInputDTO) error {
+	if err := phone.Bind(datas); err != nil {
+		return err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.phones[phone.Id]; !ok {
+		return nil
+	}
+
+	return s.save(phone)
+}
+
+func (s *phoneRepository) DeletePhone(ctx context.Context, phone *domain.Phone) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for _, account := range s.db.accounts {
+		if account.PhoneID == phone.Id {
+			return pgerror.ErrForeignKeyViolated
+		}
+	}
+
+	delete(s.db.phones, phone.Id)
+	return nil
+}
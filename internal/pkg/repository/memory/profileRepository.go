@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/filter"
+	"github.com/raulaguila/go-pass/pkg/pgerror"
+	"gorm.io/gorm"
+)
+
+func NewProfileRepository(db *DB) domain.ProfileRepository {
+	return &profileRepository{
+		db: db,
+	}
+}
+
+type profileRepository struct {
+	db *DB
+}
+
+func (s *profileRepository) GetProfilesOutputDTO(ctx context.Context, filter *filter.Filter) (*dto.ItemsOutputDTO, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	ids := rows(s.db.profiles, filter, func(_ uint, profile domain.Profile) bool {
+		return matches(filter, profile.Name)
+	})
+
+	items := []domain.Profile{}
+	for _, id := range paginate(ids, filter) {
+		profile, _ := s.db.profile(id)
+		items = append(items, *profile)
+	}
+
+	return &dto.ItemsOutputDTO{
+		Items: &items,
+		Count: int64(len(ids)),
+	}, nil
+}
+
+func (s *profileRepository) GetProfileByID(ctx context.Context, profileID uint) (*domain.Profile, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return s.db.profile(profileID)
+}
+
+func (s *profileRepository) GetProfileByName(ctx context.Context, name string) (*domain.Profile, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for id, profile := range s.db.profiles {
+		if profile.Name == name {
+			return s.db.profile(id)
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// save writes the profile, keeping the names unique.
+func (s *profileRepository) save(profile *domain.Profile) error {
+	for id, stored := range s.db.profiles {
+		if id != profile.Id && stored.Name == profile.Name {
+			return pgerror.ErrDuplicatedKey
+		}
+	}
+
+	if profile.Id == 0 {
+		profile.Id = s.db.nextID()
+		profile.Permissions.Id = s.db.nextID()
+	}
+	profile.Permissions.ProfileID = profile.Id
+
+	touch(&profile.Base)
+	s.db.profiles[profile.Id] = *profile
+	return nil
+}
+
+func (s *profileRepository) CreateProfile(ctx context.Context, datas *dto.ProfileInputDTO) (*domain.Profile, error) {
+	profile := &domain.Profile{}
+	if err := profile.Bind(datas); err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return profile, s.save(profile)
+}
+
+func (s *profileRepository) UpdateProfile(ctx context.Context, profile *domain.Profile, datas *dto.ProfileInputDTO) error {
+	if err := profile.Bind(datas); err != nil {
+		return err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.profiles[profile.Id]; !ok {
+		return nil
+	}
+
+	return s.save(profile)
+}
+
+func (s *profileRepository) DeleteProfile(ctx context.Context, profile *domain.Profile) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for _, user := range s.db.users {
+		if user.ProfileID == profile.Id {
+			return pgerror.ErrForeignKeyViolated
+		}
+	}
+
+	delete(s.db.profiles, profile.Id)
+	return nil
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/secretref"
+	"gorm.io/gorm"
+)
+
+func NewSecretRepository(db *DB, auditRepository domain.AuditRepository) domain.SecretRepository {
+	return &secretRepository{
+		db:              db,
+		auditRepository: auditRepository,
+	}
+}
+
+type secretRepository struct {
+	db              *DB
+	auditRepository domain.AuditRepository
+}
+
+func (s *secretRepository) getAccountByReference(ref *secretref.Reference) (*domain.Account, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	ids := rows(s.db.accounts, nil, func(_ uint, account domain.Account) bool {
+		site, ok := s.db.sites[account.SiteID]
+		return ok && strings.EqualFold(site.Name, ref.Site) && account.Username == ref.Account
+	})
+	switch {
+	case len(ids) == 0:
+		return nil, gorm.ErrRecordNotFound
+	case len(ids) > 1:
+		return nil, domain.ErrSecretAmbiguous
+	}
+
+	return s.db.account(ids[0])
+}
+
+func (s *secretRepository) audit(ctx context.Context, requester *domain.SecretRequester, ref string, err error) error {
+	audit := &domain.AuditLog{
+		Action:    domain.AuditSecretResolve,
+		Target:    ref,
+		Success:   err == nil,
+		UserID:    requester.User.Id,
+		IP:        requester.IP,
+		RequestID: requester.RequestID,
+	}
+
+	if err != nil {
+		audit.Detail = err.Error()
+	}
+
+	if requester.Token != nil {
+		audit.TokenID = &requester.Token.Id
+	}
+
+	return s.auditRepository.CreateAuditLog(ctx, audit)
+}
+
+func (s *secretRepository) resolve(item dto.SecretRefInputDTO, requester *domain.SecretRequester) (*secretref.Secret, error) {
+	ref, err := secretref.Parse(item.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.getAccountByReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if requester.Token != nil && !requester.Token.AllowsSite(account.SiteID) {
+		return nil, domain.ErrSecretForbidden
+	}
+
+	secret := &secretref.Secret{Name: item.Name, Ref: ref.String()}
+	if secret.Name == "" {
+		secret.Name = ref.EnvName()
+	}
+
+	switch ref.Field {
+	case secretref.FieldUsername:
+		secret.Value = account.Username
+	case secretref.FieldURL:
+		secret.Value = account.Site.URL
+	default:
+		secret.Value = account.DecodePass()
+	}
+
+	return secret, nil
+}
+
+// ResolveSecrets resolves every reference or none. The resolutions are audited once the outcome is known, a failure
+// is recorded on every reference attempted, as none of their values is returned.
+func (s *secretRepository) ResolveSecrets(ctx context.Context, datas *dto.SecretResolveInputDTO, requester *domain.SecretRequester) ([]secretref.Secret, error) {
+	secrets := make([]secretref.Secret, 0, len(datas.Secrets))
+	attempted := datas.Secrets
+
+	var err error
+	for i, item := range datas.Secrets {
+		secret, resolveErr := s.resolve(item, requester)
+		if resolveErr != nil {
+			err = &domain.SecretError{Ref: item.Ref, Err: resolveErr}
+			attempted = datas.Secrets[:i+1]
+			break
+		}
+		secrets = append(secrets, *secret)
+	}
+
+	for _, item := range attempted {
+		if auditErr := s.audit(ctx, requester, item.Ref, err); auditErr != nil {
+			return nil, auditErr
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/filter"
+	"github.com/raulaguila/go-pass/pkg/pgerror"
+)
+
+func NewSiteRepository(db *DB) domain.SiteRepository {
+	return &siteRepository{
+		db: db,
+	}
+}
+
+type siteRepository struct {
+	db *DB
+}
+
+func (s *siteRepository) GetSitesOutputDTO(ctx context.Context, filter *filter.Filter) (*dto.ItemsOutputDTO, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	ids := rows(s.db.sites, filter, func(_ uint, site domain.Site) bool {
+		return matches(filter, site.Name)
+	})
+
+	items := []domain.Site{}
+	for _, id := range paginate(ids, filter) {
+		site, _ := s.db.site(id)
+		items = append(items, *site)
+	}
+
+	return &dto.ItemsOutputDTO{
+		Items: &items,
+		Count: int64(len(ids)),
+	}, nil
+}
+
+func (s *siteRepository) GetSiteByID(ctx context.Context, siteID uint) (*domain.Site, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return s.db.site(siteID)
+}
+
+// save writes the site, keeping the names and the urls unique.
+func (s *siteRepository) save(site *domain.Site) error {
+	for id, stored := range s.db.sites {
+		if id != site.Id && (stored.Name == site.Name || stored.URL == site.URL) {
+			return pgerror.ErrDuplicatedKey
+		}
+	}
+
+	if site.Id == 0 {
+		site.Id = s.db.nextID()
+	}
+
+	touch(&site.Base)
+	s.db.sites[site.Id] = *site
+	return nil
+}
+
+func (s *siteRepository) CreateSite(ctx context.Context, datas *dto.SiteInputDTO) (*domain.Site, error) {
+	site := &domain.Site{}
+	if err := site.Bind(datas); err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return site, s.save(site)
+}
+
+func (s *siteRepository) UpdateSite(ctx context.Context, site *domain.Site, datas *dto.SiteInputDTO) error {
+	if err := site.Bind(datas); err != nil {
+		return err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.sites[site.Id]; !ok {
+		return nil
+	}
+
+	return s.save(site)
+}
+
+func (s *siteRepository) DeleteSite(ctx context.Context, site *domain.Site) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for _, account := range s.db.accounts {
+		if account.SiteID == site.Id {
+			return pgerror.ErrForeignKeyViolated
+		}
+	}
+
+	delete(s.db.sites, site.Id)
+	return nil
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/pgerror"
+	"gorm.io/gorm"
+)
+
+func NewPersonalTokenRepository(db *DB) domain.PersonalTokenRepository {
+	return &personalTokenRepository{
+		db: db,
+	}
+}
+
+type personalTokenRepository struct {
+	db *DB
+}
+
+func (s *personalTokenRepository) GetPersonalTokensOutputDTO(ctx context.Context, userID uint) (*dto.ItemsOutputDTO, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	// The newest first, like the order by creation of the database.
+	ids := rows(s.db.personalTokens, nil, func(_ uint, token domain.PersonalToken) bool {
+		return token.UserID == userID
+	})
+	slices.Reverse(ids)
+
+	tokens := []domain.PersonalToken{}
+	for _, id := range ids {
+		tokens = append(tokens, s.db.personalTokens[id])
+	}
+
+	return &dto.ItemsOutputDTO{
+		Items: &tokens,
+		Count: int64(len(tokens)),
+	}, nil
+}
+
+func (s *personalTokenRepository) GetPersonalTokenByID(ctx context.Context, tokenID, userID uint) (*domain.PersonalToken, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	token, ok := s.db.personalTokens[tokenID]
+	if !ok || token.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &token, nil
+}
+
+func (s *personalTokenRepository) CreatePersonalToken(ctx context.Context, datas *dto.PersonalTokenInputDTO, userID uint) (*domain.PersonalTokenResponse, error) {
+	token := &domain.PersonalToken{UserID: userID}
+	if err := token.Bind(datas); err != nil {
+		return nil, err
+	}
+
+	secret, err := token.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.users[userID]; !ok {
+		return nil, pgerror.ErrForeignKeyViolated
+	}
+	for _, stored := range s.db.personalTokens {
+		if stored.Hash == token.Hash {
+			return nil, pgerror.ErrDuplicatedKey
+		}
+	}
+
+	token.Id = s.db.nextID()
+	touch(&token.Base)
+	s.db.personalTokens[token.Id] = *token
+
+	return &domain.PersonalTokenResponse{
+		PersonalToken: token,
+		Token:         secret,
+	}, nil
+}
+
+func (s *personalTokenRepository) DeletePersonalToken(ctx context.Context, token *domain.PersonalToken) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	delete(s.db.personalTokens, token.Id)
+	return nil
+}
+
+func (s *personalTokenRepository) Authenticate(ctx context.Context, secret, ip string) (*domain.PersonalToken, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	hash := domain.HashPersonalToken(secret)
+	ids := rows(s.db.personalTokens, nil, func(_ uint, token domain.PersonalToken) bool {
+		return token.Hash == hash
+	})
+	if len(ids) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	token := s.db.personalTokens[ids[0]]
+	token.User, _ = s.db.user(token.UserID)
+
+	if token.Expired() {
+		return nil, domain.ErrPersonalTokenExpired
+	}
+
+	if !token.AllowsIP(ip) {
+		return nil, domain.ErrInvalidIpAssociation
+	}
+
+	if token.User == nil || !token.User.Status {
+		return nil, domain.ErrDisabledUser
+	}
+
+	now := time.Now()
+	token.LastUsedAt = &now
+
+	stored := token
+	stored.User = nil
+	s.db.personalTokens[token.Id] = stored
+	return &token, nil
+}
@@ -0,0 +1,316 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/pkg/filter"
+	"github.com/raulaguila/go-pass/pkg/hasher"
+	"github.com/raulaguila/go-pass/pkg/passpolicy"
+	"github.com/raulaguila/go-pass/pkg/pgerror"
+	"gorm.io/gorm"
+)
+
+func NewUserRepository(db *DB, policy *passpolicy.Policy) domain.UserRepository {
+	return &userRepository{
+		db:     db,
+		policy: policy,
+	}
+}
+
+type userRepository struct {
+	db     *DB
+	policy *passpolicy.Policy
+}
+
+func (s *userRepository) GetUsersOutputDTO(ctx context.Context, filter *filter.UserFilter) (*dto.ItemsOutputDTO, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	ids := rows(s.db.users, &filter.Filter, func(_ uint, user domain.User) bool {
+		if filter.ProfileID != 0 && user.ProfileID != filter.ProfileID {
+			return false
+		}
+		if filter.Source != "" && user.Source != filter.Source {
+			return false
+		}
+
+		profile, _ := s.db.profile(user.ProfileID)
+		return matches(&filter.Filter, user.Name, user.Email, profile.Name)
+	})
+
+	items := []domain.User{}
+	for _, id := range paginate(ids, &filter.Filter) {
+		user, _ := s.db.user(id)
+		items = append(items, *user)
+	}
+
+	return &dto.ItemsOutputDTO{
+		Items: &items,
+		Count: int64(len(ids)),
+	}, nil
+}
+
+func (s *userRepository) GetUserByID(ctx context.Context, userID uint) (*domain.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return s.db.user(userID)
+}
+
+// find returns the first user, by id, matching the keep function.
+func (s *userRepository) find(keep func(domain.User) bool) (*domain.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	ids := rows(s.db.users, nil, func(_ uint, user domain.User) bool {
+		return keep(user)
+	})
+	if len(ids) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return s.db.user(ids[0])
+}
+
+func (s *userRepository) GetUserByMail(ctx context.Context, mail string) (*domain.User, error) {
+	return s.find(func(user domain.User) bool {
+		return user.Email == mail
+	})
+}
+
+func (s *userRepository) GetUserByToken(ctx context.Context, token string) (*domain.User, error) {
+	return s.find(func(user domain.User) bool {
+		return user.Token != nil && *user.Token == token
+	})
+}
+
+// save writes the user, keeping the emails and the tokens unique and the profile existing.
+func (s *userRepository) save(user *domain.User) error {
+	if _, ok := s.db.profiles[user.ProfileID]; !ok {
+		return pgerror.ErrForeignKeyViolated
+	}
+
+	for id, stored := range s.db.users {
+		if id == user.Id {
+			continue
+		}
+		if stored.Email == user.Email || (user.Token != nil && stored.Token != nil && *stored.Token == *user.Token) {
+			return pgerror.ErrDuplicatedKey
+		}
+	}
+
+	if user.Id == 0 {
+		user.Id = s.db.nextID()
+	}
+	if user.Source == "" {
+		user.Source = domain.UserSourceLocal
+	}
+
+	touch(&user.Base)
+	stored := *user
+	stored.Token = clonePointer(user.Token)
+	stored.Password = clonePointer(user.Password)
+	stored.Profile = nil
+	s.db.users[user.Id] = stored
+	return nil
+}
+
+// update writes the user when it is still stored, the updates of deleted rows change nothing.
+func (s *userRepository) update(user *domain.User) error {
+	if _, ok := s.db.users[user.Id]; !ok {
+		return nil
+	}
+
+	return s.save(user)
+}
+
+func (s *userRepository) CreateUser(ctx context.Context, datas *dto.UserInputDTO) (*domain.User, error) {
+	user := &domain.User{New: true}
+	if err := user.Bind(datas); err != nil {
+		return nil, err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if err := s.save(user); err != nil {
+		return nil, err
+	}
+
+	return s.db.user(user.Id)
+}
+
+func (s *userRepository) UpdateUser(ctx context.Context, user *domain.User, datas *dto.UserInputDTO) error {
+	if err := user.Bind(datas); err != nil {
+		return err
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return s.update(user)
+}
+
+func (s *userRepository) DeleteUser(ctx context.Context, user *domain.User) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for _, account := range s.db.accounts {
+		if account.UserID == user.Id {
+			return pgerror.ErrForeignKeyViolated
+		}
+	}
+
+	// The tokens and the password history are deleted in cascade.
+	for id, token := range s.db.personalTokens {
+		if token.UserID == user.Id {
+			delete(s.db.personalTokens, id)
+		}
+	}
+	for id, token := range s.db.userTokens {
+		if token.UserID == user.Id {
+			delete(s.db.userTokens, id)
+		}
+	}
+	delete(s.db.passwordHistory, user.Id)
+	delete(s.db.users, user.Id)
+	return nil
+}
+
+// passwordUsed reports whether the password matches the current hash of the user or one of the hashes kept in the history.
+func (s *userRepository) passwordUsed(user *domain.User, password string) bool {
+	if s.policy.History <= 0 {
+		return false
+	}
+	if user.Password != nil {
+		if used, _, _ := hasher.Verify(*user.Password, password); used {
+			return true
+		}
+	}
+
+	s.db.mu.Lock()
+	history := s.db.passwordHistory[user.Id]
+	s.db.mu.Unlock()
+
+	for _, item := range history {
+		if used, _, _ := hasher.Verify(item.Hash, password); used {
+			return true
+		}
+	}
+
+	return false
+}
+
+// savePassword writes the user keeping the replaced hash in the password history, and revokes the personal tokens
+// of the user.
+func (s *userRepository) savePassword(user *domain.User, previous *string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if err := s.update(user); err != nil {
+		return err
+	}
+
+	for id, token := range s.db.personalTokens {
+		if token.UserID == user.Id {
+			delete(s.db.personalTokens, id)
+		}
+	}
+
+	history := s.db.passwordHistory[user.Id]
+	if previous != nil && s.policy.History > 0 {
+		history = append(history, domain.PasswordHistory{Id: s.db.nextID(), Hash: *previous, UserID: user.Id})
+	}
+
+	history = history[len(history)-min(len(history), max(s.policy.History, 0)):]
+	s.db.passwordHistory[user.Id] = history
+	return nil
+}
+
+func (s *userRepository) ResetUser(ctx context.Context, user *domain.User) error {
+	previous := user.Password
+	user.Password = nil
+	user.Token = nil
+	user.New = true
+
+	return s.savePassword(user, previous)
+}
+
+// CheckPassword validates a new password of the user against the policy and the password history.
+func (s *userRepository) CheckPassword(ctx context.Context, user *domain.User, password string) error {
+	if err := s.policy.Validate(password); err != nil {
+		return err
+	}
+
+	if s.passwordUsed(user, password) {
+		return domain.ErrPasswordReused
+	}
+
+	return nil
+}
+
+func (s *userRepository) PasswordUser(ctx context.Context, user *domain.User, pass *dto.PasswordInputDTO) error {
+	if err := s.CheckPassword(ctx, user, *pass.Password); err != nil {
+		return err
+	}
+
+	hash, err := hasher.Hash(*pass.Password)
+	if err != nil {
+		return err
+	}
+
+	previous := user.Password
+	user.New = false
+	user.ChangePassword = false
+	user.Token = new(string)
+	*user.Token = uuid.New().String()
+	user.Password = &hash
+
+	return s.savePassword(user, previous)
+}
+
+// RehashUser stores the password hash upgraded by the ValidatePassword of the user.
+func (s *userRepository) RehashUser(ctx context.Context, user *domain.User) error {
+	if !user.Rehashed || user.Password == nil {
+		return nil
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if stored, ok := s.db.users[user.Id]; ok {
+		stored.Password = clonePointer(user.Password)
+		s.db.users[user.Id] = stored
+	}
+
+	user.Rehashed = false
+	return nil
+}
+
+// ActivateUser enables the login of users without password, like the ones authenticated by an identity provider.
+func (s *userRepository) ActivateUser(ctx context.Context, user *domain.User) error {
+	if user.Token == nil {
+		user.Token = new(string)
+		*user.Token = uuid.New().String()
+	}
+	user.New = false
+
+	if user.Source == "" {
+		user.Source = domain.UserSourceLocal
+	}
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.users[user.Id]
+	if !ok {
+		return nil
+	}
+
+	stored.New, stored.Token, stored.Source = user.New, clonePointer(user.Token), user.Source
+	s.db.users[user.Id] = stored
+	return nil
+}
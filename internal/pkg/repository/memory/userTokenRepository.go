@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/pkg/mailer"
+	"gorm.io/gorm"
+)
+
+// The emailed links point to the appURL, the invitations and the password resets last inviteLife and resetLife.
+// Unlike the database repository, the tokens are random strings instead of signed JWTs.
+func NewUserTokenRepository(db *DB, userRepository domain.UserRepository, sender mailer.Sender, appURL string, inviteLife, resetLife time.Duration) domain.UserTokenRepository {
+	return &userTokenRepository{
+		db:             db,
+		userRepository: userRepository,
+		sender:         sender,
+		appURL:         strings.TrimSuffix(appURL, "/"),
+		inviteLife:     inviteLife,
+		resetLife:      resetLife,
+	}
+}
+
+type userTokenRepository struct {
+	db             *DB
+	userRepository domain.UserRepository
+	sender         mailer.Sender
+	appURL         string
+	inviteLife     time.Duration
+	resetLife      time.Duration
+}
+
+func hashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// createUserToken creates a token for the purpose, replacing the unused tokens of the user with the same purpose.
+func (s *userTokenRepository) createUserToken(user *domain.User, purpose string, life time.Duration) (string, time.Time, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", time.Time{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(random)
+
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for id, stored := range s.db.userTokens {
+		if stored.UserID == user.Id && stored.Purpose == purpose && stored.UsedAt == nil {
+			delete(s.db.userTokens, id)
+		}
+	}
+
+	userToken := domain.UserToken{
+		Purpose:   purpose,
+		Hash:      hashUserToken(token),
+		ExpiresAt: time.Now().Add(life),
+		UserID:    user.Id,
+	}
+	userToken.Id = s.db.nextID()
+	touch(&userToken.Base)
+	s.db.userTokens[userToken.Id] = userToken
+
+	return token, userToken.ExpiresAt, nil
+}
+
+// getUserToken returns the unused token with its user.
+func (s *userTokenRepository) getUserToken(token, purpose string) (*domain.UserToken, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	hash, now := hashUserToken(token), time.Now()
+	for _, userToken := range s.db.userTokens {
+		if userToken.Hash == hash && userToken.Purpose == purpose && userToken.UsedAt == nil && userToken.ExpiresAt.After(now) {
+			user, err := s.db.user(userToken.UserID)
+			if err != nil {
+				return nil, err
+			}
+
+			userToken.User = user
+			return &userToken, nil
+		}
+	}
+
+	return nil, domain.ErrInvalidUserToken
+}
+
+// markUserToken sets the time the token was used, failing when another request used it first. A nil time releases
+// the token.
+func (s *userTokenRepository) markUserToken(userToken *domain.UserToken, usedAt *time.Time) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	stored, ok := s.db.userTokens[userToken.Id]
+	if !ok || (usedAt != nil && stored.UsedAt != nil) {
+		return domain.ErrInvalidUserToken
+	}
+
+	stored.UsedAt = usedAt
+	s.db.userTokens[userToken.Id] = stored
+	return nil
+}
+
+// useUserToken marks the token as used and sets the password of its user, a failed password write releases the token
+// like the rollback of the database transaction.
+func (s *userTokenRepository) useUserToken(ctx context.Context, userToken *domain.UserToken, pass *dto.PasswordInputDTO) error {
+	now := time.Now()
+	if err := s.markUserToken(userToken, &now); err != nil {
+		return err
+	}
+
+	if err := s.userRepository.PasswordUser(ctx, userToken.User, pass); err != nil {
+		_ = s.markUserToken(userToken, nil)
+		return err
+	}
+
+	return nil
+}
+
+// sendUserToken emails the link with a new token, using the localized subject and body messages.
+func (s *userTokenRepository) sendUserToken(ctx context.Context, user *domain.User, purpose string, life time.Duration, translation *i18n.Translation, subject, body, path string) error {
+	token, expires, err := s.createUserToken(user, purpose, life)
+	if err != nil {
+		return err
+	}
+
+	return s.sender.Send(ctx, &mailer.Message{
+		To:      []string{user.Email},
+		Subject: translation.Message(subject, nil),
+		Text: translation.Message(body, map[string]string{
+			"Name":    user.Name,
+			"Link":    s.appURL + path + "?" + url.Values{"email": {user.Email}, "token": {token}}.Encode(),
+			"Expires": expires.Format(time.DateTime),
+		}),
+	})
+}
+
+func (s *userTokenRepository) InviteUser(ctx context.Context, user *domain.User, translation *i18n.Translation) error {
+	return s.sendUserToken(ctx, user, domain.UserTokenInvite, s.inviteLife, translation, "MailInviteSubject", "MailInviteBody", "/invite")
+}
+
+// AcceptInvite sets the password of the invited user, consuming the invitation.
+func (s *userTokenRepository) AcceptInvite(ctx context.Context, token, email string, pass *dto.PasswordInputDTO) (*domain.User, error) {
+	userToken, err := s.getUserToken(token, domain.UserTokenInvite)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(userToken.User.Email, email) {
+		return nil, domain.ErrInvalidUserToken
+	}
+	if !userToken.User.New {
+		return nil, domain.ErrUserHasPassword
+	}
+
+	if err := s.userRepository.CheckPassword(ctx, userToken.User, *pass.Password); err != nil {
+		return nil, err
+	}
+
+	if err := s.useUserToken(ctx, userToken, pass); err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetUserByID(ctx, userToken.UserID)
+}
+
+// ForgotPassword emails a reset link to the enabled local users with password, doing nothing for the others.
+func (s *userTokenRepository) ForgotPassword(ctx context.Context, email string, translation *i18n.Translation) error {
+	user, err := s.userRepository.GetUserByMail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !user.Status || user.New || user.Password == nil || user.Source != domain.UserSourceLocal {
+		return nil
+	}
+
+	return s.sendUserToken(ctx, user, domain.UserTokenReset, s.resetLife, translation, "MailResetSubject", "MailResetBody", "/reset")
+}
+
+// ResetPassword replaces the password of the user, consuming the reset token.
+func (s *userTokenRepository) ResetPassword(ctx context.Context, token string, pass *dto.PasswordInputDTO) (*domain.User, error) {
+	userToken, err := s.getUserToken(token, domain.UserTokenReset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepository.CheckPassword(ctx, userToken.User, *pass.Password); err != nil {
+		return nil, err
+	}
+
+	if err := s.useUserToken(ctx, userToken, pass); err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetUserByID(ctx, userToken.UserID)
+}
@@ -22,7 +22,7 @@ type phoneRepository struct {
 
 func (s *phoneRepository) applyFilter(ctx context.Context, filter *filter.Filter) *gorm.DB {
 	postgres := s.postgres.WithContext(ctx)
-	postgres = filter.ApplySearchLike(postgres, "name")
+	postgres = filter.ApplySearchLike(postgres, "number")
 	postgres = filter.ApplyOrder(postgres)
 
 	return postgres
@@ -35,7 +35,7 @@ func (s *phoneRepository) countPhones(postgres *gorm.DB) (int64, error) {
 
 func (s *phoneRepository) listPhones(postgres *gorm.DB) (*[]domain.Phone, error) {
 	phones := &[]domain.Phone{}
-	return phones, postgres.Preload(postgre.Operator).Preload(postgre.Accounts).Find(phones).Error
+	return phones, postgres.Preload(postgre.Operator).Find(phones).Error
 }
 
 func (s *phoneRepository) GetPhonesOutputDTO(ctx context.Context, filter *filter.Filter) (*dto.ItemsOutputDTO, error) {
@@ -59,7 +59,7 @@ func (s *phoneRepository) GetPhonesOutputDTO(ctx context.Context, filter *filter
 
 func (s *phoneRepository) GetPhoneByID(ctx context.Context, phoneID uint) (*domain.Phone, error) {
 	phone := &domain.Phone{}
-	return phone, s.postgres.WithContext(ctx).Preload(postgre.Operator).Preload(postgre.Accounts).First(phone, phoneID).Error
+	return phone, s.postgres.WithContext(ctx).Preload(postgre.Operator).First(phone, phoneID).Error
 }
 
 func (s *phoneRepository) CreatePhone(ctx context.Context, datas *dto.PhoneInputDTO) (*domain.Phone, error) {
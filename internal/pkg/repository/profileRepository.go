@@ -62,6 +62,11 @@ func (s *profileRepository) GetProfileByID(ctx context.Context, profileID uint)
 	return profile, s.postgres.WithContext(ctx).Preload(clause.Associations).First(profile, profileID).Error
 }
 
+func (s *profileRepository) GetProfileByName(ctx context.Context, name string) (*domain.Profile, error) {
+	profile := &domain.Profile{Name: name}
+	return profile, s.postgres.WithContext(ctx).Preload(clause.Associations).Where(profile).First(profile).Error
+}
+
 func (s *profileRepository) CreateProfile(ctx context.Context, datas *dto.ProfileInputDTO) (*domain.Profile, error) {
 	profile := &domain.Profile{}
 	if err := profile.Bind(datas); err != nil {
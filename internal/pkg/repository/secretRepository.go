@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/postgre"
+	"github.com/raulaguila/go-pass/pkg/secretref"
+	"gorm.io/gorm"
+)
+
+func NewSecretRepository(postgres *gorm.DB, auditRepository domain.AuditRepository) domain.SecretRepository {
+	return &secretRepository{
+		postgres:        postgres,
+		auditRepository: auditRepository,
+	}
+}
+
+type secretRepository struct {
+	postgres        *gorm.DB
+	auditRepository domain.AuditRepository
+}
+
+// getAccountByReference returns the account of the reference, the site names differing only by case can match more
+// than one account.
+func (s *secretRepository) getAccountByReference(ctx context.Context, ref *secretref.Reference) (*domain.Account, error) {
+	accounts := []domain.Account{}
+	err := s.postgres.WithContext(ctx).Preload(postgre.Site).
+		Joins(fmt.Sprintf("JOIN %v ON %v.id = %v.site_id", domain.SiteTableName, domain.SiteTableName, domain.AccountTableName)).
+		Where(fmt.Sprintf("LOWER(%v.name) = LOWER(?) AND %v.username = ?", domain.SiteTableName, domain.AccountTableName), ref.Site, ref.Account).
+		Limit(2).Find(&accounts).Error
+	switch {
+	case err != nil:
+		return nil, err
+	case len(accounts) == 0:
+		return nil, gorm.ErrRecordNotFound
+	case len(accounts) > 1:
+		return nil, domain.ErrSecretAmbiguous
+	}
+
+	return &accounts[0], nil
+}
+
+func (s *secretRepository) audit(ctx context.Context, requester *domain.SecretRequester, ref string, err error) error {
+	audit := &domain.AuditLog{
+		Action:    domain.AuditSecretResolve,
+		Target:    ref,
+		Success:   err == nil,
+		UserID:    requester.User.Id,
+		IP:        requester.IP,
+		RequestID: requester.RequestID,
+	}
+
+	if err != nil {
+		audit.Detail = err.Error()
+	}
+
+	if requester.Token != nil {
+		audit.TokenID = &requester.Token.Id
+	}
+
+	return s.auditRepository.CreateAuditLog(ctx, audit)
+}
+
+func (s *secretRepository) resolve(ctx context.Context, item dto.SecretRefInputDTO, requester *domain.SecretRequester) (*secretref.Secret, error) {
+	ref, err := secretref.Parse(item.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.getAccountByReference(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if requester.Token != nil && !requester.Token.AllowsSite(account.SiteID) {
+		return nil, domain.ErrSecretForbidden
+	}
+
+	secret := &secretref.Secret{Name: item.Name, Ref: ref.String()}
+	if secret.Name == "" {
+		secret.Name = ref.EnvName()
+	}
+
+	switch ref.Field {
+	case secretref.FieldUsername:
+		secret.Value = account.Username
+	case secretref.FieldURL:
+		secret.Value = account.Site.URL
+	default:
+		secret.Value = account.DecodePass()
+	}
+
+	return secret, nil
+}
+
+// ResolveSecrets resolves every reference or none. The resolutions are audited once the outcome is known, a failure
+// is recorded on every reference attempted, as none of their values is returned.
+func (s *secretRepository) ResolveSecrets(ctx context.Context, datas *dto.SecretResolveInputDTO, requester *domain.SecretRequester) ([]secretref.Secret, error) {
+	secrets := make([]secretref.Secret, 0, len(datas.Secrets))
+	attempted := datas.Secrets
+
+	var err error
+	for i, item := range datas.Secrets {
+		secret, resolveErr := s.resolve(ctx, item, requester)
+		if resolveErr != nil {
+			err = &domain.SecretError{Ref: item.Ref, Err: resolveErr}
+			attempted = datas.Secrets[:i+1]
+			break
+		}
+		secrets = append(secrets, *secret)
+	}
+
+	for _, item := range attempted {
+		if auditErr := s.audit(ctx, requester, item.Ref, err); auditErr != nil {
+			return nil, auditErr
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
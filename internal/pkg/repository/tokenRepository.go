@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/postgre"
+	"gorm.io/gorm"
+)
+
+func NewPersonalTokenRepository(postgres *gorm.DB) domain.PersonalTokenRepository {
+	return &personalTokenRepository{
+		postgres: postgres,
+	}
+}
+
+type personalTokenRepository struct {
+	postgres *gorm.DB
+}
+
+func (s *personalTokenRepository) GetPersonalTokensOutputDTO(ctx context.Context, userID uint) (*dto.ItemsOutputDTO, error) {
+	tokens := &[]domain.PersonalToken{}
+	if err := s.postgres.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Find(tokens).Error; err != nil {
+		return nil, err
+	}
+
+	return &dto.ItemsOutputDTO{
+		Items: tokens,
+		Count: int64(len(*tokens)),
+	}, nil
+}
+
+func (s *personalTokenRepository) GetPersonalTokenByID(ctx context.Context, tokenID, userID uint) (*domain.PersonalToken, error) {
+	token := &domain.PersonalToken{}
+	return token, s.postgres.WithContext(ctx).Where("user_id = ?", userID).First(token, tokenID).Error
+}
+
+func (s *personalTokenRepository) CreatePersonalToken(ctx context.Context, datas *dto.PersonalTokenInputDTO, userID uint) (*domain.PersonalTokenResponse, error) {
+	token := &domain.PersonalToken{UserID: userID}
+	if err := token.Bind(datas); err != nil {
+		return nil, err
+	}
+
+	secret, err := token.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.postgres.WithContext(ctx).Create(token).Error; err != nil {
+		return nil, err
+	}
+
+	return &domain.PersonalTokenResponse{
+		PersonalToken: token,
+		Token:         secret,
+	}, nil
+}
+
+func (s *personalTokenRepository) DeletePersonalToken(ctx context.Context, token *domain.PersonalToken) error {
+	return s.postgres.WithContext(ctx).Delete(token).Error
+}
+
+func (s *personalTokenRepository) Authenticate(ctx context.Context, secret, ip string) (*domain.PersonalToken, error) {
+	token := &domain.PersonalToken{}
+	err := s.postgres.WithContext(ctx).Preload(postgre.UserProfile).Where("hash = ?", domain.HashPersonalToken(secret)).First(token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Expired() {
+		return nil, domain.ErrPersonalTokenExpired
+	}
+
+	if !token.AllowsIP(ip) {
+		return nil, domain.ErrInvalidIpAssociation
+	}
+
+	if token.User == nil || !token.User.Status {
+		return nil, domain.ErrDisabledUser
+	}
+
+	now := time.Now()
+	token.LastUsedAt = &now
+	return token, s.postgres.WithContext(ctx).Model(token).UpdateColumn("last_used_at", now).Error
+}
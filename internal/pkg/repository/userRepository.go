@@ -3,24 +3,28 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/raulaguila/go-pass/internal/pkg/domain"
 	"github.com/raulaguila/go-pass/internal/pkg/dto"
 	"github.com/raulaguila/go-pass/internal/pkg/postgre"
 	"github.com/raulaguila/go-pass/pkg/filter"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/raulaguila/go-pass/pkg/hasher"
+	"github.com/raulaguila/go-pass/pkg/passpolicy"
 	"gorm.io/gorm"
 )
 
-func NewUserRepository(postgres *gorm.DB) domain.UserRepository {
+func NewUserRepository(postgres *gorm.DB, policy *passpolicy.Policy) domain.UserRepository {
 	return &userRepository{
 		postgres: postgres,
+		policy:   policy,
 	}
 }
 
 type userRepository struct {
 	postgres *gorm.DB
+	policy   *passpolicy.Policy
 }
 
 func (s *userRepository) applyFilter(ctx context.Context, filter *filter.UserFilter) *gorm.DB {
@@ -28,8 +32,15 @@ func (s *userRepository) applyFilter(ctx context.Context, filter *filter.UserFil
 	if filter.ProfileID != 0 {
 		postgres = postgres.Where(domain.UserTableName+".profile_id = ?", filter.ProfileID)
 	}
+	if filter.Source != "" {
+		postgres = postgres.Where(domain.UserTableName+".source = ?", filter.Source)
+	}
 	postgres = postgres.Joins(fmt.Sprintf("JOIN %v ON %v.id = %v.profile_id", domain.ProfileTableName, domain.ProfileTableName, domain.UserTableName))
 	postgres = filter.ApplySearchLike(postgres, domain.UserTableName+".name", domain.UserTableName+".mail", domain.ProfileTableName+".name")
+	// The profiles join shares column names with the users, like the default sort 'updated_at'.
+	if !strings.Contains(filter.Sort, ".") {
+		filter.Sort = domain.UserTableName + "." + filter.Sort
+	}
 	postgres = filter.ApplyOrder(postgres)
 
 	return postgres
@@ -104,25 +115,129 @@ func (s *userRepository) DeleteUser(ctx context.Context, user *domain.User) erro
 	return s.postgres.WithContext(ctx).Delete(user).Error
 }
 
+// passwordUsed reports whether the password matches the current hash of the user or one of the hashes kept in the history.
+func (s *userRepository) passwordUsed(ctx context.Context, user *domain.User, password string) (bool, error) {
+	if s.policy.History <= 0 {
+		return false, nil
+	}
+	if user.Password != nil {
+		if used, _, _ := hasher.Verify(*user.Password, password); used {
+			return true, nil
+		}
+	}
+
+	history := []domain.PasswordHistory{}
+	if err := s.postgres.WithContext(ctx).Where("user_id = ?", user.Id).Order("id desc").Limit(s.policy.History).Find(&history).Error; err != nil {
+		return false, err
+	}
+
+	for _, item := range history {
+		if used, _, _ := hasher.Verify(item.Hash, password); used {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// savePassword writes the user keeping the replaced hash in the password history, and revokes the personal tokens
+// of the user.
+func (s *userRepository) savePassword(ctx context.Context, user *domain.User, previous *string) error {
+	return s.postgres.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if previous != nil && s.policy.History > 0 {
+			if err := tx.Create(&domain.PasswordHistory{UserID: user.Id, Hash: *previous}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(user).Updates(user.ToMap()).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.Id).Delete(&domain.PersonalToken{}).Error; err != nil {
+			return err
+		}
+
+		pruned := tx.Where("user_id = ?", user.Id)
+		if s.policy.History > 0 {
+			kept := tx.Model(&domain.PasswordHistory{}).Select("id").Where("user_id = ?", user.Id).Order("id desc").Limit(s.policy.History)
+			pruned = pruned.Where("id NOT IN (?)", kept)
+		}
+		return pruned.Delete(&domain.PasswordHistory{}).Error
+	})
+}
+
 func (s *userRepository) ResetUser(ctx context.Context, user *domain.User) error {
+	previous := user.Password
 	user.Password = nil
 	user.Token = nil
 	user.New = true
 
-	return s.UpdateUser(ctx, user, &dto.UserInputDTO{})
+	return s.savePassword(ctx, user, previous)
+}
+
+// CheckPassword validates a new password of the user against the policy and the password history.
+func (s *userRepository) CheckPassword(ctx context.Context, user *domain.User, password string) error {
+	if err := s.policy.Validate(password); err != nil {
+		return err
+	}
+
+	used, err := s.passwordUsed(ctx, user, password)
+	if err != nil {
+		return err
+	}
+	if used {
+		return domain.ErrPasswordReused
+	}
+
+	return nil
 }
 
 func (s *userRepository) PasswordUser(ctx context.Context, user *domain.User, pass *dto.PasswordInputDTO) error {
+	if err := s.CheckPassword(ctx, user, *pass.Password); err != nil {
+		return err
+	}
+
+	hash, err := hasher.Hash(*pass.Password)
+	if err != nil {
+		return err
+	}
+
+	previous := user.Password
 	user.New = false
+	user.ChangePassword = false
 	user.Token = new(string)
 	*user.Token = uuid.New().String()
+	user.Password = &hash
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(*pass.Password), bcrypt.DefaultCost)
-	if err != nil {
+	return s.savePassword(ctx, user, previous)
+}
+
+// RehashUser stores the password hash upgraded by the ValidatePassword of the user.
+func (s *userRepository) RehashUser(ctx context.Context, user *domain.User) error {
+	if !user.Rehashed || user.Password == nil {
+		return nil
+	}
+
+	if err := s.postgres.WithContext(ctx).Model(user).Update("password", *user.Password).Error; err != nil {
 		return err
 	}
-	user.Password = new(string)
-	*user.Password = string(hash)
 
-	return s.UpdateUser(ctx, user, &dto.UserInputDTO{})
+	user.Rehashed = false
+	return nil
+}
+
+// ActivateUser enables the login of users without password, like the ones authenticated by an identity provider.
+func (s *userRepository) ActivateUser(ctx context.Context, user *domain.User) error {
+	if user.Token == nil {
+		user.Token = new(string)
+		*user.Token = uuid.New().String()
+	}
+	user.New = false
+
+	if user.Source == "" {
+		user.Source = domain.UserSourceLocal
+	}
+
+	return s.postgres.WithContext(ctx).Model(user).Updates(map[string]interface{}{"new": user.New, "token": *user.Token, "source": user.Source}).Error
 }
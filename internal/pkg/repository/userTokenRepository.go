@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	"github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/internal/pkg/postgre"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/raulaguila/go-pass/pkg/mailer"
+	"gorm.io/gorm"
+)
+
+// The emailed links point to the appURL, the invitations and the password resets last inviteLife and resetLife. The
+// users are read and written by the repositories of userRepository, which also opens one inside the transaction using
+// a token.
+func NewUserTokenRepository(postgres *gorm.DB, userRepository func(*gorm.DB) domain.UserRepository, keys *keyring.Keyring, sender mailer.Sender, appURL string, inviteLife, resetLife time.Duration) domain.UserTokenRepository {
+	return &userTokenRepository{
+		postgres:         postgres,
+		userRepository:   userRepository(postgres),
+		txUserRepository: userRepository,
+		keys:             keys,
+		sender:           sender,
+		appURL:           strings.TrimSuffix(appURL, "/"),
+		inviteLife:       inviteLife,
+		resetLife:        resetLife,
+	}
+}
+
+type userTokenRepository struct {
+	postgres         *gorm.DB
+	userRepository   domain.UserRepository
+	txUserRepository func(*gorm.DB) domain.UserRepository
+	keys             *keyring.Keyring
+	sender           mailer.Sender
+	appURL           string
+	inviteLife       time.Duration
+	resetLife        time.Duration
+}
+
+func hashUserToken(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
+
+// createUserToken signs a token for the purpose, replacing the unused tokens of the user with the same purpose.
+func (s *userTokenRepository) createUserToken(ctx context.Context, user *domain.User, purpose string, life time.Duration) (string, time.Time, error) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", time.Time{}, err
+	}
+	jti := base64.RawURLEncoding.EncodeToString(id)
+
+	now := time.Now()
+	userToken := &domain.UserToken{
+		Purpose:   purpose,
+		Hash:      hashUserToken(jti),
+		ExpiresAt: now.Add(life),
+		UserID:    user.Id,
+	}
+
+	err := s.postgres.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.Id, purpose).Delete(&domain.UserToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(userToken).Error
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	token, err := s.keys.Sign(jwt.MapClaims{
+		"aud": purpose,
+		"jti": jti,
+		"iat": now.Unix(),
+		"exp": userToken.ExpiresAt.Unix(),
+	})
+
+	return token, userToken.ExpiresAt, err
+}
+
+// getUserToken validates the signature and returns the unused token with its user.
+func (s *userTokenRepository) getUserToken(ctx context.Context, token, purpose string) (*domain.UserToken, error) {
+	claims := &jwt.RegisteredClaims{}
+	if _, err := s.keys.Parse(token, claims, jwt.WithAudience(purpose), jwt.WithExpirationRequired()); err != nil || claims.ID == "" {
+		return nil, domain.ErrInvalidUserToken
+	}
+
+	userToken := &domain.UserToken{}
+	err := s.postgres.WithContext(ctx).Preload(postgre.UserProfile).
+		Where("hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", hashUserToken(claims.ID), purpose, time.Now()).
+		First(userToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrInvalidUserToken
+	}
+
+	return userToken, err
+}
+
+// useUserToken marks the token as used and sets the password of its user in one transaction, failing when another
+// request used the token first. A failed password write keeps the token unused.
+func (s *userTokenRepository) useUserToken(ctx context.Context, userToken *domain.UserToken, pass *dto.PasswordInputDTO) error {
+	return s.postgres.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(userToken).Where("used_at IS NULL").Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return domain.ErrInvalidUserToken
+		}
+
+		return s.txUserRepository(tx).PasswordUser(ctx, userToken.User, pass)
+	})
+}
+
+// sendUserToken emails the link with a new token, using the localized subject and body messages.
+func (s *userTokenRepository) sendUserToken(ctx context.Context, user *domain.User, purpose string, life time.Duration, translation *i18n.Translation, subject, body, path string) error {
+	token, expires, err := s.createUserToken(ctx, user, purpose, life)
+	if err != nil {
+		return err
+	}
+
+	return s.sender.Send(ctx, &mailer.Message{
+		To:      []string{user.Email},
+		Subject: translation.Message(subject, nil),
+		Text: translation.Message(body, map[string]string{
+			"Name":    user.Name,
+			"Link":    s.appURL + path + "?" + url.Values{"email": {user.Email}, "token": {token}}.Encode(),
+			"Expires": expires.Format(time.DateTime),
+		}),
+	})
+}
+
+func (s *userTokenRepository) InviteUser(ctx context.Context, user *domain.User, translation *i18n.Translation) error {
+	return s.sendUserToken(ctx, user, domain.UserTokenInvite, s.inviteLife, translation, "MailInviteSubject", "MailInviteBody", "/invite")
+}
+
+// AcceptInvite sets the password of the invited user, consuming the invitation.
+func (s *userTokenRepository) AcceptInvite(ctx context.Context, token, email string, pass *dto.PasswordInputDTO) (*domain.User, error) {
+	userToken, err := s.getUserToken(ctx, token, domain.UserTokenInvite)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(userToken.User.Email, email) {
+		return nil, domain.ErrInvalidUserToken
+	}
+	if !userToken.User.New {
+		return nil, domain.ErrUserHasPassword
+	}
+
+	// Checked before using the token, so a refused password does not waste the link.
+	if err := s.userRepository.CheckPassword(ctx, userToken.User, *pass.Password); err != nil {
+		return nil, err
+	}
+
+	if err := s.useUserToken(ctx, userToken, pass); err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetUserByID(ctx, userToken.UserID)
+}
+
+// ForgotPassword emails a reset link to the enabled local users with password, doing nothing for the others.
+func (s *userTokenRepository) ForgotPassword(ctx context.Context, email string, translation *i18n.Translation) error {
+	user, err := s.userRepository.GetUserByMail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if !user.Status || user.New || user.Password == nil || user.Source != domain.UserSourceLocal {
+		return nil
+	}
+
+	return s.sendUserToken(ctx, user, domain.UserTokenReset, s.resetLife, translation, "MailResetSubject", "MailResetBody", "/reset")
+}
+
+// ResetPassword replaces the password of the user, consuming the reset token and ending the user sessions.
+func (s *userTokenRepository) ResetPassword(ctx context.Context, token string, pass *dto.PasswordInputDTO) (*domain.User, error) {
+	userToken, err := s.getUserToken(ctx, token, domain.UserTokenReset)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepository.CheckPassword(ctx, userToken.User, *pass.Password); err != nil {
+		return nil, err
+	}
+
+	if err := s.useUserToken(ctx, userToken, pass); err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetUserByID(ctx, userToken.UserID)
+}
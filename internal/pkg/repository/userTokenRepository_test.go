@@ -0,0 +1,106 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/raulaguila/go-pass/internal/pkg/domain"
+	"github.com/raulaguila/go-pass/internal/pkg/dto"
+	myi18n "github.com/raulaguila/go-pass/internal/pkg/i18n"
+	"github.com/raulaguila/go-pass/internal/pkg/repository"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/raulaguila/go-pass/pkg/mailer"
+	"github.com/raulaguila/go-pass/pkg/passpolicy"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+)
+
+var mailToken *regexp.Regexp = regexp.MustCompile(`token=([^&\s]+)`)
+
+// outbox keeps the sent messages.
+type outbox struct {
+	messages []*mailer.Message
+}
+
+func (s *outbox) Send(_ context.Context, message *mailer.Message) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+// token returns the token of the link in the last message.
+func (s *outbox) token(t *testing.T) string {
+	if !assert.NotEmpty(t, s.messages) {
+		t.FailNow()
+	}
+
+	matches := mailToken.FindStringSubmatch(s.messages[len(s.messages)-1].Text)
+	if !assert.Len(t, matches, 2) {
+		t.FailNow()
+	}
+
+	token, err := url.QueryUnescape(matches[1])
+	assert.Nil(t, err)
+	return token
+}
+
+func newTranslation(t *testing.T) *myi18n.Translation {
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
+	if _, err := bundle.LoadMessageFile("../../../configs/i18n/active.en.toml"); !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	return myi18n.NewTranslation("en", i18n.NewLocalizer(bundle, "en"))
+}
+
+// go test -run TestResetPasswordRollback
+func TestResetPasswordRollback(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	users := repository.NewUserRepository(db, passpolicy.Default())
+
+	key, err := keyring.Generate("EdDSA")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	sent := &outbox{}
+	userTokens := repository.NewUserTokenRepository(db, func(tx *gorm.DB) domain.UserRepository { return repository.NewUserRepository(tx, passpolicy.Default()) }, keyring.New(key), sent, "http://localhost", 0, time.Hour)
+
+	profile, err := repository.NewProfileRepository(db).GetProfileByName(ctx, "USER")
+	assert.Nil(t, err)
+	user, err := users.CreateUser(ctx, &dto.UserInputDTO{Name: ptr("Erin Green"), Email: ptr("erin@example.com"), Status: ptr(true), ProfileID: &profile.Id})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Nil(t, users.PasswordUser(ctx, user, &dto.PasswordInputDTO{Password: ptr("Erin#Green123"), PasswordConfirm: ptr("Erin#Green123")}))
+
+	assert.Nil(t, userTokens.ForgotPassword(ctx, user.Email, newTranslation(t)))
+	token := sent.token(t)
+	pass := &dto.PasswordInputDTO{Password: ptr("Erin#Reset456"), PasswordConfirm: ptr("Erin#Reset456")}
+
+	// The password write fails after the token was marked as used.
+	failure := errors.New("user update failed")
+	assert.Nil(t, db.Callback().Update().Before("gorm:update").Register("test:fail_user", func(tx *gorm.DB) {
+		if tx.Statement.Table == domain.UserTableName {
+			_ = tx.AddError(failure)
+		}
+	}))
+	_, err = userTokens.ResetPassword(ctx, token, pass)
+	assert.ErrorIs(t, err, failure)
+	assert.Nil(t, db.Callback().Update().Remove("test:fail_user"))
+
+	reset, err := userTokens.ResetPassword(ctx, token, pass)
+	if assert.Nil(t, err) {
+		assert.True(t, reset.ValidatePassword("Erin#Reset456"))
+	}
+
+	_, err = userTokens.ResetPassword(ctx, token, pass)
+	assert.ErrorIs(t, err, domain.ErrInvalidUserToken)
+}
@@ -0,0 +1,131 @@
+package bruteforce
+
+import (
+	"sync"
+	"time"
+)
+
+// Maximum of tracked keys before stale entries are pruned.
+const pruneThreshold int = 10000
+
+type (
+	Config struct {
+		MaxAttempts int           // Failures allowed before the key is locked
+		BaseDelay   time.Duration // Backoff after the first failure, doubled on every new failure
+		MaxDelay    time.Duration // Upper bound for the backoff
+		LockoutTime time.Duration // Lock duration after MaxAttempts failures, also the failures memory window
+		OnLockout   func(key string, failures int, until time.Time)
+	}
+
+	Guard struct {
+		mu      sync.Mutex
+		config  Config
+		entries map[string]*entry
+		now     func() time.Time
+	}
+
+	entry struct {
+		failures     int
+		lastFailure  time.Time
+		blockedUntil time.Time
+	}
+)
+
+func New(config Config) *Guard {
+	return &Guard{
+		config:  config,
+		entries: map[string]*entry{},
+		now:     time.Now,
+	}
+}
+
+func (s *Guard) stale(e *entry, now time.Time) bool {
+	return now.After(e.blockedUntil) && now.Sub(e.lastFailure) > s.config.LockoutTime
+}
+
+func (s *Guard) prune(now time.Time) {
+	for key, e := range s.entries {
+		if s.stale(e, now) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func (s *Guard) delay(failures int) time.Duration {
+	if failures >= s.config.MaxAttempts {
+		return s.config.LockoutTime
+	}
+
+	delay := s.config.BaseDelay
+	for i := 1; i < failures && delay < s.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxDelay {
+		delay = s.config.MaxDelay
+	}
+
+	return delay
+}
+
+// Check returns the remaining time until the first blocked key is released.
+func (s *Guard) Check(keys ...string) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var wait time.Duration
+	for _, key := range keys {
+		e, ok := s.entries[key]
+		if !ok {
+			continue
+		}
+
+		if s.stale(e, now) {
+			delete(s.entries, key)
+			continue
+		}
+
+		if remaining := e.blockedUntil.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+
+	return wait, wait > 0
+}
+
+// Fail registers a failed attempt for every key, applying backoff or lockout.
+func (s *Guard) Fail(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if len(s.entries) > pruneThreshold {
+		s.prune(now)
+	}
+
+	for _, key := range keys {
+		e, ok := s.entries[key]
+		if !ok || s.stale(e, now) {
+			e = &entry{}
+			s.entries[key] = e
+		}
+
+		e.failures++
+		e.lastFailure = now
+		e.blockedUntil = now.Add(s.delay(e.failures))
+
+		if e.failures == s.config.MaxAttempts && s.config.OnLockout != nil {
+			go s.config.OnLockout(key, e.failures, e.blockedUntil)
+		}
+	}
+}
+
+// Reset forgets the failed attempts of every key.
+func (s *Guard) Reset(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+}
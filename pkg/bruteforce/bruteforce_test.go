@@ -0,0 +1,95 @@
+package bruteforce
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGuard(now *time.Time, onLockout func(string, int, time.Time)) *Guard {
+	guard := New(Config{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    10 * time.Second,
+		LockoutTime: time.Minute,
+		OnLockout:   onLockout,
+	})
+	guard.now = func() time.Time { return *now }
+	return guard
+}
+
+// go test -run TestGuardWithoutFailures
+func TestGuardWithoutFailures(t *testing.T) {
+	now := time.Now()
+	guard := newTestGuard(&now, nil)
+
+	wait, blocked := guard.Check("mail:user@email.com", "ip:127.0.0.1")
+	assert.False(t, blocked)
+	assert.Equal(t, time.Duration(0), wait)
+}
+
+// go test -run TestGuardBackoff
+func TestGuardBackoff(t *testing.T) {
+	now := time.Now()
+	guard := newTestGuard(&now, nil)
+
+	guard.Fail("key")
+	wait, blocked := guard.Check("key")
+	assert.True(t, blocked)
+	assert.Equal(t, time.Second, wait)
+
+	now = now.Add(time.Second + time.Millisecond)
+	_, blocked = guard.Check("key")
+	assert.False(t, blocked)
+
+	guard.Fail("key")
+	wait, blocked = guard.Check("key")
+	assert.True(t, blocked)
+	assert.Equal(t, 2*time.Second, wait)
+}
+
+// go test -run TestGuardLockout
+func TestGuardLockout(t *testing.T) {
+	now := time.Now()
+	locked := make(chan string, 1)
+	guard := newTestGuard(&now, func(key string, failures int, until time.Time) {
+		locked <- key
+	})
+
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Hour / 120)
+		guard.Fail("key")
+	}
+
+	wait, blocked := guard.Check("key", "other")
+	assert.True(t, blocked)
+	assert.Equal(t, time.Minute, wait)
+
+	select {
+	case key := <-locked:
+		assert.Equal(t, "key", key)
+	case <-time.After(time.Second):
+		t.Error("lockout hook was not called")
+	}
+
+	now = now.Add(time.Minute + time.Millisecond)
+	_, blocked = guard.Check("key")
+	assert.False(t, blocked)
+	assert.Empty(t, guard.entries)
+}
+
+// go test -run TestGuardReset
+func TestGuardReset(t *testing.T) {
+	now := time.Now()
+	guard := newTestGuard(&now, nil)
+
+	guard.Fail("mail", "ip")
+	guard.Reset("mail")
+
+	_, blocked := guard.Check("mail")
+	assert.False(t, blocked)
+
+	_, blocked = guard.Check("ip")
+	assert.True(t, blocked)
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) Accounts(ctx context.Context, filter *AccountFilter) (*List[Account], error) {
+	out := &List[Account]{}
+	return out, c.do(ctx, http.MethodGet, "/account", filter.query(), nil, out)
+}
+
+func (c *Client) Account(ctx context.Context, id uint) (*Account, error) {
+	out := &Account{}
+	return out, c.do(ctx, http.MethodGet, idPath("/account", id), nil, nil, out)
+}
+
+// AccountPassword reveals the password of the account.
+func (c *Client) AccountPassword(ctx context.Context, id uint) (string, error) {
+	out := &struct {
+		Password string `json:"password"`
+	}{}
+	if err := c.do(ctx, http.MethodGet, idPath("/account", id)+"/pass", nil, nil, out); err != nil {
+		return "", err
+	}
+
+	return out.Password, nil
+}
+
+func (c *Client) CreateAccount(ctx context.Context, in *AccountInput) (*Account, error) {
+	out := &Account{}
+	return out, c.do(ctx, http.MethodPost, "/account", nil, in, out)
+}
+
+func (c *Client) UpdateAccount(ctx context.Context, id uint, in *AccountInput) (*Account, error) {
+	out := &Account{}
+	return out, c.do(ctx, http.MethodPut, idPath("/account", id), nil, in, out)
+}
+
+func (c *Client) DeleteAccount(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, idPath("/account", id), nil, nil, nil)
+}
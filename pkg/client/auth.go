@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Login authenticates with the email and password, without expire the tokens do not expire.
+func (c *Client) Login(ctx context.Context, email, password string, expire bool) (*AuthResponse, error) {
+	in := map[string]interface{}{"email": email, "password": password, "expire": expire}
+	out := &AuthResponse{}
+	if _, err := c.send(ctx, http.MethodPost, "/auth", nil, in, out, ""); err != nil {
+		return nil, err
+	}
+
+	c.setTokens(out.Tokens)
+	return out, nil
+}
+
+// Me returns the authenticated user.
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	out := &User{}
+	return out, c.do(ctx, http.MethodGet, "/auth", nil, nil, out)
+}
+
+// ChangePassword replaces the password of the authenticated user, ending its sessions, so log in again after it.
+func (c *Client) ChangePassword(ctx context.Context, current, password string) error {
+	in := &PasswordInput{Current: current, Password: password, PasswordConfirm: password}
+	return c.do(ctx, http.MethodPut, "/auth/password", nil, in, nil)
+}
+
+// ForgotPassword emails a password reset link, the API accepts unknown emails without telling them apart.
+func (c *Client) ForgotPassword(ctx context.Context, email string) error {
+	_, err := c.send(ctx, http.MethodPost, "/auth/forgot", nil, map[string]string{"email": email}, nil, "")
+	return err
+}
+
+// ResetPassword sets the password with the token of the reset email.
+func (c *Client) ResetPassword(ctx context.Context, token, password string) error {
+	in := &PasswordInput{Token: token, Password: password, PasswordConfirm: password}
+	_, err := c.send(ctx, http.MethodPost, "/auth/reset", nil, in, nil, "")
+	return err
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Tokens are the bearer tokens of a session, the personal tokens have no refresh token.
+	Tokens struct {
+		AccessToken  string `json:"accesstoken"`
+		RefreshToken string `json:"refreshtoken"`
+	}
+
+	Option func(*Client)
+
+	// Client calls the REST API, renewing the access token with the refresh token on 401 responses.
+	Client struct {
+		baseURL  string
+		http     *http.Client
+		lang     string
+		onTokens func(Tokens)
+
+		mu     sync.Mutex
+		tokens Tokens
+	}
+)
+
+// WithHTTPClient replaces the default client, with a 30 seconds timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = httpClient
+	}
+}
+
+// WithLanguage sets the language of the error messages.
+func WithLanguage(lang string) Option {
+	return func(c *Client) {
+		c.lang = lang
+	}
+}
+
+// WithTokens resumes a session, or authenticates with a personal token as access token.
+func WithTokens(tokens Tokens) Option {
+	return func(c *Client) {
+		c.tokens = tokens
+	}
+}
+
+// WithTokensHandler is called with the new tokens after each login and refresh, like to cache them.
+func WithTokensHandler(handler func(Tokens)) Option {
+	return func(c *Client) {
+		c.onTokens = handler
+	}
+}
+
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens
+}
+
+func (c *Client) setTokens(tokens Tokens) {
+	c.mu.Lock()
+	c.tokens = tokens
+	c.mu.Unlock()
+
+	if c.onTokens != nil {
+		c.onTokens(tokens)
+	}
+}
+
+func (c *Client) url(path string, query url.Values) string {
+	if c.lang != "" {
+		if query == nil {
+			query = url.Values{}
+		}
+		query.Set("lang", c.lang)
+	}
+
+	if len(query) == 0 {
+		return c.baseURL + path
+	}
+
+	return c.baseURL + path + "?" + query.Encode()
+}
+
+// send executes the request with the token, decoding the JSON response into out when not nil.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, in, out interface{}, token string) (int, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path, query), body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp.StatusCode, decodeError(resp)
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("could not decode response: %w", err)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+// do sends an authenticated request, refreshing the tokens and retrying once when the access token is refused.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	tokens := c.Tokens()
+	status, err := c.send(ctx, method, path, query, in, out, tokens.AccessToken)
+	if status != http.StatusUnauthorized || tokens.RefreshToken == "" {
+		return err
+	}
+
+	if _, refreshErr := c.Refresh(ctx); refreshErr != nil {
+		return err
+	}
+
+	_, err = c.send(ctx, method, path, query, in, out, c.Tokens().AccessToken)
+	return err
+}
+
+// Refresh renews the tokens with the refresh token.
+func (c *Client) Refresh(ctx context.Context) (*Tokens, error) {
+	tokens := c.Tokens()
+	if tokens.RefreshToken == "" {
+		return nil, ErrNoSession
+	}
+
+	renewed := &Tokens{}
+	if _, err := c.send(ctx, http.MethodPut, "/auth", nil, nil, renewed, tokens.RefreshToken); err != nil {
+		return nil, err
+	}
+
+	c.setTokens(*renewed)
+	return renewed, nil
+}
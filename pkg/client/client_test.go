@@ -0,0 +1,300 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/raulaguila/go-pass/configs"
+	"github.com/raulaguila/go-pass/internal/infra/database"
+	"github.com/raulaguila/go-pass/internal/infra/handlers"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	adminMail     string = "admin@admin.com"
+	adminPassword string = "Initial-Passw0rd"
+	newPassword   string = "Changed-Passw0rd"
+)
+
+var (
+	serverURL string
+	mailFile  string
+)
+
+func base64Key() string {
+	key, err := keyring.Generate("EdDSA")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	private, err := key.PrivateBase64()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return private
+}
+
+// Every test shares one app, seeded once and backed by a SQLite database.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "go-pass-client")
+	if err != nil {
+		log.Fatal(err)
+	}
+	mailFile = filepath.Join(dir, "mail.log")
+
+	cfg, err := configs.Load([]string{
+		"-tokens.access_private", base64Key(),
+		"-tokens.refresh_private", base64Key(),
+		"-database.driver", "sqlite",
+		"-sqlite.path", filepath.Join(dir, "test.db"),
+		"-admin.mail", adminMail,
+		"-admin.password", adminPassword,
+		"-password.hasher", "bcrypt",
+		"-password.bcrypt_cost", "4",
+		"-api.logger", "false",
+		"-mail.driver", "file",
+		"-mail.file", mailFile,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Apply(); err != nil {
+		log.Fatal(err)
+	}
+
+	db := database.Open(cfg)
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	if err := database.Seed(db, cfg.Admin); err != nil {
+		log.Fatal(err)
+	}
+
+	api, err := handlers.NewServer(db, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(api.App()))
+	serverURL = server.URL
+
+	code := m.Run()
+	server.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+var mailToken = regexp.MustCompile(`[?&]token=([^\s&]+)`)
+
+// lastMailToken returns the token of the last emailed link.
+func lastMailToken(t *testing.T) string {
+	data, err := os.ReadFile(mailFile)
+	assert.Nil(t, err)
+
+	matches := mailToken.FindAllStringSubmatch(string(data), -1)
+	if !assert.NotEmpty(t, matches) {
+		t.FailNow()
+	}
+
+	token, err := url.QueryUnescape(matches[len(matches)-1][1])
+	assert.Nil(t, err)
+	return token
+}
+
+var changePassword sync.Once
+
+// login returns a client of the administrator, the first call replaces the seeded password.
+func login(t *testing.T, options ...Option) *Client {
+	ctx := context.Background()
+	c := New(serverURL, options...)
+
+	changePassword.Do(func() {
+		response, err := c.Login(ctx, adminMail, adminPassword, true)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		assert.True(t, response.User.ChangePassword)
+
+		// Only the password change is allowed until the seeded password is replaced.
+		_, err = c.Profiles(ctx, nil)
+		assert.True(t, errors.Is(err, ErrForbidden))
+		assert.Nil(t, c.ChangePassword(ctx, adminPassword, newPassword))
+	})
+
+	response, err := c.Login(ctx, adminMail, newPassword, true)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.False(t, response.User.ChangePassword)
+
+	return c
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// go test -run TestAuth
+func TestAuth(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := New(serverURL).Me(ctx)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+
+	c := login(t)
+	me, err := c.Me(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, adminMail, me.Email)
+	assert.Equal(t, "ROOT", me.Profile.Name)
+}
+
+// go test -run TestRefresh
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	tokens := login(t).Tokens()
+
+	renewed := []Tokens{}
+	c := New(serverURL, WithTokens(Tokens{AccessToken: "invalid", RefreshToken: tokens.RefreshToken}), WithTokensHandler(func(tokens Tokens) {
+		renewed = append(renewed, tokens)
+	}))
+
+	me, err := c.Me(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, adminMail, me.Email)
+	assert.Len(t, renewed, 1)
+	assert.Equal(t, renewed[0], c.Tokens())
+	assert.NotEqual(t, "invalid", c.Tokens().AccessToken)
+
+	_, err = New(serverURL, WithTokens(Tokens{AccessToken: "invalid"})).Me(ctx)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+
+	_, err = New(serverURL).Refresh(ctx)
+	assert.True(t, errors.Is(err, ErrNoSession))
+}
+
+// go test -run TestErrors
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := login(t).Site(ctx, 999999)
+	english := &Error{}
+	assert.True(t, errors.As(err, &english))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NotEmpty(t, english.Message)
+
+	_, err = login(t, WithLanguage("pt")).Site(ctx, 999999)
+	portuguese := &Error{}
+	assert.True(t, errors.As(err, &portuguese))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NotEqual(t, english.Message, portuguese.Message)
+	assert.Equal(t, "site_not_found", english.Code)
+	assert.Equal(t, english.Code, portuguese.Code)
+}
+
+// go test -run TestAccounts
+func TestAccounts(t *testing.T) {
+	ctx := context.Background()
+	c := login(t)
+
+	operator, err := c.CreateOperator(ctx, &OperatorInput{Name: ptr("Operator")})
+	assert.Nil(t, err)
+	operator, err = c.UpdateOperator(ctx, operator.ID, &OperatorInput{Name: ptr("Renamed operator")})
+	assert.Nil(t, err)
+	assert.Equal(t, "Renamed operator", operator.Name)
+
+	phone, err := c.CreatePhone(ctx, &PhoneInput{Number: ptr("5511999999999"), OperatorID: &operator.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, operator.ID, phone.Operator.ID)
+	phones, err := c.Phones(ctx, &Filter{Search: "99999"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), phones.Count)
+
+	site, err := c.CreateSite(ctx, &SiteInput{Name: ptr("Example"), URL: ptr("https://example.com")})
+	assert.Nil(t, err)
+	_, err = c.CreateSite(ctx, &SiteInput{Name: ptr("Example")})
+	assert.True(t, errors.Is(err, ErrBadRequest))
+	_, err = c.CreateSite(ctx, &SiteInput{Name: ptr("Example"), URL: ptr("https://example.org")})
+	assert.True(t, errors.Is(err, ErrConflict))
+
+	account, err := c.CreateAccount(ctx, &AccountInput{Username: ptr("john"), Password: ptr("account-secret"), SiteID: &site.ID, PhoneID: &phone.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, "john", account.Username)
+	assert.Equal(t, site.ID, account.Site.ID)
+
+	password, err := c.AccountPassword(ctx, account.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "account-secret", password)
+
+	account, err = c.UpdateAccount(ctx, account.ID, &AccountInput{Username: ptr("jane")})
+	assert.Nil(t, err)
+	assert.Equal(t, "jane", account.Username)
+
+	accounts, err := c.Accounts(ctx, &AccountFilter{SiteIDs: []uint{site.ID}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), accounts.Count)
+	assert.Equal(t, account.ID, accounts.Items[0].ID)
+
+	sites, err := c.Sites(ctx, &Filter{Search: "EXAMP", Page: 1, Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), sites.Count)
+	sites, err = c.Sites(ctx, &Filter{Search: "missing"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), sites.Count)
+
+	assert.Nil(t, c.DeleteAccount(ctx, account.ID))
+	_, err = c.Account(ctx, account.ID)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	assert.Nil(t, c.DeleteSite(ctx, site.ID))
+	assert.Nil(t, c.DeletePhone(ctx, phone.ID))
+	assert.Nil(t, c.DeleteOperator(ctx, operator.ID))
+}
+
+// go test -run TestUsers
+func TestUsers(t *testing.T) {
+	ctx := context.Background()
+	c := login(t)
+
+	profile, err := c.CreateProfile(ctx, &ProfileInput{Name: ptr("AUDITOR"), Permissions: PermissionsInput{UserModule: ptr(true)}})
+	assert.Nil(t, err)
+	assert.True(t, profile.Permissions.UserModule)
+	assert.False(t, profile.Permissions.ProfileModule)
+
+	user, err := c.CreateUser(ctx, &UserInput{Name: ptr("Auditor user"), Email: ptr("auditor@admin.com"), Status: ptr(true), ProfileID: &profile.ID})
+	assert.Nil(t, err)
+	assert.True(t, user.New)
+
+	users, err := c.Users(ctx, &UserFilter{Filter: Filter{Search: "auditor"}, ProfileID: profile.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), users.Count)
+	assert.Equal(t, user.ID, users.Items[0].ID)
+
+	user, err = c.AcceptInvite(ctx, "auditor@admin.com", lastMailToken(t), "Auditor-Passw0rd")
+	assert.Nil(t, err)
+	assert.False(t, user.New)
+
+	auditor := New(serverURL)
+	_, err = auditor.Login(ctx, "auditor@admin.com", "Auditor-Passw0rd", true)
+	assert.Nil(t, err)
+
+	assert.Nil(t, c.DeleteUser(ctx, user.ID))
+	_, err = c.User(ctx, user.ID)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	assert.Nil(t, c.DeleteProfile(ctx, profile.ID))
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Error is a problem response of the API, compare it with errors.Is to the status errors like ErrNotFound, or branch
+// on its Code, which does not depend on the language like the Message.
+type Error struct {
+	Status    int          `json:"status"`
+	Code      string       `json:"code"`
+	Title     string       `json:"title"`
+	Message   string       `json:"detail"`
+	RequestID string       `json:"request_id"`
+	Fields    []FieldError `json:"errors"`
+}
+
+// FieldError is a validation rule broken by a field of the request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+var ErrNoSession error = errors.New("no session, log in first")
+
+var (
+	ErrBadRequest      error = &Error{Status: http.StatusBadRequest}
+	ErrUnauthorized    error = &Error{Status: http.StatusUnauthorized}
+	ErrForbidden       error = &Error{Status: http.StatusForbidden}
+	ErrNotFound        error = &Error{Status: http.StatusNotFound}
+	ErrConflict        error = &Error{Status: http.StatusConflict}
+	ErrTooManyRequests error = &Error{Status: http.StatusTooManyRequests}
+	ErrInternal        error = &Error{Status: http.StatusInternalServerError}
+)
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%v %v", e.Status, http.StatusText(e.Status))
+	}
+
+	return fmt.Sprintf("%v: %v", e.Status, e.Message)
+}
+
+// Is matches the errors with the same status.
+func (e *Error) Is(target error) bool {
+	other, ok := target.(*Error)
+	return ok && other.Status == e.Status
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil || json.Unmarshal(data, apiErr) != nil {
+		apiErr = &Error{}
+	}
+	apiErr.Status = resp.StatusCode
+
+	return apiErr
+}
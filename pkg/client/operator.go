@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) Operators(ctx context.Context, filter *Filter) (*List[Operator], error) {
+	out := &List[Operator]{}
+	return out, c.do(ctx, http.MethodGet, "/operator", filter.query(), nil, out)
+}
+
+func (c *Client) Operator(ctx context.Context, id uint) (*Operator, error) {
+	out := &Operator{}
+	return out, c.do(ctx, http.MethodGet, idPath("/operator", id), nil, nil, out)
+}
+
+func (c *Client) CreateOperator(ctx context.Context, in *OperatorInput) (*Operator, error) {
+	out := &Operator{}
+	return out, c.do(ctx, http.MethodPost, "/operator", nil, in, out)
+}
+
+func (c *Client) UpdateOperator(ctx context.Context, id uint, in *OperatorInput) (*Operator, error) {
+	out := &Operator{}
+	return out, c.do(ctx, http.MethodPut, idPath("/operator", id), nil, in, out)
+}
+
+func (c *Client) DeleteOperator(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, idPath("/operator", id), nil, nil, nil)
+}
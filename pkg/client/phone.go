@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) Phones(ctx context.Context, filter *Filter) (*List[Phone], error) {
+	out := &List[Phone]{}
+	return out, c.do(ctx, http.MethodGet, "/phone", filter.query(), nil, out)
+}
+
+func (c *Client) Phone(ctx context.Context, id uint) (*Phone, error) {
+	out := &Phone{}
+	return out, c.do(ctx, http.MethodGet, idPath("/phone", id), nil, nil, out)
+}
+
+func (c *Client) CreatePhone(ctx context.Context, in *PhoneInput) (*Phone, error) {
+	out := &Phone{}
+	return out, c.do(ctx, http.MethodPost, "/phone", nil, in, out)
+}
+
+func (c *Client) UpdatePhone(ctx context.Context, id uint, in *PhoneInput) (*Phone, error) {
+	out := &Phone{}
+	return out, c.do(ctx, http.MethodPut, idPath("/phone", id), nil, in, out)
+}
+
+func (c *Client) DeletePhone(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, idPath("/phone", id), nil, nil, nil)
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) Profiles(ctx context.Context, filter *Filter) (*List[Profile], error) {
+	out := &List[Profile]{}
+	return out, c.do(ctx, http.MethodGet, "/profile", filter.query(), nil, out)
+}
+
+func (c *Client) Profile(ctx context.Context, id uint) (*Profile, error) {
+	out := &Profile{}
+	return out, c.do(ctx, http.MethodGet, idPath("/profile", id), nil, nil, out)
+}
+
+func (c *Client) CreateProfile(ctx context.Context, in *ProfileInput) (*Profile, error) {
+	out := &Profile{}
+	return out, c.do(ctx, http.MethodPost, "/profile", nil, in, out)
+}
+
+func (c *Client) UpdateProfile(ctx context.Context, id uint, in *ProfileInput) (*Profile, error) {
+	out := &Profile{}
+	return out, c.do(ctx, http.MethodPut, idPath("/profile", id), nil, in, out)
+}
+
+func (c *Client) DeleteProfile(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, idPath("/profile", id), nil, nil, nil)
+}
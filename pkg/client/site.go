@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) Sites(ctx context.Context, filter *Filter) (*List[Site], error) {
+	out := &List[Site]{}
+	return out, c.do(ctx, http.MethodGet, "/site", filter.query(), nil, out)
+}
+
+func (c *Client) Site(ctx context.Context, id uint) (*Site, error) {
+	out := &Site{}
+	return out, c.do(ctx, http.MethodGet, idPath("/site", id), nil, nil, out)
+}
+
+func (c *Client) CreateSite(ctx context.Context, in *SiteInput) (*Site, error) {
+	out := &Site{}
+	return out, c.do(ctx, http.MethodPost, "/site", nil, in, out)
+}
+
+func (c *Client) UpdateSite(ctx context.Context, id uint, in *SiteInput) (*Site, error) {
+	out := &Site{}
+	return out, c.do(ctx, http.MethodPut, idPath("/site", id), nil, in, out)
+}
+
+func (c *Client) DeleteSite(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, idPath("/site", id), nil, nil, nil)
+}
@@ -0,0 +1,194 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+)
+
+type (
+	// List is a page of items and the count of items matching the filter.
+	List[T any] struct {
+		Items []T   `json:"items"`
+		Count int64 `json:"count"`
+	}
+
+	// Filter of the list routes, the zero value lists everything with the server defaults.
+	Filter struct {
+		Search string
+		Page   int
+		Limit  int
+		Sort   string
+		Order  string
+	}
+
+	UserFilter struct {
+		Filter
+		ProfileID uint
+		Source    string
+	}
+
+	AccountFilter struct {
+		Filter
+		SiteIDs []uint
+	}
+
+	Permissions struct {
+		UserModule    bool `json:"user_module"`
+		ProfileModule bool `json:"profile_module"`
+	}
+
+	Profile struct {
+		ID          uint        `json:"id"`
+		Name        string      `json:"name"`
+		Permissions Permissions `json:"permissions"`
+	}
+
+	User struct {
+		ID             uint     `json:"id"`
+		Name           string   `json:"name"`
+		Email          string   `json:"mail"`
+		Status         bool     `json:"status"`
+		New            bool     `json:"new"`
+		Source         string   `json:"source"`
+		ChangePassword bool     `json:"change_password"`
+		Language       string   `json:"language"`
+		Profile        *Profile `json:"profile,omitempty"`
+	}
+
+	AuthResponse struct {
+		User *User `json:"user"`
+		Tokens
+	}
+
+	Site struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+
+	Operator struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+	}
+
+	Phone struct {
+		ID       uint      `json:"id"`
+		Number   string    `json:"number"`
+		Operator *Operator `json:"operator,omitempty"`
+	}
+
+	Account struct {
+		ID       uint     `json:"id"`
+		Username string   `json:"username"`
+		Site     *Site    `json:"site,omitempty"`
+		Phone    *Phone   `json:"phone,omitempty"`
+		Mail     *Account `json:"mail,omitempty"`
+	}
+
+	PermissionsInput struct {
+		UserModule    *bool `json:"user_module,omitempty"`
+		ProfileModule *bool `json:"profile_module,omitempty"`
+	}
+
+	// The nil fields of the inputs are left unchanged on updates.
+	ProfileInput struct {
+		Name        *string          `json:"name,omitempty"`
+		Permissions PermissionsInput `json:"permissions"`
+	}
+
+	UserInput struct {
+		Name      *string `json:"name,omitempty"`
+		Email     *string `json:"email,omitempty"`
+		Status    *bool   `json:"status,omitempty"`
+		ProfileID *uint   `json:"profile_id,omitempty"`
+		Language  *string `json:"language,omitempty"`
+	}
+
+	// PasswordInput sets a password, the token is the one of the invitation or the reset email.
+	PasswordInput struct {
+		Token           string `json:"token,omitempty"`
+		Current         string `json:"current_password,omitempty"`
+		Password        string `json:"password"`
+		PasswordConfirm string `json:"password_confirm"`
+	}
+
+	SiteInput struct {
+		Name *string `json:"name,omitempty"`
+		URL  *string `json:"url,omitempty"`
+	}
+
+	OperatorInput struct {
+		Name *string `json:"name,omitempty"`
+	}
+
+	PhoneInput struct {
+		Number     *string `json:"number,omitempty"`
+		OperatorID *uint   `json:"operator_id,omitempty"`
+	}
+
+	AccountInput struct {
+		Username *string `json:"username,omitempty"`
+		Password *string `json:"password,omitempty"`
+		SiteID   *uint   `json:"site_id,omitempty"`
+		PhoneID  *uint   `json:"phone_id,omitempty"`
+		MailID   *uint   `json:"mail_id,omitempty"`
+	}
+)
+
+func (f *Filter) query() url.Values {
+	query := url.Values{}
+	if f == nil {
+		return query
+	}
+
+	if f.Search != "" {
+		query.Set("search", f.Search)
+	}
+	if f.Page > 0 {
+		query.Set("page", strconv.Itoa(f.Page))
+	}
+	if f.Limit > 0 {
+		query.Set("limit", strconv.Itoa(f.Limit))
+	}
+	if f.Sort != "" {
+		query.Set("sort", f.Sort)
+	}
+	if f.Order != "" {
+		query.Set("order", f.Order)
+	}
+
+	return query
+}
+
+func (f *UserFilter) query() url.Values {
+	if f == nil {
+		return url.Values{}
+	}
+
+	query := f.Filter.query()
+	if f.ProfileID > 0 {
+		query.Set("profile_id", strconv.FormatUint(uint64(f.ProfileID), 10))
+	}
+	if f.Source != "" {
+		query.Set("source", f.Source)
+	}
+
+	return query
+}
+
+func (f *AccountFilter) query() url.Values {
+	if f == nil {
+		return url.Values{}
+	}
+
+	query := f.Filter.query()
+	for _, id := range f.SiteIDs {
+		query.Add("site_id", strconv.FormatUint(uint64(id), 10))
+	}
+
+	return query
+}
+
+func idPath(prefix string, id uint) string {
+	return prefix + "/" + strconv.FormatUint(uint64(id), 10)
+}
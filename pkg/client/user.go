@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+func (c *Client) Users(ctx context.Context, filter *UserFilter) (*List[User], error) {
+	out := &List[User]{}
+	return out, c.do(ctx, http.MethodGet, "/user", filter.query(), nil, out)
+}
+
+func (c *Client) User(ctx context.Context, id uint) (*User, error) {
+	out := &User{}
+	return out, c.do(ctx, http.MethodGet, idPath("/user", id), nil, nil, out)
+}
+
+// CreateUser creates a user without password, the API emails the invitation.
+func (c *Client) CreateUser(ctx context.Context, in *UserInput) (*User, error) {
+	out := &User{}
+	return out, c.do(ctx, http.MethodPost, "/user", nil, in, out)
+}
+
+func (c *Client) UpdateUser(ctx context.Context, id uint, in *UserInput) (*User, error) {
+	out := &User{}
+	return out, c.do(ctx, http.MethodPut, idPath("/user", id), nil, in, out)
+}
+
+func (c *Client) DeleteUser(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, idPath("/user", id), nil, nil, nil)
+}
+
+// ResetUser removes the password of the user and emails a new invitation.
+func (c *Client) ResetUser(ctx context.Context, id uint) (*User, error) {
+	out := &User{}
+	return out, c.do(ctx, http.MethodPatch, idPath("/user", id)+"/reset", nil, nil, out)
+}
+
+// InviteUser emails a new invitation to a user without password.
+func (c *Client) InviteUser(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodPost, idPath("/user", id)+"/invite", nil, nil, nil)
+}
+
+// AcceptInvite sets the first password of the user with the token of the invitation.
+func (c *Client) AcceptInvite(ctx context.Context, email, token, password string) (*User, error) {
+	in := &PasswordInput{Token: token, Password: password, PasswordConfirm: password}
+	out := &User{}
+	if _, err := c.send(ctx, http.MethodPatch, "/user/"+url.PathEscape(email)+"/passw", nil, in, out, ""); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,253 @@
+package conf
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv is the environment variable with the configuration file path, also given by the -config flag.
+const FileEnv string = "CONFIG_FILE"
+
+var (
+	ErrUnsupportedFile error = errors.New("unsupported configuration file, use .toml, .yaml or .yml")
+	ErrUnknownKey      error = errors.New("unknown configuration key")
+)
+
+// option is a field of a section, tagged like:
+//
+//	Port string `key:"port" env:"API_PORT,OLD_PORT" default:"9000"`
+//	Life time.Duration `key:"life" env:"LIFE" default:"30" unit:"m"`
+//
+// The files use the section and field keys, the flags are named -section.key and
+// the first environment variable set wins, the others are deprecated names.
+type option struct {
+	name  string
+	env   []string
+	def   string
+	unit  time.Duration
+	value reflect.Value
+}
+
+func options(config interface{}) ([]*option, error) {
+	root := reflect.ValueOf(config)
+	if root.Kind() != reflect.Pointer || root.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("configuration must be a pointer to struct")
+	}
+	root = root.Elem()
+
+	opts := []*option{}
+	for i := 0; i < root.NumField(); i++ {
+		section, value := root.Type().Field(i), root.Field(i)
+		sectionKey := section.Tag.Get("key")
+		if sectionKey == "" || sectionKey == "-" || value.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := 0; j < value.NumField(); j++ {
+			field := value.Type().Field(j)
+			key := field.Tag.Get("key")
+			if key == "" || key == "-" {
+				continue
+			}
+
+			opt := &option{name: sectionKey + "." + key, def: field.Tag.Get("default"), value: value.Field(j)}
+			if env := field.Tag.Get("env"); env != "" {
+				opt.env = strings.Split(env, ",")
+			}
+			if unit := field.Tag.Get("unit"); unit != "" {
+				parsed, err := time.ParseDuration("1" + unit)
+				if err != nil {
+					return nil, fmt.Errorf("%v: invalid unit %q", opt.name, unit)
+				}
+				opt.unit = parsed
+			}
+
+			opts = append(opts, opt)
+		}
+	}
+
+	return opts, nil
+}
+
+// set parses the raw value into the field, the durations without unit use the unit of the option.
+func (o *option) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch o.value.Interface().(type) {
+	case string:
+		o.value.SetString(raw)
+	case bool:
+		if raw == "" {
+			o.value.SetBool(false)
+			return nil
+		}
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%v: invalid boolean %q", o.name, raw)
+		}
+		o.value.SetBool(parsed)
+	case int:
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%v: invalid integer %q", o.name, raw)
+		}
+		o.value.SetInt(int64(parsed))
+	case time.Duration:
+		if parsed, err := strconv.Atoi(raw); err == nil && o.unit != 0 {
+			o.value.SetInt(int64(time.Duration(parsed) * o.unit))
+			return nil
+		}
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%v: invalid duration %q", o.name, raw)
+		}
+		o.value.SetInt(int64(parsed))
+	case []string:
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		o.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("%v: unsupported type %v", o.name, o.value.Type())
+	}
+
+	return nil
+}
+
+// readFile flattens the sections of a TOML or YAML file into section.key values.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := map[string]map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(data, &sections)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &sections)
+	default:
+		return nil, ErrUnsupportedFile
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+
+	values := map[string]string{}
+	for section, keys := range sections {
+		for key, value := range keys {
+			if items, ok := value.([]interface{}); ok {
+				texts := make([]string, len(items))
+				for i, item := range items {
+					texts[i] = fmt.Sprint(item)
+				}
+				value = strings.Join(texts, ",")
+			}
+			values[section+"."+key] = fmt.Sprint(value)
+		}
+	}
+
+	return values, nil
+}
+
+// Load fills the sections of the config with the defaults, the configuration file, the environment and the flags, each one overriding the previous.
+// The dotenv file is loaded when present, without overriding the variables already set.
+func Load(config interface{}, name, envFile string, args []string) error {
+	opts, err := options(config)
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	file := flags.String("config", "", "Configuration file, TOML or YAML (env "+FileEnv+")")
+	values := map[string]*string{}
+	for _, opt := range opts {
+		usage := "Overrides " + opt.name
+		if len(opt.env) > 0 {
+			usage += " (env " + opt.env[0] + ")"
+		}
+		values[opt.name] = flags.String(opt.name, "", usage)
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if envFile != "" {
+		if err := godotenv.Load(envFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	if *file == "" {
+		*file = os.Getenv(FileEnv)
+	}
+	fileValues := map[string]string{}
+	if *file != "" {
+		if fileValues, err = readFile(*file); err != nil {
+			return err
+		}
+	}
+
+	visited := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		visited[f.Name] = true
+	})
+
+	errs := []error{}
+	known := map[string]bool{}
+	for _, opt := range opts {
+		known[opt.name] = true
+
+		raw := opt.def
+		if value, ok := fileValues[opt.name]; ok {
+			raw = value
+		}
+		for i, env := range opt.env {
+			if value, ok := os.LookupEnv(env); ok {
+				if i > 0 {
+					log.Printf("[CONFIG] %v is deprecated, use %v\n", env, opt.env[0])
+				}
+				raw = value
+				break
+			}
+		}
+		if visited[opt.name] {
+			raw = *values[opt.name]
+		}
+
+		if err := opt.set(raw); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	keys := make([]string, 0, len(fileValues))
+	for key := range fileValues {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if !known[key] {
+			errs = append(errs, fmt.Errorf("%w: %v", ErrUnknownKey, key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
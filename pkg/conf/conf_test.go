@@ -0,0 +1,119 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig struct {
+	Version string
+	API     testAPI   `key:"api"`
+	Token   testToken `key:"token"`
+}
+
+type testAPI struct {
+	Port      string   `key:"port" env:"TEST_API_PORT" default:"9000"`
+	Logger    bool     `key:"logger" env:"TEST_API_LOGGER" default:"true"`
+	Languages []string `key:"languages" env:"TEST_API_LANGUAGES" default:"en, pt"`
+	Internal  string
+}
+
+type testToken struct {
+	Expire   time.Duration `key:"expire" env:"TEST_TOKEN_EXPIRE,TEST_TOKEN_EXPIRE_OLD" default:"120" unit:"m"`
+	Attempts int           `key:"attempts" env:"TEST_TOKEN_ATTEMPTS" default:"5"`
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// go test -run TestLoadDefaults
+func TestLoadDefaults(t *testing.T) {
+	config := &testConfig{}
+	assert.Nil(t, Load(config, "test", "", nil))
+
+	assert.Equal(t, "9000", config.API.Port)
+	assert.True(t, config.API.Logger)
+	assert.Equal(t, []string{"en", "pt"}, config.API.Languages)
+	assert.Equal(t, 2*time.Hour, config.Token.Expire)
+	assert.Equal(t, 5, config.Token.Attempts)
+}
+
+// go test -run TestLoadPrecedence
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, "config.toml", "[api]\nport = 8000\nlogger = false\nlanguages = [\"pt\"]\n\n[token]\nexpire = \"90s\"\nattempts = 3\n")
+	t.Setenv("TEST_API_PORT", "7000")
+	t.Setenv("TEST_TOKEN_EXPIRE", "30")
+
+	config := &testConfig{}
+	assert.Nil(t, Load(config, "test", "", []string{"-config", path, "-api.port", "6000"}))
+
+	assert.Equal(t, "6000", config.API.Port, "flags override the environment")
+	assert.Equal(t, 30*time.Minute, config.Token.Expire, "the environment overrides the file")
+	assert.False(t, config.API.Logger)
+	assert.Equal(t, []string{"pt"}, config.API.Languages)
+	assert.Equal(t, 3, config.Token.Attempts)
+}
+
+// go test -run TestLoadYAML
+func TestLoadYAML(t *testing.T) {
+	path := writeFile(t, "config.yaml", "api:\n  port: \"8000\"\n  languages: [en, pt, es]\ntoken:\n  expire: 1h\n")
+	t.Setenv(FileEnv, path)
+
+	config := &testConfig{}
+	assert.Nil(t, Load(config, "test", "", nil))
+
+	assert.Equal(t, "8000", config.API.Port)
+	assert.Equal(t, []string{"en", "pt", "es"}, config.API.Languages)
+	assert.Equal(t, time.Hour, config.Token.Expire)
+}
+
+// go test -run TestLoadDeprecatedEnv
+func TestLoadDeprecatedEnv(t *testing.T) {
+	t.Setenv("TEST_TOKEN_EXPIRE_OLD", "10")
+
+	config := &testConfig{}
+	assert.Nil(t, Load(config, "test", "", nil))
+	assert.Equal(t, 10*time.Minute, config.Token.Expire)
+
+	t.Setenv("TEST_TOKEN_EXPIRE", "20")
+	assert.Nil(t, Load(config, "test", "", nil))
+	assert.Equal(t, 20*time.Minute, config.Token.Expire)
+}
+
+// go test -run TestLoadEnvFile
+func TestLoadEnvFile(t *testing.T) {
+	path := writeFile(t, ".env", "TEST_TOKEN_ATTEMPTS='7'\n")
+
+	config := &testConfig{}
+	assert.Nil(t, Load(config, "test", path, nil))
+	assert.Equal(t, 7, config.Token.Attempts)
+	os.Unsetenv("TEST_TOKEN_ATTEMPTS")
+
+	assert.Nil(t, Load(config, "test", filepath.Join(t.TempDir(), "missing.env"), nil))
+}
+
+// go test -run TestLoadErrors
+func TestLoadErrors(t *testing.T) {
+	t.Setenv("TEST_API_LOGGER", "maybe")
+	t.Setenv("TEST_TOKEN_ATTEMPTS", "five")
+	path := writeFile(t, "config.toml", "[api]\nprot = \"8000\"\n")
+
+	err := Load(&testConfig{}, "test", "", []string{"-config", path})
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrUnknownKey))
+	assert.Contains(t, err.Error(), "api.logger")
+	assert.Contains(t, err.Error(), "token.attempts")
+	assert.Contains(t, err.Error(), "api.prot")
+
+	assert.Equal(t, ErrUnsupportedFile, Load(&testConfig{}, "test", "", []string{"-config", writeFile(t, "config.ini", "")}))
+	assert.NotNil(t, Load(&testConfig{}, "test", "", []string{"-unknown"}))
+	assert.NotNil(t, Load(testConfig{}, "test", "", nil))
+}
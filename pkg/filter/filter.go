@@ -1,8 +1,8 @@
 package filter
 
 import (
+	"database/sql"
 	"fmt"
-	"os"
 	"slices"
 	"strings"
 
@@ -11,13 +11,15 @@ import (
 
 var orders []string = []string{"asc", "desc"}
 
-func NewFilter() *Filter {
+// NewFilter creates a filter with the default sort and order, the order is also used when the requested one is invalid.
+func NewFilter(sort, order string) *Filter {
 	return &Filter{
-		Search: "",
-		Page:   0,
-		Limit:  0,
-		Sort:   os.Getenv("API_DEFAULT_SORT"),
-		Order:  os.Getenv("API_DEFAULT_ORDER"),
+		Search:       "",
+		Page:         0,
+		Limit:        0,
+		Sort:         sort,
+		Order:        order,
+		defaultOrder: order,
 	}
 }
 
@@ -28,35 +30,40 @@ type (
 		Limit  int    `query:"limit" form:"limit" example:"10"`
 		Sort   string `query:"sort" form:"sort" example:"'updated_at', 'created_at', 'name' or some other field of the response object"`
 		Order  string `query:"order" form:"order" example:"descending order 'desc' or ascending order 'asc'"`
+
+		defaultOrder string
 	}
 
 	UserFilter struct {
 		Filter
-		ProfileID uint `query:"profile_id" form:"profile_id" example:"1"`
+		ProfileID uint   `query:"profile_id" form:"profile_id" example:"1"`
+		Source    string `query:"source" form:"source" example:"local"`
+	}
+
+	AccountFilter struct {
+		Filter
+		SiteIDs []uint `query:"site_id" form:"site_id" example:"1"`
 	}
 )
 
+// ApplySearchLike keeps the rows with any column containing the search, ignoring the case, and the accents on PostgreSQL.
 func (s *Filter) ApplySearchLike(db *gorm.DB, columns ...string) *gorm.DB {
-	if len(columns) > 0 && s.Search != "" {
-		whereLike := func(column, value string) string {
-			return fmt.Sprintf("unaccent(LOWER(%v)) LIKE unaccent(LOWER('%%%v%%'))", column, value)
-		}
-		where := ""
-
-		for i, column := range columns {
-			if i > 0 {
-				where = where + " or "
-			}
-
-			where = where + whereLike(column, s.Search)
-		}
-
-		if where != "" {
-			return db.Where(where)
-		}
+	if len(columns) == 0 || s.Search == "" {
+		return db
 	}
 
-	return db
+	// SQLite has no unaccent, and its LOWER only folds the ASCII letters.
+	like := "LOWER(%v) LIKE LOWER(@search)"
+	if db.Dialector.Name() == "postgres" {
+		like = "unaccent(LOWER(%v)) LIKE unaccent(LOWER(@search))"
+	}
+
+	where := make([]string, len(columns))
+	for i, column := range columns {
+		where[i] = fmt.Sprintf(like, column)
+	}
+
+	return db.Where("("+strings.Join(where, " OR ")+")", sql.Named("search", "%"+s.Search+"%"))
 }
 
 func (s *Filter) ApplyOrder(db *gorm.DB) *gorm.DB {
@@ -64,6 +71,12 @@ func (s *Filter) ApplyOrder(db *gorm.DB) *gorm.DB {
 	return db.Order(fmt.Sprintf("%v %v", s.Sort, s.Order))
 }
 
+// Descending reports whether the rows are sorted in descending order, validating the order like ApplyOrder.
+func (s *Filter) Descending() bool {
+	s.check()
+	return s.Order == "desc"
+}
+
 func (s *Filter) ApplyPagination(db *gorm.DB) *gorm.DB {
 	if s.Page > 0 && s.Limit > 0 {
 		return db.Offset((s.Page - 1) * s.Limit).Limit(s.Limit)
@@ -75,6 +88,6 @@ func (s *Filter) ApplyPagination(db *gorm.DB) *gorm.DB {
 func (s *Filter) check() {
 	s.Order = strings.ToLower(s.Order)
 	if !slices.Contains(orders, s.Order) {
-		s.Order = os.Getenv("API_DEFAULT_ORDER")
+		s.Order = s.defaultOrder
 	}
 }
@@ -1,23 +1,63 @@
 package filter
 
 import (
-	"os"
 	"testing"
 
+	"github.com/glebarez/sqlite"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 )
 
 // go test -run TestNewFilter
 func TestNewFilter(t *testing.T) {
-	os.Setenv("API_DEFAULT_SORT", "updated_at")
-	os.Setenv("API_DEFAULT_ORDER", "desc")
-
-	filter := NewFilter()
+	filter := NewFilter("updated_at", "desc")
 
 	assert.NotNil(t, filter)
 	assert.Equal(t, "", filter.Search)
 	assert.Equal(t, 0, filter.Limit)
 	assert.Equal(t, 0, filter.Page)
-	assert.Equal(t, os.Getenv("API_DEFAULT_SORT"), filter.Sort)
-	assert.Equal(t, os.Getenv("API_DEFAULT_ORDER"), filter.Order)
+	assert.Equal(t, "updated_at", filter.Sort)
+	assert.Equal(t, "desc", filter.Order)
+}
+
+// go test -run TestFilterInvalidOrder
+func TestFilterInvalidOrder(t *testing.T) {
+	filter := NewFilter("name", "asc")
+	filter.Order = "DESC"
+	filter.check()
+	assert.Equal(t, "desc", filter.Order)
+
+	filter.Order = "sideways"
+	filter.check()
+	assert.Equal(t, "asc", filter.Order)
+
+	filter.Order = "Desc"
+	assert.True(t, filter.Descending())
+	filter.Order = "sideways"
+	assert.False(t, filter.Descending())
+}
+
+// go test -run TestFilterSearchLike
+func TestFilterSearchLike(t *testing.T) {
+	type row struct {
+		Name string
+	}
+
+	search := func(dialector gorm.Dialector) *gorm.Statement {
+		db, err := gorm.Open(dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+		assert.Nil(t, err)
+
+		filter := NewFilter("name", "asc")
+		filter.Search = "o'neil"
+		return filter.ApplySearchLike(db, "name", "mail").Find(&[]row{}).Statement
+	}
+
+	statement := search(postgres.New(postgres.Config{DSN: "host=localhost"}))
+	assert.Equal(t, `SELECT * FROM "rows" WHERE (unaccent(LOWER(name)) LIKE unaccent(LOWER($1)) OR unaccent(LOWER(mail)) LIKE unaccent(LOWER($2)))`, statement.SQL.String())
+	assert.Equal(t, []interface{}{"%o'neil%", "%o'neil%"}, statement.Vars)
+
+	statement = search(sqlite.Open(":memory:"))
+	assert.Equal(t, "SELECT * FROM `rows` WHERE (LOWER(name) LIKE LOWER(?) OR LOWER(mail) LIKE LOWER(?))", statement.SQL.String())
+	assert.Equal(t, []interface{}{"%o'neil%", "%o'neil%"}, statement.Vars)
 }
@@ -0,0 +1,58 @@
+package hasher
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUnknownHash error = errors.New("unknown password hash format")
+	ErrInvalidHash error = errors.New("invalid password hash")
+)
+
+// Hasher creates and verifies PHC formatted password hashes.
+type Hasher interface {
+	// Match reports whether the hash was created by this kind of hasher.
+	Match(encoded string) bool
+	Hash(password string) (string, error)
+	Verify(encoded, password string) (bool, error)
+	// NeedsRehash reports whether the hash was created with other parameters than the hasher ones.
+	NeedsRehash(encoded string) bool
+}
+
+var (
+	// Hashes the new passwords.
+	defaultHasher Hasher = NewArgon2id(DefaultArgon2idParams)
+
+	// Verify the hashes of every supported format, whatever the default hasher is.
+	verifiers []Hasher = []Hasher{NewArgon2id(DefaultArgon2idParams), NewBcrypt(bcrypt.DefaultCost)}
+)
+
+// SetDefault changes the hasher of the new passwords, the existing hashes are still verified.
+func SetDefault(h Hasher) {
+	defaultHasher = h
+}
+
+// Hash hashes the password with the default hasher.
+func Hash(password string) (string, error) {
+	return defaultHasher.Hash(password)
+}
+
+// Verify checks the password against a hash of any supported format,
+// rehash is true when the password matches but the hash should be replaced by one of the default hasher.
+func Verify(encoded, password string) (ok bool, rehash bool, err error) {
+	if defaultHasher.Match(encoded) {
+		ok, err = defaultHasher.Verify(encoded, password)
+		return ok, ok && defaultHasher.NeedsRehash(encoded), err
+	}
+
+	for _, verifier := range verifiers {
+		if verifier.Match(encoded) {
+			ok, err = verifier.Verify(encoded, password)
+			return ok, ok, err
+		}
+	}
+
+	return false, false, ErrUnknownHash
+}
@@ -0,0 +1,137 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testParams Argon2idParams = Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func setTestDefault(t *testing.T, h Hasher) {
+	previous := defaultHasher
+	SetDefault(h)
+	t.Cleanup(func() { SetDefault(previous) })
+}
+
+// go test -run TestArgon2id
+func TestArgon2id(t *testing.T) {
+	h := NewArgon2id(testParams)
+
+	encoded, err := h.Hash("Correct-horse7")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.True(t, h.Match(encoded))
+	assert.False(t, h.NeedsRehash(encoded))
+
+	ok, err := h.Verify(encoded, "Correct-horse7")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify(encoded, "Wrong-horse7")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	other, err := h.Hash("Correct-horse7")
+	assert.Nil(t, err)
+	assert.NotEqual(t, encoded, other, "the salt must be random")
+}
+
+// go test -run TestArgon2idInvalidHash
+func TestArgon2idInvalidHash(t *testing.T) {
+	h := NewArgon2id(testParams)
+
+	for _, encoded := range []string{
+		"$argon2id$",
+		"$argon2id$v=18$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!$a2V5",
+	} {
+		ok, err := h.Verify(encoded, "password")
+		assert.False(t, ok, encoded)
+		assert.Equal(t, ErrInvalidHash, err, encoded)
+		assert.True(t, h.NeedsRehash(encoded), encoded)
+	}
+}
+
+// go test -run TestArgon2idNeedsRehash
+func TestArgon2idNeedsRehash(t *testing.T) {
+	encoded, err := NewArgon2id(testParams).Hash("Correct-horse7")
+	assert.Nil(t, err)
+
+	stronger := testParams
+	stronger.Iterations = 2
+	assert.True(t, NewArgon2id(stronger).NeedsRehash(encoded))
+}
+
+// go test -run TestBcrypt
+func TestBcrypt(t *testing.T) {
+	h := NewBcrypt(bcrypt.MinCost)
+
+	encoded, err := h.Hash("Correct-horse7")
+	assert.Nil(t, err)
+	assert.True(t, h.Match(encoded))
+	assert.False(t, h.NeedsRehash(encoded))
+	assert.True(t, NewBcrypt(bcrypt.MinCost+1).NeedsRehash(encoded))
+
+	ok, err := h.Verify(encoded, "Correct-horse7")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = h.Verify(encoded, "Wrong-horse7")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+// go test -run TestVerifyUpgradesBcrypt
+func TestVerifyUpgradesBcrypt(t *testing.T) {
+	setTestDefault(t, NewArgon2id(testParams))
+
+	legacy, err := NewBcrypt(bcrypt.MinCost).Hash("Correct-horse7")
+	assert.Nil(t, err)
+
+	ok, rehash, err := Verify(legacy, "Correct-horse7")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.True(t, rehash)
+
+	ok, rehash, err = Verify(legacy, "Wrong-horse7")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	assert.False(t, rehash)
+
+	encoded, err := Hash("Correct-horse7")
+	assert.Nil(t, err)
+
+	ok, rehash, err = Verify(encoded, "Correct-horse7")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.False(t, rehash)
+}
+
+// go test -run TestVerifyOutdatedParams
+func TestVerifyOutdatedParams(t *testing.T) {
+	setTestDefault(t, NewArgon2id(testParams))
+	encoded, err := Hash("Correct-horse7")
+	assert.Nil(t, err)
+
+	stronger := testParams
+	stronger.Memory = 2048
+	setTestDefault(t, NewArgon2id(stronger))
+
+	ok, rehash, err := Verify(encoded, "Correct-horse7")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.True(t, rehash)
+}
+
+// go test -run TestVerifyUnknownHash
+func TestVerifyUnknownHash(t *testing.T) {
+	ok, rehash, err := Verify("plain-text", "plain-text")
+	assert.False(t, ok)
+	assert.False(t, rehash)
+	assert.Equal(t, ErrUnknownHash, err)
+}
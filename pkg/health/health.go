@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   string = "up"
+	StatusDown string = "down"
+)
+
+type (
+	// Check returns nil when the component can serve.
+	Check func(ctx context.Context) error
+
+	Component struct {
+		Status string `json:"status" example:"up"`
+		Error  string `json:"error,omitempty" example:"connection refused"`
+	}
+
+	// Report is up when every component is up.
+	Report struct {
+		Status     string               `json:"status" example:"up"`
+		Components map[string]Component `json:"components,omitempty"`
+	}
+
+	Checker struct {
+		timeout time.Duration
+		names   []string
+		checks  map[string]Check
+	}
+)
+
+// New creates a checker whose checks are canceled after the timeout.
+func New(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+		checks:  map[string]Check{},
+	}
+}
+
+// Add registers the check of a component, replacing a previous one of the same name.
+func (s *Checker) Add(name string, check Check) {
+	if _, ok := s.checks[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.checks[name] = check
+}
+
+// Run runs every check concurrently and reports the status of each component.
+func (s *Checker) Run(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	report := &Report{Status: StatusUp, Components: map[string]Component{}}
+	for _, name := range s.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			component := Component{Status: StatusUp}
+			if err := check(ctx); err != nil {
+				component = Component{Status: StatusDown, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[name] = component
+			if component.Status == StatusDown {
+				report.Status = StatusDown
+			}
+		}(name, s.checks[name])
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (s *Report) Up() bool {
+	return s.Status == StatusUp
+}
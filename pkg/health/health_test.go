@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestCheckerUp
+func TestCheckerUp(t *testing.T) {
+	checker := New(time.Second)
+	checker.Add("database", func(context.Context) error { return nil })
+	checker.Add("keys", func(context.Context) error { return nil })
+
+	report := checker.Run(context.Background())
+	assert.True(t, report.Up())
+	assert.Equal(t, map[string]Component{"database": {Status: StatusUp}, "keys": {Status: StatusUp}}, report.Components)
+}
+
+// go test -run TestCheckerDown
+func TestCheckerDown(t *testing.T) {
+	checker := New(time.Second)
+	checker.Add("database", func(context.Context) error { return errors.New("connection refused") })
+	checker.Add("keys", func(context.Context) error { return nil })
+
+	report := checker.Run(context.Background())
+	assert.False(t, report.Up())
+	assert.Equal(t, Component{Status: StatusDown, Error: "connection refused"}, report.Components["database"])
+	assert.Equal(t, Component{Status: StatusUp}, report.Components["keys"])
+}
+
+// go test -run TestCheckerTimeout
+func TestCheckerTimeout(t *testing.T) {
+	checker := New(10 * time.Millisecond)
+	checker.Add("database", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Run(context.Background())
+	assert.False(t, report.Up())
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Components["database"].Error)
+}
+
+// go test -run TestCheckerWithoutChecks
+func TestCheckerWithoutChecks(t *testing.T) {
+	report := New(time.Second).Run(context.Background())
+	assert.True(t, report.Up())
+	assert.Empty(t, report.Components)
+}
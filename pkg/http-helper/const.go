@@ -1,12 +1,14 @@
 package httphelper
 
 const (
-	LocalObject   string = "localObject"
-	LocalUser     string = "localUser"
-	LocalLang     string = "localLang"
-	LocalDTO      string = "localDTO"
-	LocalFilter   string = "localFilter"
-	LocalInterval string = "localInterval"
-	ParamID       string = "id"
-	ParamMail     string = "email"
+	LocalObject    string = "localObject"
+	LocalUser      string = "localUser"
+	LocalToken     string = "localToken"
+	LocalLang      string = "localLang"
+	LocalDTO       string = "localDTO"
+	LocalFilter    string = "localFilter"
+	LocalInterval  string = "localInterval"
+	LocalRequestID string = "requestid"
+	ParamID        string = "id"
+	ParamMail      string = "email"
 )
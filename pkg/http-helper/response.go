@@ -1,15 +1,113 @@
 package httphelper
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
 
-type HTTPResponse struct {
-	Code    int    `json:"code" example:"400"`
-	Message string `json:"message" example:"status bad request"`
+	"github.com/gofiber/fiber/v2"
+	"github.com/raulaguila/go-pass/pkg/validator"
+)
+
+const (
+	MIMEProblemJSON string = "application/problem+json"
+
+	// The type of the problems is this prefix followed by their code.
+	problemTypePrefix string = "urn:go-pass:problem:"
+)
+
+type (
+	// Problem is an RFC 7807 error response. The code is stable across languages, for the clients to branch on, while
+	// the title and the detail are localized.
+	Problem struct {
+		Type      string       `json:"type" example:"urn:go-pass:problem:user_not_found"`
+		Title     string       `json:"title" example:"Not found"`
+		Status    int          `json:"status" example:"404"`
+		Detail    string       `json:"detail" example:"User not found."`
+		Instance  string       `json:"instance" example:"/user/1"`
+		Code      string       `json:"code" example:"user_not_found"`
+		RequestID string       `json:"request_id,omitempty" example:"0b8f9d6e-3c1a-4d8e-9b5e-1f2a3b4c5d6e"`
+		Errors    []FieldError `json:"errors,omitempty"`
+	}
+
+	// FieldError is a validation rule broken by a field of the request.
+	FieldError struct {
+		Field   string `json:"field" example:"Email"`
+		Rule    string `json:"rule" example:"email"`
+		Param   string `json:"param,omitempty" example:""`
+		Message string `json:"message" example:"Email does not meet the 'email[]' requirement with value 'invalid'"`
+	}
+
+	// coder is implemented by the errors with a code, like the translated ones.
+	coder interface {
+		Code() string
+	}
+
+	// titler localizes the titles of the problems, it is implemented by the translation of the request.
+	titler interface {
+		Title(status int) string
+	}
+
+	// violationTranslator localizes the messages of the validation rules, it is implemented by the translation of the
+	// request.
+	violationTranslator interface {
+		Violation(err *validator.ValidatorError) string
+	}
+)
+
+// statusCode returns the snake case of the status text, the code of the errors without their own, like not_found.
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// NewProblem describes the error with the status, the title is localized by the language of the request.
+func NewProblem(c *fiber.Ctx, status int, err error) *Problem {
+	problem := &Problem{
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: c.Path(),
+		Code:     statusCode(status),
+	}
+
+	if translation, ok := c.Locals(LocalLang).(titler); ok {
+		problem.Title = translation.Title(status)
+	}
+	if requestID, ok := c.Locals(LocalRequestID).(string); ok {
+		problem.RequestID = requestID
+	}
+
+	var coded coder
+	if errors.As(err, &coded) {
+		problem.Code = coded.Code()
+	}
+
+	problem.Type = problemTypePrefix + problem.Code
+	return problem
 }
 
+// NewHTTPResponse sends the error as a problem with the status.
 func NewHTTPResponse(c *fiber.Ctx, status int, err error) error {
-	return c.Status(status).JSON(&HTTPResponse{
-		Code:    status,
-		Message: err.Error(),
-	})
+	return c.Status(status).JSON(NewProblem(c, status, err), MIMEProblemJSON)
+}
+
+// NewValidationResponse sends the violations of the fields as a bad request, the detail summarizes them.
+func NewValidationResponse(c *fiber.Ctx, detail error, violations validator.ValidationErrors) error {
+	problem := NewProblem(c, fiber.StatusBadRequest, detail)
+	translation, translated := c.Locals(LocalLang).(violationTranslator)
+	for _, violation := range violations {
+		message := violation.Error()
+		if translated {
+			message = translation.Violation(violation)
+		}
+		problem.Errors = append(problem.Errors, FieldError{Field: violation.Field(), Rule: violation.Tag(), Param: violation.Param(), Message: message})
+	}
+
+	return c.Status(fiber.StatusBadRequest).JSON(problem, MIMEProblemJSON)
+}
+
+// LogError logs the unexpected error of the request, with the attributes of its context like the request id.
+func LogError(c *fiber.Ctx, err error) {
+	slog.ErrorContext(c.UserContext(), "request failed", "route", c.Route().Path, "error", err)
 }
@@ -0,0 +1,63 @@
+package keyring
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+)
+
+// Generate creates a private key of the algorithm, the RSA keys have 3072 bits.
+func Generate(algorithm string) (*Key, error) {
+	switch algorithm {
+	case AlgRS256:
+		private, err := rsa.GenerateKey(rand.Reader, 3072)
+		if err != nil {
+			return nil, err
+		}
+		return newKey(private, private.Public())
+	case AlgES256:
+		private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return newKey(private, private.Public())
+	case AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return newKey(private, private.Public())
+	}
+
+	return nil, fmt.Errorf("%w: algorithm %v", ErrUnsupportedKey, algorithm)
+}
+
+// PrivateBase64 encodes the private key like the FromBase64 lists, as a base64 PKCS #8 PEM.
+func (k *Key) PrivateBase64() (string, error) {
+	if k.private == nil {
+		return "", ErrNoSigningKey
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(k.private)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// PublicBase64 encodes the public key like the FromBase64 lists, as a base64 PKIX PEM.
+func (k *Key) PublicBase64() (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(k.public)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
@@ -0,0 +1,230 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgRS256 string = "RS256"
+	AlgES256 string = "ES256"
+	AlgEdDSA string = "EdDSA"
+)
+
+var (
+	ErrUnsupportedKey = errors.New("unsupported key type")
+	ErrNoSigningKey   = errors.New("keyring has no signing key")
+	ErrUnknownKey     = errors.New("unknown key id")
+)
+
+type (
+	Key struct {
+		ID        string
+		Algorithm string
+		private   crypto.Signer
+		public    crypto.PublicKey
+	}
+
+	// Keyring holds every key accepted for verification, the first private key signs new tokens.
+	Keyring struct {
+		active *Key
+		keys   map[string]*Key
+		order  []string
+	}
+)
+
+func algorithmFor(public crypto.PublicKey) (string, error) {
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return AlgRS256, nil
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return "", fmt.Errorf("%w: curve %v", ErrUnsupportedKey, pub.Curve.Params().Name)
+		}
+		return AlgES256, nil
+	case ed25519.PublicKey:
+		return AlgEdDSA, nil
+	}
+
+	return "", ErrUnsupportedKey
+}
+
+func newKey(private crypto.Signer, public crypto.PublicKey) (*Key, error) {
+	alg, err := algorithmFor(public)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{Algorithm: alg, private: private, public: public}
+	key.ID, err = thumbprint(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func parsePrivate(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	return signer, nil
+}
+
+func parsePublic(block *pem.Block) (crypto.PublicKey, error) {
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// ParsePEM parses a private or a public key, public keys only verify tokens.
+func ParsePEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("could not decode pem block")
+	}
+
+	if strings.Contains(block.Type, "PRIVATE KEY") {
+		private, err := parsePrivate(block)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse key: %v", err.Error())
+		}
+		return newKey(private, private.Public())
+	}
+
+	public, err := parsePublic(block)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse key: %v", err.Error())
+	}
+	return newKey(nil, public)
+}
+
+func New(keys ...*Key) *Keyring {
+	ring := &Keyring{keys: map[string]*Key{}}
+	for _, key := range keys {
+		ring.Add(key)
+	}
+
+	return ring
+}
+
+// FromBase64 builds a keyring from comma-separated lists of base64 encoded PEM keys.
+func FromBase64(lists ...string) (*Keyring, error) {
+	ring := New()
+	for _, list := range lists {
+		for _, value := range strings.Split(list, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode key: %v", err.Error())
+			}
+
+			key, err := ParsePEM(decoded)
+			if err != nil {
+				return nil, err
+			}
+			ring.Add(key)
+		}
+	}
+
+	if ring.active == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	return ring, nil
+}
+
+// Add registers the key, the first private key added becomes the signing key.
+func (s *Keyring) Add(key *Key) {
+	if existing, ok := s.keys[key.ID]; ok {
+		if existing.private == nil && key.private != nil {
+			existing.private = key.private
+		}
+	} else {
+		s.keys[key.ID] = key
+		s.order = append(s.order, key.ID)
+	}
+
+	if s.active == nil && key.private != nil {
+		s.active = s.keys[key.ID]
+	}
+}
+
+func (s *Keyring) Active() *Key {
+	return s.active
+}
+
+func (s *Keyring) Keys() []*Key {
+	keys := make([]*Key, 0, len(s.order))
+	for _, id := range s.order {
+		keys = append(keys, s.keys[id])
+	}
+
+	return keys
+}
+
+// Sign signs the claims with the active key, identifying it in the 'kid' header.
+func (s *Keyring) Sign(claims jwt.Claims) (string, error) {
+	if s.active == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(s.active.Algorithm), claims)
+	token.Header["kid"] = s.active.ID
+	return token.SignedString(s.active.private)
+}
+
+// Keyfunc selects the verification key by the token 'kid', tokens without it use the active key.
+func (s *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	key := s.active
+	if kid, ok := token.Header["kid"].(string); ok {
+		if key, ok = s.keys[kid]; !ok {
+			return nil, ErrUnknownKey
+		}
+	}
+
+	if key == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
+	}
+
+	return key.public, nil
+}
+
+func (s *Keyring) Parse(tokenString string, claims jwt.Claims, options ...jwt.ParserOption) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, s.Keyfunc, append(options, jwt.WithValidMethods([]string{AlgRS256, AlgES256, AlgEdDSA}))...)
+}
@@ -0,0 +1,168 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateKeys(t *testing.T) map[string]crypto.Signer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nil(t, err)
+
+	return map[string]crypto.Signer{AlgRS256: rsaKey, AlgES256: ecKey, AlgEdDSA: edKey}
+}
+
+func privatePEM(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t, err)
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func publicPEM(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	assert.Nil(t, err)
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// go test -run TestKeyringSignAndParse
+func TestKeyringSignAndParse(t *testing.T) {
+	for alg, key := range generateKeys(t) {
+		ring, err := FromBase64(privatePEM(t, key))
+		assert.Nil(t, err)
+		assert.Equal(t, alg, ring.Active().Algorithm)
+
+		signed, err := ring.Sign(jwt.MapClaims{"token": "value"})
+		assert.Nil(t, err)
+
+		claims := jwt.MapClaims{}
+		token, err := ring.Parse(signed, claims)
+		assert.Nil(t, err, alg)
+		assert.True(t, token.Valid)
+		assert.Equal(t, ring.Active().ID, token.Header["kid"])
+		assert.Equal(t, "value", claims["token"])
+	}
+}
+
+// go test -run TestKeyringRotation
+func TestKeyringRotation(t *testing.T) {
+	keys := generateKeys(t)
+
+	oldRing, err := FromBase64(privatePEM(t, keys[AlgRS256]))
+	assert.Nil(t, err)
+	signed, err := oldRing.Sign(jwt.MapClaims{})
+	assert.Nil(t, err)
+
+	// The new key signs, the old one only verifies.
+	ring, err := FromBase64(privatePEM(t, keys[AlgEdDSA]), publicPEM(t, keys[AlgRS256]))
+	assert.Nil(t, err)
+	assert.Equal(t, AlgEdDSA, ring.Active().Algorithm)
+	assert.Len(t, ring.Keys(), 2)
+
+	_, err = ring.Parse(signed, jwt.MapClaims{})
+	assert.Nil(t, err)
+
+	// Tokens of removed keys are rejected.
+	ring, err = FromBase64(privatePEM(t, keys[AlgES256]))
+	assert.Nil(t, err)
+	_, err = ring.Parse(signed, jwt.MapClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+// go test -run TestKeyringWithoutSigningKey
+func TestKeyringWithoutSigningKey(t *testing.T) {
+	_, err := FromBase64(publicPEM(t, generateKeys(t)[AlgES256]))
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	_, err = FromBase64("")
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	_, err = FromBase64("not base64")
+	assert.NotNil(t, err)
+}
+
+// go test -run TestKeyringJWKS
+func TestKeyringJWKS(t *testing.T) {
+	keys := generateKeys(t)
+	ring, err := FromBase64(privatePEM(t, keys[AlgRS256]), publicPEM(t, keys[AlgES256])+","+publicPEM(t, keys[AlgEdDSA]))
+	assert.Nil(t, err)
+
+	set := ring.JWKS()
+	assert.Len(t, set.Keys, 3)
+
+	kty := map[string]string{}
+	for _, jwk := range set.Keys {
+		assert.NotEmpty(t, jwk.Kid)
+		assert.Equal(t, "sig", jwk.Use)
+		kty[jwk.Alg] = jwk.Kty
+	}
+	assert.Equal(t, map[string]string{AlgRS256: "RSA", AlgES256: "EC", AlgEdDSA: "OKP"}, kty)
+}
+
+// go test -run TestThumbprint
+func TestThumbprint(t *testing.T) {
+	// Example from RFC 7638, section 3.1.
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	assert.Nil(t, err)
+
+	key, err := newKey(nil, &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537})
+	assert.Nil(t, err)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", key.ID)
+}
+
+// go test -run TestGenerate
+func TestGenerate(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgES256, AlgEdDSA} {
+		key, err := Generate(alg)
+		assert.Nil(t, err, alg)
+		assert.Equal(t, alg, key.Algorithm)
+
+		private, err := key.PrivateBase64()
+		assert.Nil(t, err)
+		public, err := key.PublicBase64()
+		assert.Nil(t, err)
+
+		ring, err := FromBase64(private)
+		assert.Nil(t, err)
+		assert.Equal(t, key.ID, ring.Active().ID)
+
+		signed, err := ring.Sign(jwt.MapClaims{"token": "value"})
+		assert.Nil(t, err)
+
+		verifier := New()
+		parsed, err := ParsePEM(mustDecode(t, public))
+		assert.Nil(t, err)
+		verifier.Add(parsed)
+		_, err = verifier.Parse(signed, jwt.MapClaims{})
+		assert.Nil(t, err, alg)
+
+		_, err = parsed.PrivateBase64()
+		assert.Equal(t, ErrNoSigningKey, err)
+	}
+
+	_, err := Generate("HS256")
+	assert.ErrorIs(t, err, ErrUnsupportedKey)
+}
+
+func mustDecode(t *testing.T, value string) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	assert.Nil(t, err)
+	return decoded
+}
@@ -0,0 +1,48 @@
+package ldapauth
+
+import (
+	"strings"
+)
+
+type (
+	GroupProfile struct {
+		Group   string
+		Profile string
+	}
+
+	// GroupMap maps directory groups to profiles, the first matching group wins.
+	GroupMap []GroupProfile
+)
+
+// ParseGroupMap parses a list like go-pass-admins:ADMIN,go-pass-users:USER.
+func ParseGroupMap(raw string) (GroupMap, error) {
+	var groupMap GroupMap
+	for _, item := range strings.Split(raw, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		group, profile, ok := strings.Cut(item, ":")
+		group, profile = strings.TrimSpace(group), strings.TrimSpace(profile)
+		if !ok || group == "" || profile == "" {
+			return nil, ErrInvalidGroupMap
+		}
+
+		groupMap = append(groupMap, GroupProfile{Group: group, Profile: profile})
+	}
+
+	return groupMap, nil
+}
+
+// Profile returns the profile of the groups, empty when no group is mapped.
+func (s GroupMap) Profile(groups []string) string {
+	for _, item := range s {
+		for _, group := range groups {
+			if strings.EqualFold(item.Group, group) {
+				return item.Profile
+			}
+		}
+	}
+
+	return ""
+}
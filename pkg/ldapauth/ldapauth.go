@@ -0,0 +1,232 @@
+package ldapauth
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid directory credentials")
+	ErrInvalidGroupMap    = errors.New("invalid group map, expected group:profile,group:profile")
+	ErrUserBaseNotFound   = errors.New("user base DN not found")
+)
+
+type (
+	Config struct {
+		URL      string
+		StartTLS bool
+		TLS      *tls.Config
+		Timeout  time.Duration
+
+		// Service account used to search the directory, empty for anonymous searches.
+		BindDN       string
+		BindPassword string
+
+		UserBaseDN string
+		// Filter with a %s replaced by the escaped login, like (mail=%s).
+		UserFilter string
+		MailAttr   string
+		NameAttr   string
+
+		// Groups are read from the memberOf attribute, and searched too when GroupBaseDN is set.
+		GroupBaseDN string
+		// Filter with a %s replaced by the escaped user DN, like (member=%s).
+		GroupFilter string
+	}
+
+	Entry struct {
+		DN     string
+		Email  string
+		Name   string
+		Groups []string
+	}
+
+	// Directory authenticates users by binding with their DN and password.
+	Directory struct {
+		config Config
+	}
+)
+
+func New(config Config) *Directory {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.UserFilter == "" {
+		config.UserFilter = "(mail=%s)"
+	}
+	if config.MailAttr == "" {
+		config.MailAttr = "mail"
+	}
+	if config.NameAttr == "" {
+		config.NameAttr = "cn"
+	}
+	if config.GroupFilter == "" {
+		config.GroupFilter = "(member=%s)"
+	}
+	if config.TLS == nil {
+		config.TLS = &tls.Config{}
+		if parsed, err := url.Parse(config.URL); err == nil {
+			config.TLS.ServerName = parsed.Hostname()
+		}
+	}
+
+	return &Directory{config: config}
+}
+
+// connect opens a connection bound with the service account.
+func (s *Directory) connect() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(s.config.URL, ldap.DialWithTLSConfig(s.config.TLS))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(s.config.Timeout)
+
+	if s.config.StartTLS {
+		if err := conn.StartTLS(s.config.TLS); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if s.config.BindDN == "" {
+		err = conn.UnauthenticatedBind("")
+	} else {
+		err = conn.Bind(s.config.BindDN, s.config.BindPassword)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("could not bind the service account: %v", err.Error())
+	}
+
+	return conn, nil
+}
+
+func (s *Directory) search(conn *ldap.Conn, filter string) ([]*ldap.Entry, error) {
+	result, err := conn.SearchWithPaging(ldap.NewSearchRequest(
+		s.config.UserBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		filter, []string{s.config.MailAttr, s.config.NameAttr, "memberOf"}, nil,
+	), 500)
+	if err != nil {
+		// A missing base is a misconfiguration, not a directory without users.
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, fmt.Errorf("%w: %v", ErrUserBaseNotFound, s.config.UserBaseDN)
+		}
+		return nil, err
+	}
+
+	return result.Entries, nil
+}
+
+func (s *Directory) entry(conn *ldap.Conn, entry *ldap.Entry) (*Entry, error) {
+	user := &Entry{
+		DN:    entry.DN,
+		Email: entry.GetAttributeValue(s.config.MailAttr),
+		Name:  entry.GetAttributeValue(s.config.NameAttr),
+	}
+
+	groups := entry.GetAttributeValues("memberOf")
+	if s.config.GroupBaseDN != "" {
+		result, err := conn.SearchWithPaging(ldap.NewSearchRequest(
+			s.config.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+			fmt.Sprintf(s.config.GroupFilter, ldap.EscapeFilter(entry.DN)), []string{"dn"}, nil,
+		), 500)
+		if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, err
+		}
+		if result != nil {
+			for _, group := range result.Entries {
+				groups = append(groups, group.DN)
+			}
+		}
+	}
+
+	for _, group := range groups {
+		if name := groupName(group); name != "" {
+			user.Groups = append(user.Groups, name)
+		}
+	}
+
+	return user, nil
+}
+
+// Authenticate binds as the user found by the login and returns the user with its groups.
+func (s *Directory) Authenticate(login, password string) (*Entry, error) {
+	// An empty password makes an unauthenticated bind, which succeeds on most servers.
+	if login == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	entries, err := s.search(conn, fmt.Sprintf(s.config.UserFilter, ldap.EscapeFilter(login)))
+	// Some servers answer the filters matching nothing with no such object, failing the login like an unknown user.
+	if errors.Is(err, ErrUserBaseNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := conn.Bind(entries[0].DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	// Groups may be hidden from the user, so they are read with the service account.
+	if s.config.BindDN != "" {
+		if err := conn.Bind(s.config.BindDN, s.config.BindPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.entry(conn, entries[0])
+}
+
+// Users lists every user matched by the user filter.
+func (s *Directory) Users() ([]*Entry, error) {
+	conn, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	entries, err := s.search(conn, fmt.Sprintf(s.config.UserFilter, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*Entry, 0, len(entries))
+	for _, entry := range entries {
+		user, err := s.entry(conn, entry)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// groupName returns the value of the first RDN, like admins for cn=admins,ou=groups,dc=example,dc=org.
+func groupName(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+		return ""
+	}
+
+	return parsed.RDNs[0].Attributes[0].Value
+}
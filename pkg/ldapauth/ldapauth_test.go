@@ -0,0 +1,136 @@
+package ldapauth
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jimlambrt/gldap"
+	"github.com/jimlambrt/gldap/testdirectory"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testUserDN  string = "ou=people,dc=example,dc=org"
+	testGroupDN string = "ou=groups,dc=example,dc=org"
+)
+
+func newTestDirectory(t *testing.T) *Directory {
+	td := testdirectory.Start(t, testdirectory.WithNoTLS(t), testdirectory.WithDefaults(t, &testdirectory.Defaults{
+		UserDN:  testUserDN,
+		GroupDN: testGroupDN,
+	}))
+
+	td.SetUsers(
+		gldap.NewEntry("cn=service,dc=example,dc=org", map[string][]string{"password": {"service-pass"}}),
+		gldap.NewEntry("uid=alice,"+testUserDN, map[string][]string{
+			"mail":     {"alice@example.com"},
+			"cn":       {"Alice Smith"},
+			"password": {"alice-pass"},
+		}),
+		gldap.NewEntry("uid=bob,"+testUserDN, map[string][]string{
+			"mail":     {"bob@example.com"},
+			"cn":       {"Bob Jones"},
+			"password": {"bob-pass"},
+			"memberOf": {"cn=users," + testGroupDN},
+		}),
+	)
+	td.SetGroups(gldap.NewEntry("cn=admins,"+testGroupDN, map[string][]string{"member": {"uid=alice," + testUserDN}}))
+
+	return New(Config{
+		URL:          fmt.Sprintf("ldap://%v:%v", td.Host(), td.Port()),
+		BindDN:       "cn=service,dc=example,dc=org",
+		BindPassword: "service-pass",
+		UserBaseDN:   testUserDN,
+		UserFilter:   "(uid=%s)",
+		GroupBaseDN:  testGroupDN,
+	})
+}
+
+// go test -run TestAuthenticate
+func TestAuthenticate(t *testing.T) {
+	directory := newTestDirectory(t)
+
+	user, err := directory.Authenticate("alice", "alice-pass")
+	assert.Nil(t, err)
+	assert.Equal(t, &Entry{DN: "uid=alice," + testUserDN, Email: "alice@example.com", Name: "Alice Smith", Groups: []string{"admins"}}, user)
+
+	user, err = directory.Authenticate("bob", "bob-pass")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"users"}, user.Groups)
+}
+
+// go test -run TestAuthenticateInvalidCredentials
+func TestAuthenticateInvalidCredentials(t *testing.T) {
+	directory := newTestDirectory(t)
+
+	for _, credentials := range [][2]string{
+		{"alice", "wrong-pass"},
+		{"alice", ""},
+		{"carol", "alice-pass"},
+		{"", "alice-pass"},
+	} {
+		_, err := directory.Authenticate(credentials[0], credentials[1])
+		assert.ErrorIs(t, err, ErrInvalidCredentials, credentials[0])
+	}
+}
+
+// go test -run TestUsers
+func TestUsers(t *testing.T) {
+	directory := newTestDirectory(t)
+
+	users, err := directory.Users()
+	assert.Nil(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "alice@example.com", users[0].Email)
+	assert.Equal(t, []string{"admins"}, users[0].Groups)
+	assert.Equal(t, "bob@example.com", users[1].Email)
+}
+
+// go test -run TestUsersMissingBase
+func TestUsersMissingBase(t *testing.T) {
+	mux, err := gldap.NewMux()
+	assert.Nil(t, err)
+	assert.Nil(t, mux.Bind(func(w *gldap.ResponseWriter, r *gldap.Request) {
+		_ = w.Write(r.NewBindResponse(gldap.WithResponseCode(gldap.ResultSuccess)))
+	}))
+	assert.Nil(t, mux.Search(func(w *gldap.ResponseWriter, r *gldap.Request) {
+		_ = w.Write(r.NewSearchDoneResponse(gldap.WithResponseCode(gldap.ResultNoSuchObject)))
+	}))
+
+	server, err := gldap.NewServer()
+	assert.Nil(t, err)
+	assert.Nil(t, server.Router(mux))
+	port := testdirectory.FreePort(t)
+	go func() { _ = server.Run(fmt.Sprintf("127.0.0.1:%v", port)) }()
+	t.Cleanup(func() { _ = server.Stop() })
+	for !server.Ready() {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	directory := New(Config{URL: fmt.Sprintf("ldap://127.0.0.1:%v", port), UserBaseDN: "ou=missing,dc=example,dc=org"})
+
+	_, err = directory.Users()
+	assert.ErrorIs(t, err, ErrUserBaseNotFound)
+
+	_, err = directory.Authenticate("alice", "alice-pass")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+// go test -run TestGroupMap
+func TestGroupMap(t *testing.T) {
+	groupMap, err := ParseGroupMap(" admins:ADMIN, users:USER,")
+	assert.Nil(t, err)
+	assert.Equal(t, GroupMap{{Group: "admins", Profile: "ADMIN"}, {Group: "users", Profile: "USER"}}, groupMap)
+
+	assert.Equal(t, "ADMIN", groupMap.Profile([]string{"users", "Admins"}))
+	assert.Equal(t, "USER", groupMap.Profile([]string{"users"}))
+	assert.Equal(t, "", groupMap.Profile([]string{"others"}))
+	assert.Equal(t, "", groupMap.Profile(nil))
+
+	_, err = ParseGroupMap("admins")
+	assert.ErrorIs(t, err, ErrInvalidGroupMap)
+
+	_, err = ParseGroupMap(":ADMIN")
+	assert.ErrorIs(t, err, ErrInvalidGroupMap)
+}
@@ -0,0 +1,113 @@
+// Package logging configures the slog loggers, with the attributes carried by the context and the sensitive values redacted.
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	FormatJSON string = "json"
+	FormatText string = "text"
+
+	// Redacted replaces the values of the sensitive attributes.
+	Redacted string = "[REDACTED]"
+)
+
+var (
+	ErrInvalidLevel  = errors.New("invalid log level, use debug, info, warn or error")
+	ErrInvalidFormat = errors.New("invalid log format, use json or text")
+)
+
+// The attributes whose keys contain one of these words are redacted, whatever the group.
+var sensitiveKeys = []string{"password", "passw", "secret", "token", "authorization", "cookie"}
+
+type contextKey struct{}
+
+// ParseLevel parses the level names of slog, ignoring the case.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, ErrInvalidLevel
+	}
+
+	return level, nil
+}
+
+// New creates a logger writing in the format, json or text, that adds the attributes of the context to the records.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: redact}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, ErrInvalidFormat
+	}
+
+	return slog.New(&contextHandler{handler}), nil
+}
+
+// Sensitive reports whether the values of the key must not be logged.
+func Sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range sensitiveKeys {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func redact(_ []string, attr slog.Attr) slog.Attr {
+	if Sensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	return attr
+}
+
+// With returns a copy of the context carrying the attributes, added to the records logged with it.
+func With(ctx context.Context, args ...any) context.Context {
+	attrs := slog.Group("", args...).Value.Group()
+	return context.WithValue(ctx, contextKey{}, append(Attrs(ctx), attrs...))
+}
+
+// Attrs returns the attributes carried by the context.
+func Attrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	attrs, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	return attrs[:len(attrs):len(attrs)]
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	record.AddAttrs(Attrs(ctx)...)
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestNew
+func TestNew(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "verbose", FormatJSON)
+	assert.Equal(t, ErrInvalidLevel, err)
+
+	_, err = New(&bytes.Buffer{}, "info", "xml")
+	assert.Equal(t, ErrInvalidFormat, err)
+
+	output := &bytes.Buffer{}
+	logger, err := New(output, "WARN", FormatText)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+	assert.NotContains(t, output.String(), "hidden")
+	assert.Contains(t, output.String(), "level=WARN msg=shown")
+}
+
+// go test -run TestContextAttrs
+func TestContextAttrs(t *testing.T) {
+	output := &bytes.Buffer{}
+	logger, err := New(output, "debug", FormatJSON)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	ctx := With(context.Background(), "request_id", "abc")
+	child := With(ctx, "user_id", 1)
+	logger.InfoContext(child, "request", "status", 200)
+
+	record := map[string]any{}
+	assert.Nil(t, json.Unmarshal(output.Bytes(), &record))
+	assert.Equal(t, "abc", record["request_id"])
+	assert.Equal(t, float64(1), record["user_id"])
+	assert.Equal(t, float64(200), record["status"])
+
+	// The parent context keeps its own attributes.
+	assert.Len(t, Attrs(ctx), 1)
+	assert.Empty(t, Attrs(context.Background()))
+}
+
+// go test -run TestRedact
+func TestRedact(t *testing.T) {
+	output := &bytes.Buffer{}
+	logger, err := New(output, "info", FormatJSON)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	ctx := With(context.Background(), "access_token", "eyJ")
+	logger.InfoContext(ctx, "login", "Authorization", "Bearer eyJ", slog.Group("user", "password", "secret", "mail", "admin@admin.com"))
+
+	assert.NotContains(t, output.String(), "eyJ")
+	assert.NotContains(t, output.String(), `"secret"`)
+	assert.Contains(t, output.String(), `"access_token":"`+Redacted+`"`)
+	assert.Contains(t, output.String(), `"Authorization":"`+Redacted+`"`)
+	assert.Contains(t, output.String(), `"user":{"password":"`+Redacted+`","mail":"admin@admin.com"}`)
+}
@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+)
+
+// Log writes the messages instead of delivering them, for development.
+type Log struct {
+	mu     sync.Mutex
+	from   string
+	writer io.Writer
+}
+
+func NewLog(writer io.Writer, from string) *Log {
+	return &Log{writer: writer, from: from}
+}
+
+// NewFile appends the messages to the file.
+func NewFile(path, from string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLog(file, from), nil
+}
+
+func (s *Log) Send(_ context.Context, message *Message) error {
+	data, err := message.encode(s.from, true)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.writer.Write(append(data, []byte("\r\n\r\n")...))
+	return err
+}
@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+var ErrInvalidMessage error = errors.New("invalid message, expected a recipient, a subject and no line breaks in the headers")
+
+type (
+	Message struct {
+		To      []string
+		Subject string
+		Text    string
+	}
+
+	// Sender delivers messages, like the SMTP server or the log used in development.
+	Sender interface {
+		Send(context.Context, *Message) error
+	}
+)
+
+func (s *Message) validate() error {
+	if len(s.To) == 0 || s.Subject == "" || strings.ContainsAny(s.Subject, "\r\n") {
+		return ErrInvalidMessage
+	}
+
+	for _, to := range s.To {
+		if strings.ContainsAny(to, "\r\n") {
+			return ErrInvalidMessage
+		}
+		if _, err := mail.ParseAddress(to); err != nil {
+			return ErrInvalidMessage
+		}
+	}
+
+	return nil
+}
+
+// encode renders the message as a RFC 5322 plain text UTF-8 mail, raw keeps the text readable for the logs.
+func (s *Message) encode(from string, raw bool) ([]byte, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "From: %v\r\n", from)
+	fmt.Fprintf(&buffer, "To: %v\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&buffer, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", s.Subject))
+	fmt.Fprintf(&buffer, "Date: %v\r\n", time.Now().Format(time.RFC1123Z))
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	if raw {
+		buffer.WriteString("\r\n" + s.Text)
+		return buffer.Bytes(), nil
+	}
+	buffer.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	writer := quotedprintable.NewWriter(&buffer)
+	if _, err := io.WriteString(writer, strings.ReplaceAll(s.Text, "\n", "\r\n")); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,113 @@
+package mailer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSMTP accepts one mail and returns the received commands and data.
+func fakeSMTP(t *testing.T) (string, string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var transcript strings.Builder
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		for data := false; ; {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			transcript.WriteString(line)
+
+			switch {
+			case data:
+				if line == ".\r\n" {
+					data = false
+					reply("250 OK")
+				}
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 localhost")
+			case strings.HasPrefix(line, "DATA"):
+				data = true
+				reply("354 go ahead")
+			case strings.HasPrefix(line, "QUIT"):
+				reply("221 bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+		received <- transcript.String()
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port, received
+}
+
+// go test -run TestSMTPSend
+func TestSMTPSend(t *testing.T) {
+	host, port, received := fakeSMTP(t)
+	sender := NewSMTP(SMTPConfig{Host: host, Port: port, From: "go-pass@example.com"})
+
+	err := sender.Send(context.Background(), &Message{
+		To:      []string{"john@example.com"},
+		Subject: "Convite de acesso",
+		Text:    "Open https://example.com/invite?token=abc\n",
+	})
+	assert.Nil(t, err)
+
+	transcript := <-received
+	assert.Contains(t, transcript, "MAIL FROM:<go-pass@example.com>")
+	assert.Contains(t, transcript, "RCPT TO:<john@example.com>")
+	assert.Contains(t, transcript, "Subject: Convite de acesso")
+	assert.Contains(t, transcript, "token=3Dabc")
+
+	data, err := (&Message{To: []string{"john@example.com"}, Subject: "Redefinição de senha"}).encode("go-pass@example.com", false)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "Subject: =?utf-8?q?Redefini=C3=A7=C3=A3o_de_senha?=")
+}
+
+// go test -run TestLogSend
+func TestLogSend(t *testing.T) {
+	var buffer bytes.Buffer
+	sender := NewLog(&buffer, "go-pass@example.com")
+
+	err := sender.Send(context.Background(), &Message{To: []string{"john@example.com"}, Subject: "Invite", Text: "https://example.com/invite?token=abc"})
+	assert.Nil(t, err)
+	assert.Contains(t, buffer.String(), "To: john@example.com\r\n")
+	assert.Contains(t, buffer.String(), "https://example.com/invite?token=abc")
+}
+
+// go test -run TestInvalidMessage
+func TestInvalidMessage(t *testing.T) {
+	sender := NewLog(&bytes.Buffer{}, "go-pass@example.com")
+
+	for _, message := range []*Message{
+		{Subject: "Invite"},
+		{To: []string{"john@example.com"}},
+		{To: []string{"john@example.com"}, Subject: "Invite\r\nBcc: eve@example.com"},
+		{To: []string{"john@example.com\r\nBcc: eve@example.com"}, Subject: "Invite"},
+		{To: []string{"not an email"}, Subject: "Invite"},
+	} {
+		assert.ErrorIs(t, sender.Send(context.Background(), message), ErrInvalidMessage)
+	}
+}
@@ -0,0 +1,310 @@
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Table records the applied versions.
+const Table string = "schema_migrations"
+
+var (
+	ErrInvalidName  error = errors.New("invalid migration file name, use <version>_<name>.up.sql or <version>_<name>.down.sql")
+	ErrDuplicated   error = errors.New("duplicated migration version")
+	ErrMissingUp    error = errors.New("migration without up script")
+	ErrIrreversible error = errors.New("migration without down script")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type (
+	// Migration is a pair of scripts, each one applied in a transaction with its version record.
+	Migration struct {
+		Version int64
+		Name    string
+		Up      string
+		Down    string
+	}
+
+	// Status is a migration with the time it was applied, nil when pending.
+	Status struct {
+		Migration
+		AppliedAt *time.Time
+	}
+
+	// Dialect holds the statements that differ between the databases.
+	Dialect struct {
+		// Lock and Unlock receive the lock ID, empty when the database has no advisory locks.
+		Lock   string
+		Unlock string
+		// CreateTable creates the versions table named by the %v verb.
+		CreateTable string
+		// Placeholder returns the placeholder of the nth argument, starting at 1.
+		Placeholder func(n int) string
+	}
+
+	Migrator struct {
+		db         *sql.DB
+		dialect    Dialect
+		migrations []Migration
+		lockID     int64
+	}
+)
+
+var (
+	// Postgres holds an advisory lock, keeping concurrent processes, like the replicas of the API, from migrating at once.
+	Postgres Dialect = Dialect{
+		Lock:        "SELECT pg_advisory_lock($1)",
+		Unlock:      "SELECT pg_advisory_unlock($1)",
+		CreateTable: "CREATE TABLE IF NOT EXISTS %v (version bigint PRIMARY KEY, name varchar(255) NOT NULL, applied_at timestamptz NOT NULL)",
+		Placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+	}
+
+	// SQLite has a single writer, each migration already runs in its own transaction.
+	SQLite Dialect = Dialect{
+		CreateTable: "CREATE TABLE IF NOT EXISTS %v (version integer PRIMARY KEY, name varchar(255) NOT NULL, applied_at datetime NOT NULL)",
+		Placeholder: func(int) string { return "?" },
+	}
+)
+
+// Load reads the migrations of the directory, sorted by version.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidName, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidName, entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicated, entry.Name())
+		}
+
+		script := &migration.Up
+		if match[3] == "down" {
+			script = &migration.Down
+		}
+		if *script != "" {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicated, entry.Name())
+		}
+		*script = string(data)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: %v_%v", ErrMissingUp, migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// New creates a migrator of the database with the statements of the dialect.
+func New(db *sql.DB, dialect Dialect, migrations []Migration) *Migrator {
+	id := fnv.New64a()
+	id.Write([]byte(Table))
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations, lockID: int64(id.Sum64())}
+}
+
+// locked runs fn holding the lock, when the dialect has one, on a single connection, creating the versions table when missing.
+func (m *Migrator) locked(ctx context.Context, fn func(*sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.dialect.Lock != "" {
+		if _, err := conn.ExecContext(ctx, m.dialect.Lock, m.lockID); err != nil {
+			return err
+		}
+		defer conn.ExecContext(context.Background(), m.dialect.Unlock, m.lockID)
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(m.dialect.CreateTable, Table)); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM "+Table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+// run executes the script and the version record in a transaction.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Up applies the pending migrations in order, returning the applied ones.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	done := []Migration{}
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			if err := m.run(ctx, conn, migration.Up, fmt.Sprintf("INSERT INTO %v (version, name, applied_at) VALUES (%v, %v, %v)", Table, m.dialect.Placeholder(1), m.dialect.Placeholder(2), m.dialect.Placeholder(3)), migration.Version, migration.Name, time.Now()); err != nil {
+				return fmt.Errorf("migration %v_%v: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down reverts the last steps applied migrations, newest first, returning the reverted ones.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	done := []Migration{}
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %v_%v", ErrIrreversible, migration.Version, migration.Name)
+			}
+
+			if err := m.run(ctx, conn, migration.Down, fmt.Sprintf("DELETE FROM %v WHERE version = %v", Table, m.dialect.Placeholder(1)), migration.Version); err != nil {
+				return fmt.Errorf("migration %v_%v: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Status lists every migration with its apply time.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	statuses := make([]Status, 0, len(m.migrations))
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := Status{Migration: migration}
+			if appliedAt, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Pending lists the migrations not applied yet, without locking nor creating the versions table, for the health
+// checks. It fails when the versions table is missing, like before the first migration.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []Migration{}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,133 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestLoad
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0010_accounts.up.sql":   {Data: []byte("CREATE TABLE accounts (id int);")},
+		"sql/0010_accounts.down.sql": {Data: []byte("DROP TABLE accounts;")},
+		"sql/0002_users.up.sql":      {Data: []byte("CREATE TABLE users (id int);")},
+		"sql/0001_baseline.up.sql":   {Data: []byte("SELECT 1;")},
+		"sql/0001_baseline.down.sql": {Data: []byte("SELECT 2;")},
+	}
+
+	migrations, err := Load(fsys, "sql")
+	assert.Nil(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "baseline", Up: "SELECT 1;", Down: "SELECT 2;"},
+		{Version: 2, Name: "users", Up: "CREATE TABLE users (id int);"},
+		{Version: 10, Name: "accounts", Up: "CREATE TABLE accounts (id int);", Down: "DROP TABLE accounts;"},
+	}, migrations)
+}
+
+// go test -run TestLoadErrors
+func TestLoadErrors(t *testing.T) {
+	tests := map[string]struct {
+		files fstest.MapFS
+		err   error
+	}{
+		"name": {
+			files: fstest.MapFS{"sql/baseline.up.sql": {Data: []byte("SELECT 1;")}},
+			err:   ErrInvalidName,
+		},
+		"extension": {
+			files: fstest.MapFS{"sql/0001_baseline.sql": {Data: []byte("SELECT 1;")}},
+			err:   ErrInvalidName,
+		},
+		"duplicated": {
+			files: fstest.MapFS{
+				"sql/0001_baseline.up.sql": {Data: []byte("SELECT 1;")},
+				"sql/0001_users.up.sql":    {Data: []byte("SELECT 2;")},
+			},
+			err: ErrDuplicated,
+		},
+		"down only": {
+			files: fstest.MapFS{"sql/0001_baseline.down.sql": {Data: []byte("SELECT 1;")}},
+			err:   ErrMissingUp,
+		},
+	}
+
+	for name, test := range tests {
+		_, err := Load(test.files, "sql")
+		assert.True(t, errors.Is(err, test.err), name)
+	}
+}
+
+// go test -run TestMigrator
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	assert.Nil(t, err)
+	defer db.Close()
+
+	migrations := []Migration{
+		{Version: 1, Name: "users", Up: "CREATE TABLE users (id integer PRIMARY KEY); CREATE INDEX idx_users ON users (id);", Down: "DROP TABLE users;"},
+		{Version: 2, Name: "accounts", Up: "CREATE TABLE accounts (id integer PRIMARY KEY);", Down: "DROP TABLE accounts;"},
+	}
+
+	_, err = New(db, SQLite, migrations).Pending(ctx)
+	assert.NotNil(t, err)
+
+	migrator := New(db, SQLite, migrations[:1])
+	done, err := migrator.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, migrations[:1], done)
+
+	migrator = New(db, SQLite, migrations)
+	statuses, err := migrator.Status(ctx)
+	assert.Nil(t, err)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+
+	pending, err := migrator.Pending(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, migrations[1:], pending)
+
+	done, err = migrator.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, migrations[1:], done)
+
+	done, err = migrator.Up(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, done)
+
+	pending, err = migrator.Pending(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, pending)
+
+	done, err = migrator.Down(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, migrations[1:], done)
+
+	_, err = db.Exec("SELECT * FROM accounts")
+	assert.NotNil(t, err)
+	_, err = db.Exec("SELECT * FROM users")
+	assert.Nil(t, err)
+
+	// A failed script is rolled back with its version record.
+	migrator = New(db, SQLite, append(migrations, Migration{Version: 3, Name: "broken", Up: "CREATE TABLE broken (id integer); INSERT INTO missing VALUES (1);"}))
+	_, err = migrator.Up(ctx)
+	assert.NotNil(t, err)
+	_, err = db.Exec("SELECT * FROM broken")
+	assert.NotNil(t, err)
+
+	statuses, err = migrator.Status(ctx)
+	assert.Nil(t, err)
+	assert.NotNil(t, statuses[1].AppliedAt)
+	assert.Nil(t, statuses[2].AppliedAt)
+
+	done, err = migrator.Down(ctx, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []Migration{migrations[1], migrations[0]}, done)
+}
@@ -0,0 +1,158 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// Time the user has to authenticate in the identity provider.
+const sessionLife time.Duration = 10 * time.Minute
+
+var (
+	ErrInvalidSession  = errors.New("invalid or expired login session")
+	ErrInvalidNonce    = errors.New("id token nonce does not match")
+	ErrMissingEmail    = errors.New("id token without email")
+	ErrUnverifiedEmail = errors.New("id token email is not verified")
+)
+
+type (
+	Config struct {
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		RedirectURL  string
+		Scopes       []string
+	}
+
+	// Session keeps the values of one authorization request until the callback.
+	Session struct {
+		State    string    `json:"state"`
+		Nonce    string    `json:"nonce"`
+		Verifier string    `json:"verifier"`
+		Expires  time.Time `json:"expires"`
+	}
+
+	Identity struct {
+		Subject string
+		Email   string
+		Name    string
+	}
+
+	// Provider is an OpenID Connect relying party using the authorization code flow with PKCE.
+	Provider struct {
+		oauth2   oauth2.Config
+		verifier *gooidc.IDTokenVerifier
+	}
+)
+
+// New discovers the issuer endpoints and signing keys.
+func New(ctx context.Context, config Config) (*Provider, error) {
+	provider, err := gooidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{gooidc.ScopeOpenID, "email", "profile"}
+	}
+
+	return &Provider{
+		oauth2: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			RedirectURL:  config.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier: provider.Verifier(&gooidc.Config{ClientID: config.ClientID}),
+	}, nil
+}
+
+func random() (string, error) {
+	data := make([]byte, 24)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// AuthURL returns the identity provider login URL and the session to be kept until the callback.
+func (s *Provider) AuthURL() (string, *Session, error) {
+	state, err := random()
+	if err != nil {
+		return "", nil, err
+	}
+
+	nonce, err := random()
+	if err != nil {
+		return "", nil, err
+	}
+
+	session := &Session{
+		State:    state,
+		Nonce:    nonce,
+		Verifier: oauth2.GenerateVerifier(),
+		Expires:  time.Now().Add(sessionLife),
+	}
+
+	return s.oauth2.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(session.Verifier)), session, nil
+}
+
+// Exchange trades the authorization code for the validated identity of the user.
+func (s *Provider) Exchange(ctx context.Context, session *Session, state, code string) (*Identity, error) {
+	if session == nil || session.State == "" || session.State != state || time.Now().After(session.Expires) {
+		return nil, ErrInvalidSession
+	}
+
+	token, err := s.oauth2.Exchange(ctx, code, oauth2.VerifierOption(session.Verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("token response without id_token")
+	}
+
+	idToken, err := s.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("could not verify id token: %v", err.Error())
+	}
+
+	if idToken.Nonce != session.Nonce {
+		return nil, ErrInvalidNonce
+	}
+
+	claims := struct {
+		Email         string `json:"email"`
+		EmailVerified *bool  `json:"email_verified"`
+		Name          string `json:"name"`
+	}{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, ErrMissingEmail
+	}
+
+	// The users are matched by email, so an email the provider did not verify, or whose verification is unknown, is refused.
+	if claims.EmailVerified == nil || !*claims.EmailVerified {
+		return nil, ErrUnverifiedEmail
+	}
+
+	return &Identity{
+		Subject: idToken.Subject,
+		Email:   claims.Email,
+		Name:    claims.Name,
+	}, nil
+}
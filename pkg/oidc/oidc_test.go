@@ -0,0 +1,204 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/raulaguila/go-pass/pkg/keyring"
+	"github.com/stretchr/testify/assert"
+)
+
+const testClientID string = "go-pass"
+
+// mockIdP is a minimal identity provider issuing id tokens for the registered codes.
+type mockIdP struct {
+	server *httptest.Server
+	keys   *keyring.Keyring
+	mu     sync.Mutex
+	codes  map[string]mockGrant
+}
+
+type mockGrant struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	assert.Nil(t, err)
+	keys, err := keyring.FromBase64(base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	assert.Nil(t, err)
+
+	idp := &mockIdP{keys: keys, codes: map[string]mockGrant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                idp.server.URL,
+			"authorization_endpoint":                idp.server.URL + "/authorize",
+			"token_endpoint":                        idp.server.URL + "/token",
+			"jwks_uri":                              idp.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{keyring.AlgRS256},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(idp.keys.JWKS())
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		grant, ok := idp.codes[r.FormValue("code")]
+		delete(idp.codes, r.FormValue("code"))
+		idp.mu.Unlock()
+
+		sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		idToken, _ := idp.keys.Sign(grant.claims)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+// authorize simulates the user login, returning the code sent to the callback.
+func (s *mockIdP) authorize(t *testing.T, authURL string, claims jwt.MapClaims) string {
+	parsed, err := url.Parse(authURL)
+	assert.Nil(t, err)
+	query := parsed.Query()
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	now := time.Now()
+	grant := mockGrant{challenge: query.Get("code_challenge"), claims: jwt.MapClaims{
+		"iss":   s.server.URL,
+		"aud":   testClientID,
+		"sub":   "user-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": query.Get("nonce"),
+	}}
+	for key, value := range claims {
+		grant.claims[key] = value
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.codes["code-"+query.Get("state")] = grant
+	return "code-" + query.Get("state")
+}
+
+func newTestProvider(t *testing.T, idp *mockIdP) *Provider {
+	provider, err := New(context.Background(), Config{
+		Issuer:      idp.server.URL,
+		ClientID:    testClientID,
+		RedirectURL: "http://127.0.0.1:9000/auth/oidc/callback",
+	})
+	assert.Nil(t, err)
+	return provider
+}
+
+// go test -run TestExchange
+func TestExchange(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestProvider(t, idp)
+
+	authURL, session, err := provider.AuthURL()
+	assert.Nil(t, err)
+	code := idp.authorize(t, authURL, jwt.MapClaims{"email": "john@email.com", "email_verified": true, "name": "John Doe"})
+
+	identity, err := provider.Exchange(context.Background(), session, session.State, code)
+	assert.Nil(t, err)
+	assert.Equal(t, &Identity{Subject: "user-1", Email: "john@email.com", Name: "John Doe"}, identity)
+}
+
+// go test -run TestExchangeInvalidSession
+func TestExchangeInvalidSession(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestProvider(t, idp)
+
+	authURL, session, err := provider.AuthURL()
+	assert.Nil(t, err)
+	code := idp.authorize(t, authURL, jwt.MapClaims{"email": "john@email.com"})
+
+	_, err = provider.Exchange(context.Background(), session, "other state", code)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+
+	_, err = provider.Exchange(context.Background(), nil, session.State, code)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+
+	session.Expires = time.Now().Add(-time.Second)
+	_, err = provider.Exchange(context.Background(), session, session.State, code)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+}
+
+// go test -run TestExchangeInvalidVerifier
+func TestExchangeInvalidVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestProvider(t, idp)
+
+	authURL, session, err := provider.AuthURL()
+	assert.Nil(t, err)
+	code := idp.authorize(t, authURL, jwt.MapClaims{"email": "john@email.com"})
+
+	session.Verifier = "stolen code without the verifier"
+	_, err = provider.Exchange(context.Background(), session, session.State, code)
+	assert.NotNil(t, err)
+}
+
+// go test -run TestExchangeInvalidClaims
+func TestExchangeInvalidClaims(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestProvider(t, idp)
+
+	for expected, claims := range map[error]jwt.MapClaims{
+		ErrMissingEmail:    {},
+		ErrUnverifiedEmail: {"email": "john@email.com", "email_verified": false},
+		ErrInvalidNonce:    {"email": "john@email.com", "nonce": "replayed"},
+	} {
+		authURL, session, err := provider.AuthURL()
+		assert.Nil(t, err)
+		code := idp.authorize(t, authURL, claims)
+
+		_, err = provider.Exchange(context.Background(), session, session.State, code)
+		assert.ErrorIs(t, err, expected)
+	}
+}
+
+// go test -run TestExchangeMissingVerification
+func TestExchangeMissingVerification(t *testing.T) {
+	idp := newMockIdP(t)
+	provider := newTestProvider(t, idp)
+
+	authURL, session, err := provider.AuthURL()
+	assert.Nil(t, err)
+	code := idp.authorize(t, authURL, jwt.MapClaims{"email": "john@email.com", "name": "John Doe"})
+
+	_, err = provider.Exchange(context.Background(), session, session.State, code)
+	assert.ErrorIs(t, err, ErrUnverifiedEmail)
+}
@@ -0,0 +1,73 @@
+package passgen
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+const (
+	lower   string = "abcdefghijkmnopqrstuvwxyz"
+	upper   string = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	digits  string = "23456789"
+	symbols string = "!#$%&*+-=?@^_~"
+
+	// MinLength fits one character of each class.
+	MinLength int = 4
+	// DefaultLength is used by the clients when no length is given.
+	DefaultLength int = 20
+)
+
+var ErrTooShort error = errors.New("password length must be at least 4")
+
+func pick(set string) (byte, error) {
+	index, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, err
+	}
+
+	return set[index.Int64()], nil
+}
+
+// Generate creates a random password with at least one character of each class, the symbols class is optional.
+// The characters easily confused, like 'l', 'I', 'O', '0' and '1', are left out.
+func Generate(length int, withSymbols bool) (string, error) {
+	if length < MinLength {
+		return "", ErrTooShort
+	}
+
+	classes := []string{lower, upper, digits}
+	if withSymbols {
+		classes = append(classes, symbols)
+	}
+
+	all := ""
+	password := make([]byte, 0, length)
+	for _, class := range classes {
+		char, err := pick(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, char)
+		all += class
+	}
+
+	for len(password) < length {
+		char, err := pick(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, char)
+	}
+
+	// Shuffles the leading characters of each class through the password.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
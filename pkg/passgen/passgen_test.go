@@ -0,0 +1,41 @@
+package passgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestGenerate
+func TestGenerate(t *testing.T) {
+	for _, length := range []int{MinLength, DefaultLength, 64} {
+		for _, withSymbols := range []bool{false, true} {
+			password, err := Generate(length, withSymbols)
+			assert.Nil(t, err)
+			assert.Len(t, password, length)
+
+			assert.True(t, strings.ContainsAny(password, lower))
+			assert.True(t, strings.ContainsAny(password, upper))
+			assert.True(t, strings.ContainsAny(password, digits))
+			assert.Equal(t, withSymbols, strings.ContainsAny(password, symbols), password)
+		}
+	}
+}
+
+// go test -run TestGenerateUnique
+func TestGenerateUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		password, err := Generate(DefaultLength, true)
+		assert.Nil(t, err)
+		assert.False(t, seen[password])
+		seen[password] = true
+	}
+}
+
+// go test -run TestGenerateTooShort
+func TestGenerateTooShort(t *testing.T) {
+	_, err := Generate(MinLength-1, true)
+	assert.Equal(t, ErrTooShort, err)
+}
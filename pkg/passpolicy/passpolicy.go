@@ -0,0 +1,116 @@
+package passpolicy
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed common.txt
+var commonList string
+
+var common = func() map[string]struct{} {
+	words := map[string]struct{}{}
+	for _, word := range strings.Fields(commonList) {
+		words[strings.ToLower(word)] = struct{}{}
+	}
+	return words
+}()
+
+// Violation codes, the value of the violation is the limit that was not respected.
+const (
+	CodeTooShort = "too_short"
+	CodeTooLong  = "too_long"
+	CodeClasses  = "classes"
+	CodeCommon   = "common"
+)
+
+type (
+	Policy struct {
+		MinLength  int
+		MaxLength  int
+		MinClasses int
+		History    int
+		Common     bool
+	}
+
+	Violation struct {
+		Code  string
+		Value int
+	}
+
+	Error struct {
+		Violations []Violation
+	}
+)
+
+func (e *Error) Error() string {
+	codes := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		codes[i] = violation.Code
+	}
+
+	return "password does not meet the policy: " + strings.Join(codes, ", ")
+}
+
+// Default returns the policy used when nothing is configured.
+func Default() *Policy {
+	return &Policy{
+		MinLength:  10,
+		MaxLength:  128,
+		MinClasses: 1,
+		History:    5,
+		Common:     true,
+	}
+}
+
+// Classes counts the character classes used by the password: lowercase, uppercase, digits and symbols.
+func Classes(password string) int {
+	var lower, upper, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+
+	return lower + upper + digit + other
+}
+
+// IsCommon reports whether the password is in the embedded list of breached and common passwords.
+func IsCommon(password string) bool {
+	_, ok := common[strings.ToLower(password)]
+	return ok
+}
+
+// Validate returns an *Error with every rule the password breaks, or nil.
+func (p *Policy) Validate(password string) error {
+	violations := []Violation{}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violations = append(violations, Violation{Code: CodeTooShort, Value: p.MinLength})
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, Violation{Code: CodeTooLong, Value: p.MaxLength})
+	}
+	if Classes(password) < p.MinClasses {
+		violations = append(violations, Violation{Code: CodeClasses, Value: p.MinClasses})
+	}
+	if p.Common && IsCommon(password) {
+		violations = append(violations, Violation{Code: CodeCommon})
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &Error{Violations: violations}
+}
@@ -0,0 +1,54 @@
+package passpolicy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestValidateValidPassword
+func TestValidateValidPassword(t *testing.T) {
+	policy := &Policy{MinLength: 10, MaxLength: 20, MinClasses: 3, Common: true}
+
+	assert.Nil(t, policy.Validate("Correct-horse7"))
+	assert.Nil(t, policy.Validate("çãoÉÍ-1234"))
+}
+
+// go test -run TestValidateViolations
+func TestValidateViolations(t *testing.T) {
+	policy := &Policy{MinLength: 10, MaxLength: 12, MinClasses: 3, Common: true}
+
+	tests := map[string][]Violation{
+		"short":           {{Code: CodeTooShort, Value: 10}, {Code: CodeClasses, Value: 3}},
+		"Long-password-1": {{Code: CodeTooLong, Value: 12}},
+		"lowercaseonly":   {{Code: CodeTooLong, Value: 12}, {Code: CodeClasses, Value: 3}},
+		"12345678":        {{Code: CodeTooShort, Value: 10}, {Code: CodeClasses, Value: 3}, {Code: CodeCommon}},
+	}
+
+	for password, expected := range tests {
+		err := policy.Validate(password)
+
+		policyErr := &Error{}
+		assert.True(t, errors.As(err, &policyErr), password)
+		assert.Equal(t, expected, policyErr.Violations, password)
+	}
+}
+
+// go test -run TestValidateCommon
+func TestValidateCommon(t *testing.T) {
+	policy := &Policy{Common: true}
+
+	assert.True(t, IsCommon("PassWord123"))
+	assert.NotNil(t, policy.Validate("Password123"))
+	assert.Nil(t, (&Policy{}).Validate("Password123"))
+}
+
+// go test -run TestClasses
+func TestClasses(t *testing.T) {
+	assert.Equal(t, 0, Classes(""))
+	assert.Equal(t, 1, Classes("abc"))
+	assert.Equal(t, 2, Classes("abcD"))
+	assert.Equal(t, 3, Classes("abcD1"))
+	assert.Equal(t, 4, Classes("abcD1 "))
+}
@@ -2,7 +2,7 @@ package pgerror
 
 import (
 	"errors"
-	"log"
+	"log/slog"
 
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -14,11 +14,23 @@ var (
 	ErrDatabaseAlreadyExists = errors.New("database already exists")
 )
 
+// HandlerError maps the PostgreSQL and SQLite errors to the errors of the package, the other errors are returned unchanged.
+// Errors wrapping the ones of the package, like the errors of repositories without database, are also mapped to them.
 func HandlerError(err error) error {
 	if err == nil {
 		return nil
 	}
 
+	for _, known := range []error{ErrDuplicatedKey, ErrForeignKeyViolated, ErrUndefinedColumn, ErrDatabaseAlreadyExists} {
+		if errors.Is(err, known) {
+			return known
+		}
+	}
+
+	if mapped, ok := sqliteError(err); ok {
+		return mapped
+	}
+
 	var pgError *pgconn.PgError
 	if errors.As(err, &pgError) {
 		switch pgError.Code {
@@ -31,7 +43,7 @@ func HandlerError(err error) error {
 		case "42P04":
 			return ErrDatabaseAlreadyExists
 		default:
-			log.Printf("PostgreSQL error not detected: %v\n", err)
+			slog.Warn("PostgreSQL error not detected", "code", pgError.Code, "error", err)
 		}
 	}
 
@@ -0,0 +1,61 @@
+package pgerror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	_ "github.com/glebarez/go-sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestHandlerErrorPostgres
+func TestHandlerErrorPostgres(t *testing.T) {
+	tests := map[string]error{
+		"23505": ErrDuplicatedKey,
+		"23503": ErrForeignKeyViolated,
+		"42703": ErrUndefinedColumn,
+		"42P04": ErrDatabaseAlreadyExists,
+	}
+
+	for code, expected := range tests {
+		assert.Equal(t, expected, HandlerError(&pgconn.PgError{Code: code}), code)
+	}
+
+	wrapped := fmt.Errorf("%w: fk_account_site", ErrForeignKeyViolated)
+	assert.Equal(t, ErrForeignKeyViolated, HandlerError(wrapped))
+
+	other := &pgconn.PgError{Code: "40001"}
+	assert.Equal(t, error(other), HandlerError(other))
+	assert.Nil(t, HandlerError(nil))
+}
+
+// go test -run TestHandlerErrorSQLite
+func TestHandlerErrorSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:?_pragma=foreign_keys(1)")
+	assert.Nil(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE parent (id integer PRIMARY KEY, name text UNIQUE); CREATE TABLE child (id integer PRIMARY KEY, parent_id integer REFERENCES parent (id));")
+	assert.Nil(t, err)
+	_, err = db.Exec("INSERT INTO parent (id, name) VALUES (1, 'name')")
+	assert.Nil(t, err)
+
+	_, err = db.Exec("INSERT INTO parent (id, name) VALUES (2, 'name')")
+	assert.Equal(t, ErrDuplicatedKey, HandlerError(err))
+
+	_, err = db.Exec("INSERT INTO parent (id, name) VALUES (1, 'other')")
+	assert.Equal(t, ErrDuplicatedKey, HandlerError(err))
+
+	_, err = db.Exec("INSERT INTO child (parent_id) VALUES (2)")
+	assert.Equal(t, ErrForeignKeyViolated, HandlerError(err))
+
+	_, err = db.Exec("SELECT missing FROM parent")
+	assert.Equal(t, ErrUndefinedColumn, HandlerError(err))
+
+	_, err = db.Exec("SELECT * FROM missing")
+	assert.False(t, errors.Is(HandlerError(err), ErrUndefinedColumn))
+}
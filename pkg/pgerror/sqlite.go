@@ -0,0 +1,30 @@
+package pgerror
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/glebarez/go-sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteError maps the SQLite errors, which only have a generic code for the undefined columns.
+func sqliteError(err error) (error, bool) {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return nil, false
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return ErrDuplicatedKey, true
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return ErrForeignKeyViolated, true
+	}
+
+	if strings.Contains(sqliteErr.Error(), "no such column") {
+		return ErrUndefinedColumn, true
+	}
+
+	return err, true
+}
@@ -0,0 +1,54 @@
+package secretref
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+const (
+	FormatJSON   string = "json"
+	FormatDotenv string = "dotenv"
+	FormatShell  string = "shell"
+)
+
+var ErrInvalidFormat error = errors.New("invalid format, expected json, dotenv or shell")
+
+// dotenvEscaper escapes the characters interpreted inside the double quotes of the dotenv files.
+var dotenvEscaper *strings.Replacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`)
+
+type Secret struct {
+	Name  string `json:"name"`
+	Ref   string `json:"ref"`
+	Value string `json:"value"`
+}
+
+func ContentType(format string) string {
+	if format == FormatJSON {
+		return "application/json"
+	}
+
+	return "text/plain; charset=utf-8"
+}
+
+// Encode renders the secrets in the format.
+func Encode(format string, secrets []Secret) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.Marshal(map[string][]Secret{"secrets": secrets})
+	case FormatDotenv:
+		var builder strings.Builder
+		for _, secret := range secrets {
+			builder.WriteString(ToEnvName(secret.Name) + "=" + `"` + dotenvEscaper.Replace(secret.Value) + `"` + "\n")
+		}
+		return []byte(builder.String()), nil
+	case FormatShell:
+		var builder strings.Builder
+		for _, secret := range secrets {
+			builder.WriteString("export " + ToEnvName(secret.Name) + "='" + strings.ReplaceAll(secret.Value, "'", `'\''`) + "'\n")
+		}
+		return []byte(builder.String()), nil
+	}
+
+	return nil, ErrInvalidFormat
+}
@@ -0,0 +1,80 @@
+package secretref
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+const (
+	Scheme string = "gopass"
+
+	FieldPassword string = "password"
+	FieldUsername string = "username"
+	FieldURL      string = "url"
+)
+
+var ErrInvalidReference error = errors.New("invalid secret reference, expected gopass://site/<site>/account/<username>#<field>")
+
+// Reference points to a field of an account, like gopass://site/github/account/deploy-bot#password.
+type Reference struct {
+	Site    string
+	Account string
+	Field   string
+}
+
+func Parse(raw string) (*Reference, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || parsed.Scheme != Scheme || parsed.Host != "site" {
+		return nil, ErrInvalidReference
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) != 3 || parts[1] != "account" || parts[0] == "" || parts[2] == "" {
+		return nil, ErrInvalidReference
+	}
+
+	ref := &Reference{Site: parts[0], Account: parts[2], Field: strings.ToLower(parsed.Fragment)}
+	switch ref.Field {
+	case "":
+		ref.Field = FieldPassword
+	case FieldPassword, FieldUsername, FieldURL:
+	default:
+		return nil, ErrInvalidReference
+	}
+
+	return ref, nil
+}
+
+func (s Reference) String() string {
+	return (&url.URL{
+		Scheme:   Scheme,
+		Host:     "site",
+		Path:     "/" + s.Site + "/account/" + s.Account,
+		Fragment: s.Field,
+	}).String()
+}
+
+// EnvName derives a variable name from the reference, like GITHUB_DEPLOY_BOT_PASSWORD.
+func (s Reference) EnvName() string {
+	return ToEnvName(s.Site + "_" + s.Account + "_" + s.Field)
+}
+
+func ToEnvName(name string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+
+	env := builder.String()
+	if env != "" && env[0] >= '0' && env[0] <= '9' {
+		env = "_" + env
+	}
+
+	return env
+}
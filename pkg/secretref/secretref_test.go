@@ -0,0 +1,68 @@
+package secretref
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -run TestParseValidReferences
+func TestParseValidReferences(t *testing.T) {
+	ref, err := Parse("gopass://site/github/account/deploy-bot#password")
+	assert.Nil(t, err)
+	assert.Equal(t, &Reference{Site: "github", Account: "deploy-bot", Field: FieldPassword}, ref)
+	assert.Equal(t, "GITHUB_DEPLOY_BOT_PASSWORD", ref.EnvName())
+
+	ref, err = Parse("gopass://site/my%20site/account/user@email.com")
+	assert.Nil(t, err)
+	assert.Equal(t, &Reference{Site: "my site", Account: "user@email.com", Field: FieldPassword}, ref)
+
+	ref, err = Parse("gopass://site/github/account/bot#USERNAME")
+	assert.Nil(t, err)
+	assert.Equal(t, FieldUsername, ref.Field)
+
+	parsed, err := Parse(ref.String())
+	assert.Nil(t, err)
+	assert.Equal(t, ref, parsed)
+}
+
+// go test -run TestParseInvalidReferences
+func TestParseInvalidReferences(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"github/deploy-bot",
+		"https://site/github/account/bot",
+		"gopass://vault/github/account/bot",
+		"gopass://site/github/bot",
+		"gopass://site/github/account/",
+		"gopass://site//account/bot",
+		"gopass://site/github/account/bot#token",
+	} {
+		_, err := Parse(raw)
+		assert.ErrorIs(t, err, ErrInvalidReference, raw)
+	}
+}
+
+// go test -run TestEncode
+func TestEncode(t *testing.T) {
+	secrets := []Secret{
+		{Name: "db-pass", Ref: "gopass://site/db/account/app", Value: `it's "secret"`},
+		{Name: "1TOKEN", Ref: "gopass://site/ci/account/bot", Value: "line\nbreak"},
+		{Name: "path", Ref: "gopass://site/ci/account/path", Value: `C:\$HOME\ação`},
+	}
+
+	data, err := Encode(FormatDotenv, secrets)
+	assert.Nil(t, err)
+	assert.Equal(t, "DB_PASS=\"it's \\\"secret\\\"\"\n_1TOKEN=\"line\\nbreak\"\nPATH=\"C:\\\\\\$HOME\\\\ação\"\n", string(data))
+
+	data, err = Encode(FormatShell, secrets[:2])
+	assert.Nil(t, err)
+	assert.Equal(t, "export DB_PASS='it'\\''s \"secret\"'\nexport _1TOKEN='line\nbreak'\n", string(data))
+
+	data, err = Encode(FormatJSON, secrets[:1])
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"secrets":[{"name":"db-pass","ref":"gopass://site/db/account/app","value":"it's \"secret\""}]}`, string(data))
+
+	_, err = Encode("yaml", secrets)
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
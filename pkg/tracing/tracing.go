@@ -0,0 +1,107 @@
+// Package tracing configures the OpenTelemetry tracer provider, exporting the spans with OTLP or to the standard output.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	ExporterNone   string = "none"
+	ExporterOTLP   string = "otlp"
+	ExporterStdout string = "stdout"
+)
+
+var ErrInvalidExporter = errors.New("invalid tracing exporter, use none, otlp or stdout")
+
+type Options struct {
+	// Exporter is none, otlp or stdout.
+	Exporter string
+	// Endpoint of the OTLP collector, like localhost:4318, the OTEL_EXPORTER_OTLP_* variables apply when empty.
+	Endpoint string
+	// Insecure sends the spans to the collector without TLS.
+	Insecure bool
+	// Output of the stdout exporter, the standard output when nil.
+	Output io.Writer
+
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Provider exports the spans until its shutdown.
+type Provider struct {
+	provider *sdktrace.TracerProvider
+}
+
+// New creates the provider of the exporter and sets it as the global one, with the W3C trace context and baggage
+// propagators. It returns nil with the none exporter, leaving the global no-op provider.
+func New(ctx context.Context, opts Options) (*Provider, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch strings.ToLower(opts.Exporter) {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterOTLP:
+		options := []otlptracehttp.Option{}
+		if opts.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	case ExporterStdout:
+		output := opts.Output
+		if output == nil {
+			output = os.Stdout
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(output), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, ErrInvalidExporter
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES variables override the service attributes.
+	res, err := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName(opts.ServiceName), semconv.ServiceVersion(opts.ServiceVersion)),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return &Provider{provider: provider}, nil
+}
+
+// Shutdown exports the pending spans and stops the provider.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+
+	return p.provider.Shutdown(ctx)
+}
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+// go test -run TestNew
+func TestNew(t *testing.T) {
+	provider, err := New(context.Background(), Options{Exporter: ExporterNone})
+	assert.Nil(t, err)
+	assert.Nil(t, provider)
+	assert.Nil(t, provider.Shutdown(context.Background()))
+
+	_, err = New(context.Background(), Options{Exporter: "jaeger"})
+	assert.Equal(t, ErrInvalidExporter, err)
+
+	output := &bytes.Buffer{}
+	provider, err = New(context.Background(), Options{Exporter: ExporterStdout, Output: output, ServiceName: "go-pass", ServiceVersion: "1.0.0"})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	assert.Nil(t, provider.Shutdown(context.Background()))
+	assert.Contains(t, output.String(), `"Name": "operation"`)
+	assert.Contains(t, output.String(), `"Value": "go-pass"`)
+}
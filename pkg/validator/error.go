@@ -1,6 +1,10 @@
 package validator
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
 
 var ErrValidator *ValidatorError
 
@@ -9,8 +13,50 @@ type ValidatorError struct {
 	tag   string
 	param string
 	value interface{}
+	kind  reflect.Kind
 }
 
 func (m *ValidatorError) Error() string {
 	return fmt.Sprintf("%s does not meet the '%s[%s]' requirement with value '%v'", m.field, m.tag, m.param, m.value)
 }
+
+// Field returns the name of the invalid field.
+func (m *ValidatorError) Field() string {
+	return m.field
+}
+
+// Tag returns the broken rule, like min or email.
+func (m *ValidatorError) Tag() string {
+	return m.tag
+}
+
+// Param returns the parameter of the rule, like the 4 of min=4.
+func (m *ValidatorError) Param() string {
+	return m.param
+}
+
+// Translate returns the message of the violation in the language, or Error when it has no translation.
+func (m *ValidatorError) Translate(lang string) string {
+	return StructValidator.translate(lang, m)
+}
+
+// ValidationErrors are every violation of a struct, errors.As also finds the first ValidatorError in them.
+type ValidationErrors []*ValidatorError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	ut "github.com/go-playground/universal-translator"
+)
+
+// DefaultRule is the key of the message used by the rules without their own.
+const DefaultRule string = "default"
+
+// Suffixes of the keys of the messages specific to the kind of the field, like min_string for the length of the texts.
+const (
+	suffixString string = "_string"
+	suffixItems  string = "_items"
+)
+
+// Locales of the plural rules of the languages, the others use the english rules.
+var pluralRules map[string]func() locales.Translator = map[string]func() locales.Translator{
+	"en":    en.New,
+	"pt":    pt.New,
+	"pt-br": pt_BR.New,
+}
+
+// RegisterTranslation registers the messages of the rules in the language. The messages are keyed by the tag of the
+// rule, like min, optionally suffixed by _string or _items for the texts and the lists, and DefaultRule for the rules
+// without a message. {0} is replaced by the field and {1} by the parameter of the rule.
+func (v *validatorStruct) RegisterTranslation(lang string, messages map[string]string) error {
+	locale, ok := pluralRules[strings.ToLower(lang)]
+	if !ok {
+		locale = en.New
+	}
+
+	translator := ut.New(locale()).GetFallback()
+	for key, message := range messages {
+		if err := translator.Add(key, message, true); err != nil {
+			return err
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.translations[lang] = translator
+	return nil
+}
+
+func (v *validatorStruct) translate(lang string, err *ValidatorError) string {
+	v.mu.RLock()
+	translator, ok := v.translations[lang]
+	v.mu.RUnlock()
+	if !ok {
+		return err.Error()
+	}
+
+	keys := []string{err.tag, DefaultRule}
+	switch err.kind {
+	case reflect.String:
+		keys = append([]string{err.tag + suffixString}, keys...)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		keys = append([]string{err.tag + suffixItems}, keys...)
+	}
+
+	for _, key := range keys {
+		if message, e := translator.T(key, err.field, err.param); e == nil {
+			return message
+		}
+	}
+
+	return err.Error()
+}
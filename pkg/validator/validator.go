@@ -1,27 +1,74 @@
 package validator
 
 import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 )
 
 type validatorStruct struct {
 	validator *validator.Validate
+
+	mu           sync.RWMutex
+	translations map[string]ut.Translator
 }
 
 func init() {
 	StructValidator = &validatorStruct{
-		validator: validator.New(),
+		validator:    validator.New(),
+		translations: map[string]ut.Translator{},
 	}
+	StructValidator.validator.RegisterTagNameFunc(fieldName)
 }
 
 var StructValidator *validatorStruct
 
-func (v validatorStruct) Validate(data interface{}) error {
-	if errs := v.validator.Struct(data); errs != nil {
-		for _, err := range errs.(validator.ValidationErrors) {
-			return &ValidatorError{err.Field(), err.Tag(), err.Param(), err.Value()}
+// fieldName names the fields by their json tag, the fields hidden from json by the snake case of their name, like
+// profile_id for ProfileID, which is the name of the inputs that fill them.
+func fieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name != "" && name != "-" {
+		return name
+	}
+
+	return snakeCase(field.Name)
+}
+
+func snakeCase(name string) string {
+	snake := strings.Builder{}
+	previous := rune(0)
+	for _, r := range name {
+		if unicode.IsUpper(r) {
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) {
+				snake.WriteByte('_')
+			}
+			previous, r = r, unicode.ToLower(r)
+		} else {
+			previous = r
 		}
+		snake.WriteRune(r)
+	}
+
+	return snake.String()
+}
+
+// Validate returns the ValidationErrors with every violation of the struct, or nil when valid.
+func (v *validatorStruct) Validate(data interface{}) error {
+	err := v.validator.Struct(data)
+	errs := validator.ValidationErrors{}
+	if !errors.As(err, &errs) {
+		return err
+	}
+
+	violations := make(ValidationErrors, len(errs))
+	for i, err := range errs {
+		violations[i] = &ValidatorError{err.Field(), err.Tag(), err.Param(), err.Value(), err.Kind()}
 	}
 
-	return nil
+	return violations
 }
@@ -7,9 +7,10 @@ import (
 )
 
 type structTest struct {
-	Name  string `validate:"required,min=5,max=10"`
-	Age   int    `validate:"required,min=12,max=18"`
-	Email string `validate:"required,email"`
+	Name    string `json:"name" validate:"required,min=5,max=10"`
+	Age     int    `json:"-" validate:"required,min=12,max=18"`
+	Email   string `validate:"required,email"`
+	GroupID uint   `json:"-" validate:"required"`
 }
 
 // go test -run TestValidatorWithoutDatas
@@ -23,7 +24,7 @@ func TestValidatorWithoutDatas(t *testing.T) {
 
 // go test -run TestValidatorWitInvalidDatas
 func TestValidatorWitInvalidDatas(t *testing.T) {
-	element := &structTest{"1234", 22, "toError@email"}
+	element := &structTest{"1234", 22, "toError@email", 1}
 
 	err := StructValidator.Validate(element)
 	assert.NotNil(t, err)
@@ -41,7 +42,7 @@ func TestValidatorWitInvalidDatas(t *testing.T) {
 
 // go test -run TestValidatorWitValidDatas
 func TestValidatorWitValidDatas(t *testing.T) {
-	element := &structTest{"123456", 15, "example@example.com"}
+	element := &structTest{"123456", 15, "example@example.com", 1}
 
 	err := StructValidator.Validate(element)
 	assert.Nil(t, err)
@@ -53,3 +54,53 @@ func TestValidatorWitValidDatas(t *testing.T) {
 	err = StructValidator.Validate(element)
 	assert.Nil(t, err)
 }
+
+// go test -run TestValidatorEveryViolation
+func TestValidatorEveryViolation(t *testing.T) {
+	element := &structTest{"1234", 22, "toError@email", 1}
+
+	err := StructValidator.Validate(element)
+	violations := ValidationErrors{}
+	if !assert.ErrorAs(t, err, &violations) {
+		t.FailNow()
+	}
+
+	assert.Len(t, violations, 3)
+	assert.Equal(t, "name", violations[0].Field())
+	assert.Equal(t, "min", violations[0].Tag())
+	assert.Equal(t, "5", violations[0].Param())
+	assert.Equal(t, "age", violations[1].Field())
+	assert.Equal(t, "email", violations[2].Field())
+	assert.Equal(t, "email", violations[2].Tag())
+}
+
+// go test -run TestValidatorFieldNames
+func TestValidatorFieldNames(t *testing.T) {
+	err := StructValidator.Validate(&structTest{Name: "123456", Age: 15, Email: "example@example.com"})
+	violations := ValidationErrors{}
+	if assert.ErrorAs(t, err, &violations) && assert.Len(t, violations, 1) {
+		assert.Equal(t, "group_id", violations[0].Field())
+	}
+}
+
+// go test -run TestValidatorTranslation
+func TestValidatorTranslation(t *testing.T) {
+	assert.Nil(t, StructValidator.RegisterTranslation("test-pt", map[string]string{
+		"min":        "{0} deve ser no mínimo {1}.",
+		"min_string": "{0} deve ter no mínimo {1} caracteres.",
+		DefaultRule:  "{0} é inválido.",
+	}))
+
+	err := StructValidator.Validate(&structTest{"1234", 22, "toError@email", 1})
+	violations := ValidationErrors{}
+	if !assert.ErrorAs(t, err, &violations) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "name deve ter no mínimo 5 caracteres.", violations[0].Translate("test-pt"))
+	assert.Equal(t, "age é inválido.", violations[1].Translate("test-pt"))
+	assert.Equal(t, "email é inválido.", violations[2].Translate("test-pt"))
+
+	// The languages without translations use the message of the error.
+	assert.Equal(t, violations[0].Error(), violations[0].Translate("unknown"))
+}